@@ -0,0 +1,14 @@
+//go:build gojson
+
+package main
+
+import (
+	"lambda-invoker/internal/clients/lambda"
+)
+
+func init() {
+	engines = append(engines, struct {
+		name   string
+		engine lambda.JSONEngine
+	}{"go-json", lambda.GoJSON{}})
+}
@@ -0,0 +1,220 @@
+// Command bench-codec compares the client-side cost of envelopes and JSON engines on representative payloads.
+// Lambda is replaced by an in-process HTTP stub, so the numbers cover the SDK and the invoker only.
+//
+//	go run ./cmd/bench-codec -test.benchtime 2s
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awslambda "github.com/aws/aws-sdk-go-v2/service/lambda"
+	"io"
+	"lambda-invoker/internal/clients/lambda"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"text/tabwriter"
+)
+
+const functionARN = "arn:aws:lambda:eu-central-1:000000000000:function:bench"
+
+var envelopes = []struct {
+	name     string
+	envelope lambda.Envelope
+}{
+	{"proxy", lambda.EnvelopeProxy},
+	{"raw", lambda.EnvelopeRaw},
+}
+
+// engines are the JSON engines to compare, add an injected engine here to benchmark it.
+var engines = []struct {
+	name   string
+	engine lambda.JSONEngine
+}{
+	{"std", lambda.StdJSON{}},
+	{"std-pooled", &pooledJSON{}},
+}
+
+func main() {
+	testing.Init()
+	flag.Parse()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "envelope\tengine\tpayload\tns/op\tMB/s\tB/op\tallocs/op\t")
+
+	for _, p := range payloads() {
+		for _, e := range envelopes {
+			for _, j := range engines {
+				// the raw envelope does not marshal JSON
+				if e.envelope == lambda.EnvelopeRaw && j.name != engines[0].name {
+					continue
+				}
+
+				r, err := bench(e.envelope, j.engine, p.body)
+				if err != nil {
+					log.Fatalf("bench %s/%s/%s: %v", e.name, j.name, p.name, err)
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%.1f\t%d\t%d\t\n",
+					e.name, j.name, p.name, r.NsPerOp(), mbPerSec(r), r.AllocedBytesPerOp(), r.AllocsPerOp())
+			}
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func bench(envelope lambda.Envelope, engine lambda.JSONEngine, body []byte) (testing.BenchmarkResult, error) {
+	cli, err := newClient(envelope, engine, body)
+	if err != nil {
+		return testing.BenchmarkResult{}, fmt.Errorf("newClient: %w", err)
+	}
+
+	ctx := context.Background()
+
+	// fail fast instead of benchmarking errors
+	if _, err := cli.Invoke(ctx, http.MethodPost, "/items", body); err != nil {
+		return testing.BenchmarkResult{}, fmt.Errorf("cli.Invoke: %w", err)
+	}
+
+	r := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(body)))
+
+		for range b.N {
+			if _, err := cli.Invoke(ctx, http.MethodPost, "/items", body); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	return r, nil
+}
+
+func newClient(envelope lambda.Envelope, engine lambda.JSONEngine, body []byte) (lambda.Client, error) {
+	response := body
+	if envelope == lambda.EnvelopeProxy {
+		var err error
+		response, err = json.Marshal(events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: string(body)})
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %w", err)
+		}
+	}
+
+	cli := awslambda.New(awslambda.Options{
+		Region:       "eu-central-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String("http://lambda.bench"),
+		HTTPClient:   stubHTTPClient{response: response},
+	})
+
+	return lambda.New(cli, functionARN, lambda.WithEnvelope(envelope), lambda.WithJSONEngine(engine))
+}
+
+// stubHTTPClient responds to every Invoke API call with the same payload.
+type stubHTTPClient struct {
+	response []byte
+}
+
+func (s stubHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		if _, err := io.Copy(io.Discard, req.Body); err != nil {
+			return nil, fmt.Errorf("io.Copy: %w", err)
+		}
+	}
+
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(s.response)),
+		ContentLength: int64(len(s.response)),
+		Request:       req,
+	}, nil
+}
+
+type payload struct {
+	name string
+	body []byte
+}
+
+// payloads are JSON documents of typical API sizes.
+func payloads() []payload {
+	type item struct {
+		ID          int               `json:"id"`
+		Name        string            `json:"name"`
+		Description string            `json:"description"`
+		Tags        []string          `json:"tags"`
+		Attributes  map[string]string `json:"attributes"`
+	}
+
+	doc := func(n int) []byte {
+		items := make([]item, n)
+		for i := range items {
+			items[i] = item{
+				ID:          i,
+				Name:        fmt.Sprintf("item-%d", i),
+				Description: strings.Repeat("lorem ipsum <dolor> sit amet ", 4),
+				Tags:        []string{"a", "b", "c"},
+				Attributes:  map[string]string{"color": "red", "size": "xl"},
+			}
+		}
+
+		b, err := json.Marshal(items)
+		if err != nil {
+			panic(err)
+		}
+
+		return b
+	}
+
+	return []payload{
+		{"1KB", doc(4)},
+		{"64KB", doc(256)},
+		{"1MB", doc(4096)},
+	}
+}
+
+func mbPerSec(r testing.BenchmarkResult) float64 {
+	if r.T <= 0 {
+		return 0
+	}
+
+	return float64(r.Bytes) * float64(r.N) / 1e6 / r.T.Seconds()
+}
+
+// pooledJSON is encoding/json with pooled buffers and without HTML escaping.
+type pooledJSON struct {
+	pool sync.Pool
+}
+
+func (p *pooledJSON) Marshal(v any) ([]byte, error) {
+	buf, _ := p.pool.Get().(*bytes.Buffer)
+	if buf == nil {
+		buf = new(bytes.Buffer)
+	}
+	defer p.pool.Put(buf)
+	buf.Reset()
+
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	// Encode appends a newline
+	return bytes.Clone(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
+
+func (p *pooledJSON) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,33 @@
+// Package orders is an example of a service generated by lambda-gen, see orders_lambda.go.
+package orders
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate go run lambda-invoker/cmd/lambda-gen -type Orders
+
+type Orders interface {
+	// List returns all orders.
+	//
+	// lambda:route GET /orders
+	List(ctx context.Context) ([]Order, error)
+	Create(ctx context.Context, in CreateOrder) (*Order, error)
+	Cancel(ctx context.Context, in CancelOrder) error
+	Ping(ctx context.Context) error
+}
+
+type Order struct {
+	ID        string    `json:"id"`
+	Item      string    `json:"item"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateOrder struct {
+	Item string `json:"item"`
+}
+
+type CancelOrder struct {
+	ID string `json:"id"`
+}
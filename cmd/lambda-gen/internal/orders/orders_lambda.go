@@ -0,0 +1,53 @@
+// Code generated by lambda-gen; DO NOT EDIT.
+
+package orders
+
+import (
+	"context"
+	"lambda-invoker/internal/clients/lambda"
+)
+
+// OrdersClient implements Orders by invoking a function serving NewOrdersHandler.
+type OrdersClient struct {
+	client lambda.Client
+}
+
+var _ Orders = (*OrdersClient)(nil)
+
+// NewOrdersClient returns the Orders invoking the function of the client.
+func NewOrdersClient(client lambda.Client) *OrdersClient {
+	return &OrdersClient{client: client}
+}
+
+func (c *OrdersClient) List(ctx context.Context) ([]Order, error) {
+	return lambda.CallAs[[]Order](ctx, c.client, "GET", "/orders", nil)
+}
+
+func (c *OrdersClient) Create(ctx context.Context, in CreateOrder) (*Order, error) {
+	return lambda.CallAs[*Order](ctx, c.client, "POST", "/Create", in)
+}
+
+func (c *OrdersClient) Cancel(ctx context.Context, in CancelOrder) error {
+	return c.client.Call(ctx, "POST", "/Cancel", in, nil)
+}
+
+func (c *OrdersClient) Ping(ctx context.Context) error {
+	return c.client.Call(ctx, "POST", "/Ping", nil, nil)
+}
+
+// NewOrdersHandler routes requests of OrdersClient to impl, pass its Invoke to lambda.Start of aws-lambda-go.
+func NewOrdersHandler(impl Orders) *lambda.Mux {
+	mux := lambda.NewMux()
+	mux.Handle("GET /orders", lambda.RPCHandler(func(ctx context.Context, _ struct{}) ([]Order, error) {
+		return impl.List(ctx)
+	}))
+	mux.Handle("POST /Create", lambda.RPCHandler(impl.Create))
+	mux.Handle("POST /Cancel", lambda.RPCHandler(func(ctx context.Context, in CancelOrder) (struct{}, error) {
+		return struct{}{}, impl.Cancel(ctx, in)
+	}))
+	mux.Handle("POST /Ping", lambda.RPCHandler(func(ctx context.Context, _ struct{}) (struct{}, error) {
+		return struct{}{}, impl.Ping(ctx)
+	}))
+
+	return mux
+}
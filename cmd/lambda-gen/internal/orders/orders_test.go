@@ -0,0 +1,58 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lambda-invoker/internal/clients/lambda"
+	"testing"
+	"time"
+)
+
+type service struct {
+	orders []Order
+	pings  int
+}
+
+func (s *service) List(context.Context) ([]Order, error) {
+	return s.orders, nil
+}
+
+func (s *service) Create(_ context.Context, in CreateOrder) (*Order, error) {
+	order := Order{ID: "order-1", Item: in.Item, CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s.orders = append(s.orders, order)
+	return &order, nil
+}
+
+func (s *service) Cancel(_ context.Context, in CancelOrder) error {
+	return errors.New("cannot cancel " + in.ID)
+}
+
+func (s *service) Ping(context.Context) error {
+	s.pings++
+	return nil
+}
+
+func TestOrdersClient(t *testing.T) {
+	ctx := context.Background()
+	svc := &service{}
+	client := NewOrdersClient(lambda.NewInProcess(NewOrdersHandler(svc).Invoke))
+
+	order, err := client.Create(ctx, CreateOrder{Item: "book"})
+	require.NoError(t, err)
+	assert.Equal(t, "book", order.Item)
+
+	orders, err := client.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []Order{*order}, orders)
+
+	require.NoError(t, client.Ping(ctx))
+	assert.Equal(t, 1, svc.pings)
+
+	err = client.Cancel(ctx, CancelOrder{ID: order.ID})
+	var use *lambda.ErrUnexpectedStatus
+	require.ErrorAs(t, err, &use)
+	assert.Equal(t, 500, use.Code)
+	assert.JSONEq(t, `{"message":"cannot cancel order-1"}`, use.Body)
+}
@@ -0,0 +1,372 @@
+// Command lambda-gen generates a typed client of a Go interface backed by lambda.Client, and the function side
+// handler routing its requests to an implementation, for RPC-like calls of functions:
+//
+//	//go:generate go run lambda-invoker/cmd/lambda-gen -type Orders
+//	type Orders interface {
+//		// lambda:route GET /orders
+//		List(ctx context.Context) ([]Order, error)
+//		Create(ctx context.Context, in CreateOrder) (*Order, error)
+//		Cancel(ctx context.Context, in CancelOrder) error
+//	}
+//
+// Methods take a context.Context and optionally a request, they return an error and optionally a response.
+// Both are JSON encoded. Each method is routed as "POST /<Method>" unless annotated with "lambda:route METHOD /path".
+// The generated file declares OrdersClient, NewOrdersClient and NewOrdersHandler.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+type options struct {
+	typeName string
+	source   string
+	output   string
+}
+
+func main() {
+	opts, err := parseFlags(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if err := run(opts); err != nil {
+		fmt.Fprintln(os.Stderr, "lambda-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func parseFlags(args []string, stderr io.Writer) (options, error) {
+	var opts options
+
+	fs := flag.NewFlagSet("lambda-gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.typeName, "type", "", "interface type name (required)")
+	fs.StringVar(&opts.source, "source", os.Getenv("GOFILE"), "file declaring the interface, $GOFILE by default")
+	fs.StringVar(&opts.output, "output", "", "generated file, <type>_lambda.go next to the source by default")
+
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+
+	if opts.typeName == "" {
+		return opts, errors.New("-type is required")
+	}
+	if opts.source == "" {
+		return opts, errors.New("-source is required outside of go generate")
+	}
+	if opts.output == "" {
+		opts.output = filepath.Join(filepath.Dir(opts.source), strings.ToLower(opts.typeName)+"_lambda.go")
+	}
+
+	return opts, nil
+}
+
+func run(opts options) error {
+	src, err := os.ReadFile(opts.source)
+	if err != nil {
+		return fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	svc, err := parseService(opts.source, src, opts.typeName)
+	if err != nil {
+		return fmt.Errorf("parseService: %w", err)
+	}
+
+	out, err := generate(svc)
+	if err != nil {
+		return fmt.Errorf("generate: %w", err)
+	}
+
+	if err := os.WriteFile(opts.output, out, 0o644); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+
+	return nil
+}
+
+// service is the interface to generate the client and the handler of.
+type service struct {
+	Package string
+	Name    string
+	Imports []string
+	Methods []method
+}
+
+type method struct {
+	Name       string
+	HTTPMethod string
+	Path       string
+	// In and Out are the request and response type expressions, empty if the method has none.
+	In  string
+	Out string
+}
+
+var (
+	routeAnnotation = regexp.MustCompile(`^lambda:route\s+([A-Z]+)\s+(/\S*)$`)
+	majorVersion    = regexp.MustCompile(`^v\d+$`)
+)
+
+func parseService(filename string, src []byte, typeName string) (*service, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parser.ParseFile: %w", err)
+	}
+
+	iface, err := findInterface(file, typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	svc := &service{Package: file.Name.Name, Name: typeName}
+	used := make(map[string]bool)
+
+	for _, field := range iface.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			return nil, fmt.Errorf("%s: embedded interfaces are not supported", typeName)
+		}
+
+		m, err := parseMethod(fset, field.Names[0].Name, field.Doc, ft, used)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, field.Names[0].Name, err)
+		}
+
+		svc.Methods = append(svc.Methods, m)
+	}
+
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		if importPath == "context" || !used[importName(spec, importPath)] {
+			continue
+		}
+
+		if spec.Name != nil {
+			svc.Imports = append(svc.Imports, spec.Name.Name+" "+spec.Path.Value)
+		} else {
+			svc.Imports = append(svc.Imports, spec.Path.Value)
+		}
+	}
+
+	return svc, nil
+}
+
+func findInterface(file *ast.File, typeName string) (*ast.InterfaceType, error) {
+	for _, decl := range file.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != typeName {
+				continue
+			}
+
+			iface, ok := ts.Type.(*ast.InterfaceType)
+			if !ok {
+				return nil, fmt.Errorf("%s is not an interface", typeName)
+			}
+
+			return iface, nil
+		}
+	}
+
+	return nil, fmt.Errorf("interface %s not found", typeName)
+}
+
+func parseMethod(fset *token.FileSet, name string, doc *ast.CommentGroup, ft *ast.FuncType, used map[string]bool) (method, error) {
+	m := method{Name: name, HTTPMethod: "POST", Path: "/" + name}
+
+	if doc != nil {
+		for _, line := range strings.Split(doc.Text(), "\n") {
+			if match := routeAnnotation.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				m.HTTPMethod, m.Path = match[1], match[2]
+			}
+		}
+	}
+	if strings.ContainsAny(m.Path, "{}") {
+		return m, fmt.Errorf("path parameters are not supported: %s", m.Path)
+	}
+
+	params := fieldTypes(ft.Params)
+	if len(params) == 0 || len(params) > 2 || !isSelector(params[0], "context", "Context") {
+		return m, errors.New("parameters must be (context.Context) or (context.Context, request)")
+	}
+
+	results := fieldTypes(ft.Results)
+	if len(results) == 0 || len(results) > 2 || !isIdent(results[len(results)-1], "error") {
+		return m, errors.New("results must be error or (response, error)")
+	}
+
+	var err error
+	if len(params) == 2 {
+		if m.In, err = typeString(fset, params[1], used); err != nil {
+			return m, err
+		}
+	}
+	if len(results) == 2 {
+		if m.Out, err = typeString(fset, results[0], used); err != nil {
+			return m, err
+		}
+	}
+
+	return m, nil
+}
+
+// fieldTypes returns a type per parameter, e.g. two for (a, b int).
+func fieldTypes(fields *ast.FieldList) []ast.Expr {
+	if fields == nil {
+		return nil
+	}
+
+	var types []ast.Expr
+	for _, field := range fields.List {
+		for range max(len(field.Names), 1) {
+			types = append(types, field.Type)
+		}
+	}
+
+	return types
+}
+
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	return ok && isIdent(sel.X, pkg) && sel.Sel.Name == name
+}
+
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// typeString prints the type expression and records the packages it refers to.
+func typeString(fset *token.FileSet, expr ast.Expr, used map[string]bool) (string, error) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return "", fmt.Errorf("printer.Fprint: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// importName is the name the file refers to the import by, the last path element without a major version suffix
+// unless renamed.
+func importName(spec *ast.ImportSpec, importPath string) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+
+	name := path.Base(importPath)
+	if majorVersion.MatchString(name) {
+		name = path.Base(path.Dir(importPath))
+	}
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+
+	return strings.TrimPrefix(name, "go-")
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by lambda-gen; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+	"lambda-invoker/internal/clients/lambda"
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+)
+
+// {{.Name}}Client implements {{.Name}} by invoking a function serving New{{.Name}}Handler.
+type {{.Name}}Client struct {
+	client lambda.Client
+}
+
+var _ {{.Name}} = (*{{.Name}}Client)(nil)
+
+// New{{.Name}}Client returns the {{.Name}} invoking the function of the client.
+func New{{.Name}}Client(client lambda.Client) *{{.Name}}Client {
+	return &{{.Name}}Client{client: client}
+}
+{{range .Methods}}
+func (c *{{$.Name}}Client) {{.Name}}(ctx context.Context{{if .In}}, in {{.In}}{{end}}) {{if .Out}}({{.Out}}, error){{else}}error{{end}} {
+{{- if .Out}}
+	return lambda.CallAs[{{.Out}}](ctx, c.client, "{{.HTTPMethod}}", "{{.Path}}", {{if .In}}in{{else}}nil{{end}})
+{{- else}}
+	return c.client.Call(ctx, "{{.HTTPMethod}}", "{{.Path}}", {{if .In}}in{{else}}nil{{end}}, nil)
+{{- end}}
+}
+{{end}}
+// New{{.Name}}Handler routes requests of {{.Name}}Client to impl, pass its Invoke to lambda.Start of aws-lambda-go.
+func New{{.Name}}Handler(impl {{.Name}}) *lambda.Mux {
+	mux := lambda.NewMux()
+{{- range .Methods}}
+{{- if and .In .Out}}
+	mux.Handle("{{.HTTPMethod}} {{.Path}}", lambda.RPCHandler(impl.{{.Name}}))
+{{- else if .In}}
+	mux.Handle("{{.HTTPMethod}} {{.Path}}", lambda.RPCHandler(func(ctx context.Context, in {{.In}}) (struct{}, error) {
+		return struct{}{}, impl.{{.Name}}(ctx, in)
+	}))
+{{- else if .Out}}
+	mux.Handle("{{.HTTPMethod}} {{.Path}}", lambda.RPCHandler(func(ctx context.Context, _ struct{}) ({{.Out}}, error) {
+		return impl.{{.Name}}(ctx)
+	}))
+{{- else}}
+	mux.Handle("{{.HTTPMethod}} {{.Path}}", lambda.RPCHandler(func(ctx context.Context, _ struct{}) (struct{}, error) {
+		return struct{}{}, impl.{{.Name}}(ctx)
+	}))
+{{- end}}
+{{- end}}
+
+	return mux
+}
+`))
+
+func generate(svc *service) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, svc); err != nil {
+		return nil, fmt.Errorf("fileTemplate.Execute: %w", err)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format.Source: %w\n%s", err, buf.Bytes())
+	}
+
+	return out, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_Golden(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "orders_lambda.go")
+	require.NoError(t, run(options{typeName: "Orders", source: "internal/orders/orders.go", output: output}))
+
+	got, err := os.ReadFile(output)
+	require.NoError(t, err)
+	want, err := os.ReadFile("internal/orders/orders_lambda.go")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(want), string(got), "run go generate ./cmd/lambda-gen/... to update")
+}
+
+func TestParseService(t *testing.T) {
+	src := `package svc
+
+import (
+	"context"
+	"time"
+	v1 "example.com/api/v1"
+	"example.com/unused"
+)
+
+type Svc interface {
+	// lambda:route PUT /things
+	Put(ctx context.Context, in *v1.Thing) (map[string]time.Time, error)
+}
+`
+	svc, err := parseService("svc.go", []byte(src), "Svc")
+	require.NoError(t, err)
+
+	assert.Equal(t, "svc", svc.Package)
+	assert.Equal(t, []string{`"time"`, `v1 "example.com/api/v1"`}, svc.Imports)
+	assert.Equal(t, []method{{Name: "Put", HTTPMethod: "PUT", Path: "/things", In: "*v1.Thing", Out: "map[string]time.Time"}}, svc.Methods)
+
+	_, err = generate(svc)
+	require.NoError(t, err)
+}
+
+func TestParseService_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no context":      `type Svc interface { Get(id string) error }`,
+		"no error":        `type Svc interface { Get(ctx context.Context) string }`,
+		"too many params": `type Svc interface { Get(ctx context.Context, a, b string) error }`,
+		"path params":     "type Svc interface {\n// lambda:route GET /things/{id}\nGet(ctx context.Context) error\n}",
+		"embedded":        `type Svc interface { io.Reader }`,
+		"not interface":   `type Svc struct{}`,
+		"missing":         `type Other interface{}`,
+	}
+
+	for name, decl := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseService("svc.go", []byte("package svc\n"+decl), "Svc")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	t.Setenv("GOFILE", "orders.go")
+
+	opts, err := parseFlags([]string{"-type", "Orders"}, io.Discard)
+	require.NoError(t, err)
+	assert.Equal(t, options{typeName: "Orders", source: "orders.go", output: "orders_lambda.go"}, opts)
+
+	_, err = parseFlags(nil, io.Discard)
+	require.Error(t, err)
+}
@@ -0,0 +1,463 @@
+// Command lambda-invoker invokes a deployed API Gateway proxy function from the shell.
+//
+//	lambda-invoker --arn my-function --method POST --path /orders --body @order.json
+//	lambda-invoker --arn my-function --path /orders/42 --tail --output json
+//	lambda-invoker --arn my-function --path /orders --query 'items[].id'
+//	lambda-invoker --arn my-function --method POST --path /orders --template --var tenant=acme \
+//		--body '{"tenant":"{{.Vars.tenant}}","seq":{{.Index}}}' --requests 100 --concurrency 10
+//	jq '.order' input.json | lambda-invoker --arn my-function --method POST --path /orders --body -
+//	lambda-invoker --arn my-function --path /reports/42.pdf --out report.pdf
+//
+// --watch re-invokes the function whenever the body file changes and prints a diff of the output:
+//
+//	lambda-invoker --arn my-function --method POST --path /orders --body @order.json --watch
+//
+// Setting --requests or --duration runs a load test and prints a latency and error report instead:
+//
+//	lambda-invoker --arn my-function --path /orders/42 --concurrency 20 --duration 1m --ramp-up 10s
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	awslambda "github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"io"
+	"lambda-invoker/internal/clients/lambda"
+	"lambda-invoker/internal/clients/lambda/loadtest"
+	"maps"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+type options struct {
+	arn       string
+	region    string
+	endpoint  string
+	method    string
+	path      string
+	body      string
+	headers   headerFlags
+	qualifier string
+	async     bool
+	tail      bool
+	output    string
+
+	concurrency int
+	requests    int
+	duration    time.Duration
+	rampUp      time.Duration
+
+	watch    bool
+	interval time.Duration
+
+	out   string
+	query string
+
+	template bool
+	vars     varFlags
+}
+
+// stdin is the source of --body -, replaced in tests.
+var stdin io.Reader = os.Stdin
+
+func main() {
+	opts, err := parseFlags(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, opts, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func parseFlags(args []string, stderr io.Writer) (options, error) {
+	var opts options
+
+	fs := flag.NewFlagSet("lambda-invoker", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.arn, "arn", "", "function name or ARN, optionally qualified (required)")
+	fs.StringVar(&opts.region, "region", "", "AWS region, the default config region if empty")
+	fs.StringVar(&opts.endpoint, "endpoint", "", "Lambda endpoint override, e.g. http://localhost:4566 for LocalStack")
+	fs.StringVar(&opts.method, "method", "GET", "HTTP method")
+	fs.StringVar(&opts.path, "path", "/", "request path")
+	fs.StringVar(&opts.body, "body", "", "request body, @file reads it from the file and - from stdin")
+	fs.Var(&opts.headers, "header", "request header as Name: value, repeatable")
+	fs.StringVar(&opts.qualifier, "qualifier", "", "function version or alias")
+	fs.BoolVar(&opts.async, "async", false, "invoke asynchronously with the Event invocation type")
+	fs.BoolVar(&opts.tail, "tail", false, "print the tail of the execution log to stderr")
+	fs.BoolVar(&opts.template, "template", false, "render the body as a Go template, see lambda.ParseTemplate")
+	fs.Var(&opts.vars, "var", "template variable as name=value, available as {{.Vars.name}}, repeatable")
+	fs.StringVar(&opts.query, "query", "", "JMESPath expression extracting a value from the JSON response body")
+	fs.StringVar(&opts.out, "out", "", "file to write the response body to, base64 encoded bodies are decoded")
+	fs.StringVar(&opts.output, "output", "raw", "output format: raw prints the response body, json the whole response")
+	fs.IntVar(&opts.concurrency, "concurrency", 1, "load mode: number of in-flight invocations")
+	fs.IntVar(&opts.requests, "requests", 0, "load mode: total number of invocations")
+	fs.DurationVar(&opts.duration, "duration", 0, "load mode: duration of the load test")
+	fs.DurationVar(&opts.rampUp, "ramp-up", 0, "load mode: duration of the linear ramp-up to --concurrency")
+	fs.BoolVar(&opts.watch, "watch", false, "watch mode: re-invoke whenever the --body @file changes")
+	fs.DurationVar(&opts.interval, "interval", 0, "watch mode: re-invoke on the interval")
+
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+
+	if opts.arn == "" {
+		return opts, errors.New("--arn is required")
+	}
+
+	if opts.output != "raw" && opts.output != "json" {
+		return opts, fmt.Errorf("--output must be raw or json: %s", opts.output)
+	}
+
+	if opts.watch && !strings.HasPrefix(opts.body, "@") {
+		return opts, errors.New("--watch requires --body @file")
+	}
+
+	if opts.concurrency < 1 {
+		return opts, fmt.Errorf("--concurrency must be positive: %d", opts.concurrency)
+	}
+
+	return opts, nil
+}
+
+// watchMode reports whether the function is re-invoked on body file changes or on an interval.
+func (o options) watchMode() bool {
+	return o.watch || o.interval > 0
+}
+
+// loadMode reports whether a load test is requested rather than a single invocation.
+func (o options) loadMode() bool {
+	return o.requests > 0 || o.duration > 0
+}
+
+func run(ctx context.Context, opts options, stdout, stderr io.Writer) error {
+	cli, err := newClient(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("newClient: %w", err)
+	}
+
+	switch {
+	case opts.loadMode():
+		return load(ctx, cli, opts, stdout)
+	case opts.watchMode():
+		return watch(ctx, cli, opts, stdout, stderr)
+	default:
+		return invoke(ctx, cli, opts, stdout, stderr)
+	}
+}
+
+func newRequest(opts options) (lambda.Request, error) {
+	next, err := requestFunc(opts)
+	if err != nil {
+		return lambda.Request{}, err
+	}
+
+	return next(0)
+}
+
+// requestFunc reads the body once and returns the i-th request,
+// --template bodies are rendered with the index and --var variables.
+func requestFunc(opts options) (func(i int) (lambda.Request, error), error) {
+	body, err := readBody(opts.body)
+	if err != nil {
+		return nil, fmt.Errorf("readBody: %w", err)
+	}
+
+	if !opts.template {
+		req := buildRequest(opts, body)
+		return func(int) (lambda.Request, error) { return req, nil }, nil
+	}
+
+	tmpl, err := lambda.ParseTemplate(string(body))
+	if err != nil {
+		return nil, fmt.Errorf("lambda.ParseTemplate: %w", err)
+	}
+
+	return func(i int) (lambda.Request, error) {
+		rendered, err := tmpl.Render(lambda.TemplateData{Index: i, Vars: opts.vars.values})
+		if err != nil {
+			return lambda.Request{}, fmt.Errorf("tmpl.Render: %w", err)
+		}
+		return buildRequest(opts, rendered), nil
+	}, nil
+}
+
+func buildRequest(opts options, body []byte) lambda.Request {
+	req := lambda.Request{
+		HTTPMethod: strings.ToUpper(opts.method),
+		Path:       opts.path,
+		Headers:    opts.headers.values,
+		Body:       body,
+		Async:      opts.async,
+	}
+
+	if len(body) > 0 && !hasHeader(req.Headers, "Content-Type") {
+		contentType := detectContentType(body)
+		if contentType == "application/octet-stream" {
+			req.Body = []byte(base64.StdEncoding.EncodeToString(body))
+			req.IsBase64Encoded = true
+		}
+		req.Headers = maps.Clone(req.Headers)
+		if req.Headers == nil {
+			req.Headers = make(map[string]string, 1)
+		}
+		req.Headers["Content-Type"] = contentType
+	}
+
+	return req
+}
+
+// detectContentType tells JSON from other text and binary bodies, binary bodies are sent base64 encoded.
+func detectContentType(body []byte) string {
+	switch {
+	case json.Valid(body):
+		return "application/json"
+	case utf8.Valid(body):
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func hasHeader(headers map[string]string, name string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func invoke(ctx context.Context, cli lambda.Client, opts options, stdout, stderr io.Writer) error {
+	req, err := newRequest(opts)
+	if err != nil {
+		return fmt.Errorf("newRequest: %w", err)
+	}
+
+	var callOpts []lambda.Option
+	if opts.tail {
+		callOpts = append(callOpts, lambda.WithLogType(types.LogTypeTail))
+	}
+	if opts.query != "" {
+		callOpts = append(callOpts, lambda.WithJMESPath(opts.query))
+	}
+
+	resp, err := cli.Do(ctx, req, callOpts...)
+
+	// responses of unexpected status codes are printed before failing, like curl --fail-with-body
+	var statusErr *lambda.ErrUnexpectedStatus
+	if err != nil && !errors.As(err, &statusErr) {
+		return fmt.Errorf("cli.Do: %w", err)
+	}
+
+	if opts.tail && resp.LogResult != "" {
+		fmt.Fprint(stderr, resp.LogResult)
+	}
+
+	if opts.out != "" {
+		if err := writeBody(opts.out, resp); err != nil {
+			return fmt.Errorf("writeBody: %w", err)
+		}
+	}
+
+	// the body written to --out is not repeated on stdout, unlike the rest of the json output
+	if opts.out == "" || opts.output == "json" {
+		if err := writeResponse(stdout, opts.output, resp); err != nil {
+			return fmt.Errorf("writeResponse: %w", err)
+		}
+	}
+
+	if statusErr != nil {
+		return fmt.Errorf("unexpected status code: %d", statusErr.Code)
+	}
+
+	return nil
+}
+
+func load(ctx context.Context, cli lambda.Client, opts options, stdout io.Writer) error {
+	next, err := requestFunc(opts)
+	if err != nil {
+		return fmt.Errorf("requestFunc: %w", err)
+	}
+
+	report, err := loadtest.Run(ctx, cli, loadtest.Config{
+		NewRequest:  next,
+		Concurrency: opts.concurrency,
+		Requests:    opts.requests,
+		Duration:    opts.duration,
+		RampUp:      opts.rampUp,
+	})
+	if err != nil {
+		return fmt.Errorf("loadtest.Run: %w", err)
+	}
+
+	_, err = io.WriteString(stdout, report.String())
+	return err
+}
+
+func newClient(ctx context.Context, opts options) (lambda.Client, error) {
+	var cfgOpts []func(*config.LoadOptions) error
+	if opts.region != "" {
+		cfgOpts = append(cfgOpts, config.WithRegion(opts.region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, cfgOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("config.LoadDefaultConfig: %w", err)
+	}
+
+	awsCli := awslambda.NewFromConfig(cfg, func(o *awslambda.Options) {
+		if opts.endpoint != "" {
+			o.BaseEndpoint = aws.String(opts.endpoint)
+		}
+	})
+
+	clientOpts := []lambda.Option{lambda.WithConcurrency(opts.concurrency)}
+	if opts.qualifier != "" {
+		clientOpts = append(clientOpts, lambda.WithQualifier(opts.qualifier))
+	}
+
+	return lambda.New(awsCli, opts.arn, clientOpts...)
+}
+
+// readBody returns the literal body, the content of the file for @file or stdin for -.
+// Heredocs are passed as stdin.
+func readBody(body string) ([]byte, error) {
+	if body == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("io.ReadAll: %w", err)
+		}
+		return data, nil
+	}
+
+	if name, ok := strings.CutPrefix(body, "@"); ok {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("os.ReadFile: %w", err)
+		}
+		return data, nil
+	}
+
+	if body == "" {
+		return nil, nil
+	}
+
+	return []byte(body), nil
+}
+
+// writeBody writes the decoded response body to the file.
+func writeBody(name string, resp *lambda.Response) error {
+	body, err := resp.Bytes()
+	if err != nil {
+		return fmt.Errorf("resp.Bytes: %w", err)
+	}
+
+	if err := os.WriteFile(name, body, 0o644); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+
+	return nil
+}
+
+func writeResponse(w io.Writer, output string, resp *lambda.Response) error {
+	if output == "raw" {
+		body := resp.Body
+		if body != "" && !strings.HasSuffix(body, "\n") {
+			body += "\n"
+		}
+		_, err := io.WriteString(w, body)
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(struct {
+		StatusCode      int                    `json:"statusCode"`
+		Headers         map[string]string      `json:"headers,omitempty"`
+		Body            string                 `json:"body"`
+		IsBase64Encoded bool                   `json:"isBase64Encoded,omitempty"`
+		RequestID       string                 `json:"requestId,omitempty"`
+		ExecutedVersion string                 `json:"executedVersion,omitempty"`
+		Stats           *lambda.ExecutionStats `json:"stats,omitempty"`
+	}{
+		StatusCode:      resp.StatusCode,
+		Headers:         resp.Headers,
+		Body:            resp.Body,
+		IsBase64Encoded: resp.IsBase64Encoded,
+		RequestID:       resp.RequestID,
+		ExecutedVersion: resp.ExecutedVersion,
+		Stats:           resp.Stats,
+	}); err != nil {
+		return fmt.Errorf("enc.Encode: %w", err)
+	}
+
+	return nil
+}
+
+// headerFlags collects repeated --header flags.
+type headerFlags struct {
+	values map[string]string
+}
+
+func (h *headerFlags) String() string {
+	return fmt.Sprint(h.values)
+}
+
+func (h *headerFlags) Set(s string) error {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok {
+		return fmt.Errorf("header must be Name: value: %s", s)
+	}
+
+	if h.values == nil {
+		h.values = make(map[string]string)
+	}
+	h.values[strings.TrimSpace(name)] = strings.TrimSpace(value)
+
+	return nil
+}
+
+// varFlags collects repeated --var flags.
+type varFlags struct {
+	values map[string]string
+}
+
+func (v *varFlags) String() string {
+	return fmt.Sprint(v.values)
+}
+
+func (v *varFlags) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("var must be name=value: %s", s)
+	}
+
+	if v.values == nil {
+		v.values = make(map[string]string)
+	}
+	v.values[name] = value
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"lambda-invoker/internal/clients/lambda"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	opts, err := parseFlags([]string{
+		"--arn", "my-function", "--method", "post", "--path", "/orders",
+		"--header", "X-Tenant: acme", "--header", "Accept:application/json", "--async",
+	}, io.Discard)
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-function", opts.arn)
+	assert.Equal(t, "post", opts.method)
+	assert.Equal(t, "/orders", opts.path)
+	assert.Equal(t, map[string]string{"X-Tenant": "acme", "Accept": "application/json"}, opts.headers.values)
+	assert.True(t, opts.async)
+	assert.Equal(t, "raw", opts.output)
+	assert.False(t, opts.loadMode())
+
+	opts, err = parseFlags([]string{"--arn", "f", "--concurrency", "8", "--duration", "1m"}, io.Discard)
+	require.NoError(t, err)
+	assert.True(t, opts.loadMode())
+	assert.Equal(t, 8, opts.concurrency)
+
+	_, err = parseFlags([]string{"--arn", "f", "--watch", "--body", "{}"}, io.Discard)
+	require.Error(t, err, "--watch requires a body file")
+
+	opts, err = parseFlags([]string{"--arn", "f", "--interval", "5s"}, io.Discard)
+	require.NoError(t, err)
+	assert.True(t, opts.watchMode())
+
+	_, err = parseFlags(nil, io.Discard)
+	require.Error(t, err)
+
+	_, err = parseFlags([]string{"--arn", "f", "--output", "yaml"}, io.Discard)
+	require.Error(t, err)
+}
+
+func TestReadBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "order.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"id":1}`), 0o600))
+
+	body, err := readBody("@" + path)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":1}`, string(body))
+
+	body, err = readBody(`{"id":2}`)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":2}`, string(body))
+
+	body, err = readBody("")
+	require.NoError(t, err)
+	assert.Nil(t, body)
+
+	stdin = strings.NewReader(`{"id":3}`)
+	t.Cleanup(func() { stdin = os.Stdin })
+
+	body, err = readBody("-")
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":3}`, string(body))
+}
+
+func TestNewRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        options
+		contentType string
+		body        string
+		base64      bool
+	}{
+		{"json", options{body: `{"id":1}`}, "application/json", `{"id":1}`, false},
+		{"text", options{body: "hello"}, "text/plain; charset=utf-8", "hello", false},
+		{"binary", options{body: "\xff\xfe"}, "application/octet-stream", "//4=", true},
+		{"explicit", options{body: "hello", headers: headerFlags{values: map[string]string{"content-type": "text/csv"}}}, "", "hello", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := newRequest(tt.opts)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.contentType, req.Headers["Content-Type"])
+			assert.Equal(t, tt.body, string(req.Body))
+			assert.Equal(t, tt.base64, req.IsBase64Encoded)
+		})
+	}
+}
+
+func TestRequestFunc(t *testing.T) {
+	opts, err := parseFlags([]string{"--arn", "f", "--template", "--var", "tenant=acme",
+		"--body", `{"tenant":"{{.Vars.tenant}}","seq":{{.Index}}}`}, io.Discard)
+	require.NoError(t, err)
+
+	next, err := requestFunc(opts)
+	require.NoError(t, err)
+
+	req, err := next(3)
+	require.NoError(t, err)
+	assert.Equal(t, `{"tenant":"acme","seq":3}`, string(req.Body))
+	assert.Equal(t, "application/json", req.Headers["Content-Type"])
+}
+
+func TestWriteBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.bin")
+
+	require.NoError(t, writeBody(path, &lambda.Response{Body: "//4=", IsBase64Encoded: true}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0xfe}, data)
+}
+
+func TestWriteResponse(t *testing.T) {
+	resp := &lambda.Response{StatusCode: 200, Body: `{"id":1}`, RequestID: "req-1"}
+
+	var raw bytes.Buffer
+	require.NoError(t, writeResponse(&raw, "raw", resp))
+	assert.Equal(t, "{\"id\":1}\n", raw.String())
+
+	var js bytes.Buffer
+	require.NoError(t, writeResponse(&js, "json", resp))
+	assert.JSONEq(t, `{"statusCode":200,"body":"{\"id\":1}","requestId":"req-1"}`, js.String())
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"lambda-invoker/internal/clients/lambda"
+	"os"
+	"strings"
+	"time"
+)
+
+// pollInterval is how often the body file is checked for changes.
+const pollInterval = 500 * time.Millisecond
+
+// watch invokes the function and re-invokes it whenever the body file changes or the interval elapses,
+// printing the first output in full and diffs of following outputs. Failed invocations do not stop watching.
+func watch(ctx context.Context, cli lambda.Client, opts options, stdout, stderr io.Writer) error {
+	var prev string
+	first := true
+
+	invokeAndDiff := func() {
+		var out bytes.Buffer
+		if err := invoke(ctx, cli, opts, &out, stderr); err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", time.Now().Format(time.TimeOnly), err)
+		}
+
+		switch {
+		case first:
+			fmt.Fprint(stdout, out.String())
+		case out.String() == prev:
+			fmt.Fprintf(stderr, "%s: no changes\n", time.Now().Format(time.TimeOnly))
+		default:
+			fmt.Fprint(stdout, diff(prev, out.String()))
+		}
+
+		prev, first = out.String(), false
+	}
+
+	invokeAndDiff()
+
+	var lastMod time.Time
+	if opts.watch {
+		lastMod = modTime(strings.TrimPrefix(opts.body, "@"))
+	}
+	lastRun := time.Now()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		changed := false
+		if opts.watch {
+			if mod := modTime(strings.TrimPrefix(opts.body, "@")); !mod.Equal(lastMod) {
+				lastMod, changed = mod, true
+			}
+		}
+
+		if changed || (opts.interval > 0 && time.Since(lastRun) >= opts.interval) {
+			invokeAndDiff()
+			lastRun = time.Now()
+		}
+	}
+}
+
+// modTime returns the modification time of the file, zero if it can't be read.
+func modTime(name string) time.Time {
+	info, err := os.Stat(name)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+// diff returns a line diff of a and b, removed lines are prefixed with "-", added lines with "+".
+func diff(a, b string) string {
+	x, y := strings.Split(strings.TrimSuffix(a, "\n"), "\n"), strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			sb.WriteString("  " + x[i] + "\n")
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("- " + x[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+ " + y[j] + "\n")
+			j++
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,18 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := "{\n  \"id\": 1,\n  \"status\": \"new\"\n}\n"
+	b := "{\n  \"id\": 1,\n  \"status\": \"paid\",\n  \"total\": 10\n}\n"
+
+	assert.Equal(t, "  {\n"+
+		"    \"id\": 1,\n"+
+		"-   \"status\": \"new\"\n"+
+		"+   \"status\": \"paid\",\n"+
+		"+   \"total\": 10\n"+
+		"  }\n", diff(a, b))
+}
@@ -0,0 +1,161 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+)
+
+// AppSyncRequest is a GraphQL field resolution sent to a direct Lambda resolver.
+type AppSyncRequest struct {
+	// TypeName is the parent type of the field, e.g. "Query" or "Mutation".
+	TypeName  string
+	FieldName string
+	Arguments any
+	// Identity is *events.AppSyncIAMIdentity, *events.AppSyncCognitoIdentity or nil for API key authorization.
+	Identity any
+	// Source is the parent object of nested fields.
+	Source           any
+	Headers          map[string]string
+	SelectionSetList []string
+	Variables        map[string]any
+	Stash            map[string]any
+}
+
+// appSyncEvent is the direct Lambda resolver event.
+type appSyncEvent struct {
+	Arguments any            `json:"arguments"`
+	Identity  any            `json:"identity"`
+	Source    any            `json:"source"`
+	Request   appSyncHeaders `json:"request"`
+	Prev      any            `json:"prev"`
+	Info      appSyncInfo    `json:"info"`
+	Stash     map[string]any `json:"stash"`
+}
+
+type appSyncHeaders struct {
+	Headers map[string]string `json:"headers"`
+}
+
+type appSyncInfo struct {
+	SelectionSetList []string       `json:"selectionSetList"`
+	ParentTypeName   string         `json:"parentTypeName"`
+	FieldName        string         `json:"fieldName"`
+	Variables        map[string]any `json:"variables"`
+}
+
+// AppSyncBatchResult is an item of the BatchInvoke response, resolvers report per item errors in it.
+type AppSyncBatchResult struct {
+	Data         json.RawMessage `json:"data"`
+	ErrorMessage string          `json:"errorMessage,omitempty"`
+	ErrorType    string          `json:"errorType,omitempty"`
+}
+
+// AppSyncResolver invokes GraphQL resolver functions the way AppSync does, with raw payloads.
+type AppSyncResolver struct {
+	client Client
+}
+
+// NewAppSyncResolver returns a resolver of the target function, opts are applied after WithEnvelope(EnvelopeRaw).
+func NewAppSyncResolver(cli *lambda.Client, target string, opts ...Option) (*AppSyncResolver, error) {
+	client, err := New(cli, target, append([]Option{WithEnvelope(EnvelopeRaw)}, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("New: %w", err)
+	}
+
+	return &AppSyncResolver{client: client}, nil
+}
+
+// Resolve sends the direct Lambda resolver event of req and decodes the result into out, unless nil.
+func (r *AppSyncResolver) Resolve(ctx context.Context, req AppSyncRequest, out any) error {
+	return r.invoke(ctx, req.path(), newAppSyncEvent(req), out)
+}
+
+// BatchResolve sends the events of reqs as a single BatchInvoke, as AppSync does for batched resolvers,
+// the function responds with a result per request in order.
+func (r *AppSyncResolver) BatchResolve(ctx context.Context, reqs []AppSyncRequest) ([]AppSyncBatchResult, error) {
+	batch := make([]appSyncEvent, len(reqs))
+	for i, req := range reqs {
+		batch[i] = newAppSyncEvent(req)
+	}
+
+	var path string
+	if len(reqs) > 0 {
+		path = reqs[0].path()
+	}
+
+	var results []AppSyncBatchResult
+	if err := r.invoke(ctx, path, batch, &results); err != nil {
+		return nil, err
+	}
+
+	if len(results) != len(reqs) {
+		return nil, fmt.Errorf("batch results: got %d, want %d", len(results), len(reqs))
+	}
+
+	return results, nil
+}
+
+// ResolveTemplate sends the payload of a VTL request mapping template as events.AppSyncResolverTemplate
+// with the Invoke operation and decodes the result into out, unless nil.
+func (r *AppSyncResolver) ResolveTemplate(ctx context.Context, payload, out any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	//nolint:staticcheck // the operation is deprecated in aws-lambda-go but still sent by AppSync templates
+	event := events.AppSyncResolverTemplate{Version: "2017-02-28", Operation: events.OperationInvoke, Payload: raw}
+
+	return r.invoke(ctx, "/", event, out)
+}
+
+func (r *AppSyncResolver) invoke(ctx context.Context, path string, event, out any) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	resp, err := r.client.Do(ctx, Request{HTTPMethod: "POST", Path: path, Body: payload})
+	if err != nil {
+		return fmt.Errorf("appsync[%s]: %w", path, err)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(resp.Body), out); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return nil
+}
+
+func newAppSyncEvent(req AppSyncRequest) appSyncEvent {
+	arguments := req.Arguments
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+
+	return appSyncEvent{
+		Arguments: arguments,
+		Identity:  req.Identity,
+		Source:    req.Source,
+		Request:   appSyncHeaders{Headers: req.Headers},
+		Info: appSyncInfo{
+			SelectionSetList: req.SelectionSetList,
+			ParentTypeName:   req.TypeName,
+			FieldName:        req.FieldName,
+			Variables:        req.Variables,
+		},
+		Stash: req.Stash,
+	}
+}
+
+// path names the field for logs and metrics, e.g. "/Query/getPost".
+func (req AppSyncRequest) path() string {
+	return "/" + req.TypeName + "/" + req.FieldName
+}
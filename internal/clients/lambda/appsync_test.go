@@ -0,0 +1,84 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newAppSyncResolver(api lambdaAPI) *AppSyncResolver {
+	return &AppSyncResolver{client: newClient(api, testFunctionARN, WithEnvelope(EnvelopeRaw))}
+}
+
+func TestAppSyncResolver_Resolve(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		var event map[string]any
+		require.NoError(t, json.Unmarshal(in.Payload, &event))
+
+		assert.Equal(t, map[string]any{"id": "42"}, event["arguments"])
+		assert.Equal(t, "alice", event["identity"].(map[string]any)["username"])
+		assert.Equal(t, map[string]any{"headers": map[string]any{"x-api-key": "secret"}}, event["request"])
+		assert.Equal(t, map[string]any{
+			"selectionSetList": []any{"id", "title"},
+			"parentTypeName":   "Query",
+			"fieldName":        "getPost",
+			"variables":        nil,
+		}, event["info"])
+
+		return &lambda.InvokeOutput{StatusCode: 200, Payload: []byte(`{"id":"42","title":"Hello"}`)}, nil
+	}}
+	resolver := newAppSyncResolver(api)
+
+	var post struct{ ID, Title string }
+	err := resolver.Resolve(_ctx, AppSyncRequest{
+		TypeName:         "Query",
+		FieldName:        "getPost",
+		Arguments:        map[string]string{"id": "42"},
+		Identity:         &events.AppSyncCognitoIdentity{Sub: "user-1", Username: "alice"},
+		Headers:          map[string]string{"x-api-key": "secret"},
+		SelectionSetList: []string{"id", "title"},
+	}, &post)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", post.Title)
+}
+
+func TestAppSyncResolver_BatchResolve(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		var batch []appSyncEvent
+		require.NoError(t, json.Unmarshal(in.Payload, &batch))
+		assert.Equal(t, map[string]any{"id": "1"}, batch[0].Source)
+
+		return &lambda.InvokeOutput{StatusCode: 200, Payload: []byte(`[{"data":{"name":"alice"}},{"data":null,"errorMessage":"not found","errorType":"NotFound"}]`)}, nil
+	}}
+	resolver := newAppSyncResolver(api)
+
+	results, err := resolver.BatchResolve(_ctx, []AppSyncRequest{
+		{TypeName: "Post", FieldName: "author", Source: map[string]string{"id": "1"}},
+		{TypeName: "Post", FieldName: "author", Source: map[string]string{"id": "2"}},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"alice"}`, string(results[0].Data))
+	assert.Equal(t, "NotFound", results[1].ErrorType)
+
+	_, err = resolver.BatchResolve(_ctx, []AppSyncRequest{{TypeName: "Post", FieldName: "author", Source: map[string]string{"id": "1"}}})
+	require.Error(t, err, "result count mismatch")
+}
+
+func TestAppSyncResolver_ResolveTemplate(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		assert.JSONEq(t, `{"version":"2017-02-28","operation":"Invoke","payload":{"field":"getPost","id":"42"}}`, string(in.Payload))
+
+		return &lambda.InvokeOutput{StatusCode: 200, FunctionError: aws.String("Unhandled"), Payload: []byte(`{"errorMessage":"boom"}`)}, nil
+	}}
+
+	err := newAppSyncResolver(api).ResolveTemplate(_ctx, map[string]string{"field": "getPost", "id": "42"}, nil)
+
+	var ie *InvocationError
+	require.ErrorAs(t, err, &ie)
+	assert.Equal(t, "boom", ie.Message)
+}
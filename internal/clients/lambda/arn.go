@@ -0,0 +1,85 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"regexp"
+	"strings"
+)
+
+// functionNamePattern is the FunctionName pattern of the Invoke API: a name, a partial or a full ARN, optionally qualified.
+var functionNamePattern = regexp.MustCompile(`^(arn:(aws[a-zA-Z-]*)?:lambda:)?([a-z]{2}(-gov)?-[a-z]+-\d{1}:)?(\d{12}:)?(function:)?([a-zA-Z0-9-_.]+)(:(\$LATEST|[a-zA-Z0-9-_]+))?$`)
+
+// WithStrictARN rejects function names and partial ARNs in New, only full function ARNs are accepted.
+func WithStrictARN() Option {
+	return func(c *client) {
+		c.strictARN = true
+	}
+}
+
+// validateFunction accepts what the Invoke API accepts, or full ARNs only if strict.
+func validateFunction(function string, strict bool) error {
+	if strict {
+		if _, err := arn.Parse(function); err != nil {
+			return fmt.Errorf("arn.Parse[%s]: %w", function, err)
+		}
+		return nil
+	}
+
+	if !functionNamePattern.MatchString(function) {
+		return fmt.Errorf("invalid function name or ARN: %s", function)
+	}
+
+	return nil
+}
+
+// isFunctionARN reports whether s is a full function ARN.
+func isFunctionARN(s string) bool {
+	a, err := arn.Parse(s)
+	return err == nil && a.Service == "lambda" && strings.HasPrefix(a.Resource, "function:")
+}
+
+// FunctionARN returns the full ARN of the function, a function name or partial ARN given to New
+// is resolved with GetFunction on first use and cached. With WithResolver or WithARNRefresh it returns
+// the currently resolved ARN.
+func (c *client) FunctionARN(ctx context.Context) (string, error) {
+	if c.resolver != nil {
+		return c.resolver.Resolve(ctx)
+	}
+
+	if isFunctionARN(c.functionARN) {
+		return c.functionARN, nil
+	}
+
+	c.resolveMu.Lock()
+	defer c.resolveMu.Unlock()
+
+	if c.resolvedARN != "" {
+		return c.resolvedARN, nil
+	}
+
+	resolved, err := c.getFunctionARN(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	c.resolvedARN = resolved
+
+	return c.resolvedARN, nil
+}
+
+// getFunctionARN resolves the configured function with GetFunction.
+func (c *client) getFunctionARN(ctx context.Context) (string, error) {
+	output, err := c.cli.GetFunction(ctx, &lambda.GetFunctionInput{FunctionName: &c.functionARN}, c.apiOptions()...)
+	if err != nil {
+		return "", fmt.Errorf("cli.GetFunction: %w", c.accessDeniedError(err))
+	}
+
+	if output.Configuration == nil || output.Configuration.FunctionArn == nil {
+		return "", fmt.Errorf("output.Configuration.FunctionArn is nil")
+	}
+
+	return *output.Configuration.FunctionArn, nil
+}
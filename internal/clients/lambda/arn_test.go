@@ -0,0 +1,54 @@
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestValidateFunction(t *testing.T) {
+	tests := []struct {
+		function string
+		strict   bool
+		wantErr  bool
+	}{
+		{function: "my-function"},
+		{function: "my-function:live"},
+		{function: "000000000000:function:my-function"},
+		{function: testFunctionARN},
+		{function: testFunctionARN + ":$LATEST"},
+		{function: testFunctionARN, strict: true},
+		{function: "my-function", strict: true, wantErr: true},
+		{function: "my function", wantErr: true},
+		{function: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			err := validateFunction(tt.function, tt.strict)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClient_FunctionARN(t *testing.T) {
+	api := &fakeAPI{}
+
+	got, err := newClient(api, testFunctionARN).FunctionARN(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testFunctionARN, got)
+
+	cli := newClient(api, "my-function")
+
+	got, err = cli.FunctionARN(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testFunctionARN, got)
+
+	_, err = cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "my-function", *api.calls()[0].FunctionName, "names are passed to the Invoke API as is")
+}
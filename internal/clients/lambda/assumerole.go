@@ -0,0 +1,82 @@
+package lambda
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"sync"
+	"time"
+)
+
+// AssumeRole configures the IAM role assumed to invoke functions, e.g. owned by another account or team.
+type AssumeRole struct {
+	RoleARN string
+	// ExternalID is required by roles trusting third parties.
+	ExternalID string
+	// SessionName identifies the session in CloudTrail, "lambda-invoker" by default.
+	SessionName string
+	// Duration of the temporary credentials, 15 minutes by default.
+	Duration time.Duration
+	// STS assumes the role, by default an STS client is derived from the region, credentials
+	// and HTTP client of the *lambda.Client.
+	STS stscreds.AssumeRoleAPIClient
+}
+
+// WithAssumeRole invokes functions with temporary credentials of the role, they are cached
+// and refreshed shortly before expiry. Clients sharing the option share the credentials, e.g. in a Registry.
+func WithAssumeRole(role AssumeRole) Option {
+	if role.SessionName == "" {
+		role.SessionName = "lambda-invoker"
+	}
+
+	creds := &roleCredentials{role: role}
+
+	return func(c *client) {
+		c.roleCredentials = creds
+	}
+}
+
+// roleCredentials lazily creates the credentials cache from the options of the first API call.
+type roleCredentials struct {
+	role AssumeRole
+
+	once  sync.Once
+	cache *aws.CredentialsCache
+}
+
+// apiOption sets credentials of the role, managed by m if not nil.
+func (r *roleCredentials) apiOption(o *lambda.Options, m *CredentialManager) {
+	if m != nil {
+		o.Credentials = m.credentials(r.role, o)
+		return
+	}
+
+	r.once.Do(func() {
+		r.cache = aws.NewCredentialsCache(r.role.provider(o))
+	})
+
+	o.Credentials = r.cache
+}
+
+// provider returns an uncached provider of the role credentials, the default STS client is derived from o.
+func (r AssumeRole) provider(o *lambda.Options) aws.CredentialsProvider {
+	client := r.STS
+	if client == nil {
+		client = sts.New(sts.Options{
+			Region:      o.Region,
+			Credentials: o.Credentials,
+			HTTPClient:  o.HTTPClient,
+		})
+	}
+
+	return stscreds.NewAssumeRoleProvider(client, r.RoleARN, func(ao *stscreds.AssumeRoleOptions) {
+		ao.RoleSessionName = r.SessionName
+		if r.ExternalID != "" {
+			ao.ExternalID = &r.ExternalID
+		}
+		if r.Duration > 0 {
+			ao.Duration = r.Duration
+		}
+	})
+}
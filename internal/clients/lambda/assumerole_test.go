@@ -0,0 +1,103 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeSTS struct {
+	// expiresIn is the credentials lifetime, an hour by default.
+	expiresIn time.Duration
+	err       error
+
+	mu     sync.Mutex
+	inputs []*sts.AssumeRoleInput
+}
+
+func (f *fakeSTS) AssumeRole(_ context.Context, in *sts.AssumeRoleInput, _ ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.inputs = append(f.inputs, in)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	expiresIn := f.expiresIn
+	if expiresIn == 0 {
+		expiresIn = time.Hour
+	}
+
+	return &sts.AssumeRoleOutput{Credentials: &ststypes.Credentials{
+		AccessKeyId:     aws.String("ASIA" + strconv.Itoa(len(f.inputs))),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("token"),
+		Expiration:      aws.Time(time.Now().Add(expiresIn)),
+	}}, nil
+}
+
+func (f *fakeSTS) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.inputs)
+}
+
+func (f *fakeSTS) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.err = err
+}
+
+func TestWithAssumeRole(t *testing.T) {
+	api := &fakeAPI{}
+	stsClient := &fakeSTS{}
+
+	cli := newClient(api, testFunctionARN, WithAssumeRole(AssumeRole{
+		RoleARN:    "arn:aws:iam::111111111111:role/invoker",
+		ExternalID: "team-a",
+		Duration:   30 * time.Minute,
+		STS:        stsClient,
+	}))
+
+	for range 2 {
+		_, err := cli.Do(_ctx, Request{HTTPMethod: http.MethodGet, Path: "/"})
+		require.NoError(t, err)
+	}
+
+	for i := range 2 {
+		creds, err := api.options(i).Credentials.Retrieve(_ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "ASIA1", creds.AccessKeyID)
+		assert.Equal(t, "token", creds.SessionToken)
+	}
+
+	require.Len(t, stsClient.inputs, 1, "credentials are cached")
+	in := stsClient.inputs[0]
+	assert.Equal(t, "arn:aws:iam::111111111111:role/invoker", aws.ToString(in.RoleArn))
+	assert.Equal(t, "team-a", aws.ToString(in.ExternalId))
+	assert.Equal(t, "lambda-invoker", aws.ToString(in.RoleSessionName))
+	assert.Equal(t, int32(1800), aws.ToInt32(in.DurationSeconds))
+}
+
+func TestWithAssumeRole_DefaultSTS(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithRegion("eu-west-1"), WithAssumeRole(AssumeRole{RoleARN: "arn:aws:iam::111111111111:role/invoker"}))
+
+	_, err := cli.Do(_ctx, Request{HTTPMethod: http.MethodGet, Path: "/"})
+	require.NoError(t, err)
+
+	o := api.options(0)
+	assert.Equal(t, "eu-west-1", o.Region)
+	assert.IsType(t, &aws.CredentialsCache{}, o.Credentials)
+}
@@ -0,0 +1,29 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+)
+
+// AsyncTransport delivers the payloads of async invocations instead of the Invoke API with the Event invocation type,
+// e.g. SQSTransport. The returned id, such as the message ID, is reported as Response.RequestID.
+type AsyncTransport interface {
+	Send(ctx context.Context, payload []byte) (id string, err error)
+}
+
+// WithAsyncTransport sends async invocations through t, sync invocations still call the Invoke API.
+func WithAsyncTransport(t AsyncTransport) Option {
+	return func(c *client) {
+		c.asyncTransport = t
+	}
+}
+
+// sendAsync hands the payload of an async invocation over to the async transport.
+func (c *client) sendAsync(ctx context.Context, payload []byte) (*Response, error) {
+	id, err := c.asyncTransport.Send(ctx, payload)
+	if err != nil {
+		return nil, withKind(ErrorKindTransport, fmt.Errorf("asyncTransport.Send: %w", err))
+	}
+
+	return &Response{RequestID: id}, nil
+}
@@ -0,0 +1,144 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"net/http"
+	"strings"
+)
+
+// ErrUnauthorized is returned when an Authorizer denies the request.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Authorizer simulates an API Gateway Lambda authorizer, the returned context
+// populates RequestContext.Authorizer of the proxy event.
+type Authorizer interface {
+	Authorize(ctx context.Context, req Request) (map[string]any, error)
+}
+
+// AuthorizerFunc is an Authorizer implemented by a local function.
+type AuthorizerFunc func(ctx context.Context, req Request) (map[string]any, error)
+
+func (f AuthorizerFunc) Authorize(ctx context.Context, req Request) (map[string]any, error) {
+	return f(ctx, req)
+}
+
+// WithAuthorizer runs the authorizer before every invocation of the client, matching production request shapes
+// for handlers relying on authorizer claims. To emulate API Gateway in front of the function, where denied requests
+// never reach it, set HTTPHandlerConfig.Authorizer instead.
+func WithAuthorizer(a Authorizer) Option {
+	return func(c *client) {
+		c.authorizer = a
+	}
+}
+
+type lambdaAuthorizer struct {
+	cli         lambdaAPI
+	functionARN string
+}
+
+// NewLambdaAuthorizer returns an Authorizer invoking a deployed REQUEST type authorizer function, given as
+// the Invoke API accepts it. Its context and principalId become the authorizer context, a policy without
+// Allow statements results in ErrUnauthorized.
+func NewLambdaAuthorizer(cli *lambda.Client, functionARN string) (Authorizer, error) {
+	if cli == nil {
+		return nil, fmt.Errorf("lambda.NewFromConfig returned nil")
+	}
+
+	if err := validateFunction(functionARN, false); err != nil {
+		return nil, err
+	}
+
+	return &lambdaAuthorizer{
+		cli:         cli,
+		functionARN: functionARN,
+	}, nil
+}
+
+func (a *lambdaAuthorizer) Authorize(ctx context.Context, req Request) (map[string]any, error) {
+	payload, err := json.Marshal(events.APIGatewayCustomAuthorizerRequestTypeRequest{
+		Type:       "REQUEST",
+		MethodArn:  a.methodARN(req),
+		Resource:   req.Path,
+		Path:       req.Path,
+		HTTPMethod: req.HTTPMethod,
+		Headers:    req.Headers,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	output, err := a.cli.Invoke(ctx, &lambda.InvokeInput{
+		FunctionName:   pointer.To(a.functionARN),
+		InvocationType: types.InvocationTypeRequestResponse,
+		LogType:        types.LogTypeNone,
+		Payload:        payload,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cli.Invoke: %w", err)
+	}
+
+	if output.FunctionError != nil {
+		return nil, fmt.Errorf("output.FunctionError: %s", *output.FunctionError)
+	}
+
+	if output.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("output.StatusCode: %d", output.StatusCode)
+	}
+
+	var r events.APIGatewayCustomAuthorizerResponse
+	if err := json.Unmarshal(output.Payload, &r); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	if !allowed(r.PolicyDocument) {
+		return nil, fmt.Errorf("%w: principal %s", ErrUnauthorized, r.PrincipalID)
+	}
+
+	authorizerContext := make(map[string]any, len(r.Context)+1)
+	for k, v := range r.Context {
+		authorizerContext[k] = v
+	}
+	authorizerContext["principalId"] = r.PrincipalID
+
+	return authorizerContext, nil
+}
+
+// methodARN mimics the execute-api ARN API Gateway passes to authorizers, region and account are empty
+// unless the authorizer is given as a full ARN.
+func (a *lambdaAuthorizer) methodARN(req Request) string {
+	fn, err := arn.Parse(a.functionARN)
+	if err != nil {
+		fn.Partition = "aws"
+	}
+
+	return arn.ARN{
+		Partition: fn.Partition,
+		Service:   "execute-api",
+		Region:    fn.Region,
+		AccountID: fn.AccountID,
+		Resource:  "local/local/" + req.HTTPMethod + req.Path,
+	}.String()
+}
+
+// allowed reports whether the policy has an Allow statement and no Deny ones.
+func allowed(policy events.APIGatewayCustomAuthorizerPolicy) bool {
+	allow := false
+	for _, s := range policy.Statement {
+		switch {
+		case strings.EqualFold(s.Effect, "Deny"):
+			return false
+		case strings.EqualFold(s.Effect, "Allow"):
+			allow = true
+		}
+	}
+
+	return allow
+}
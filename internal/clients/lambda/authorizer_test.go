@@ -0,0 +1,81 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestWithAuthorizer(t *testing.T) {
+	api := &fakeAPI{}
+
+	cli := newClient(api, testFunctionARN, WithAuthorizer(AuthorizerFunc(func(ctx context.Context, req Request) (map[string]any, error) {
+		return map[string]any{"tenantId": "t1"}, nil
+	})))
+
+	_, err := cli.Invoke(_ctx, "GET", "/orders", nil)
+	require.NoError(t, err)
+
+	req := proxyRequest(api.calls()[0])
+	assert.Equal(t, map[string]any{"tenantId": "t1"}, req.RequestContext.Authorizer)
+}
+
+func TestLambdaAuthorizer(t *testing.T) {
+	tests := []struct {
+		name    string
+		effect  string
+		wantErr error
+	}{
+		{name: "allow", effect: "Allow"},
+		{name: "deny", effect: "Deny", wantErr: ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+				var req events.APIGatewayCustomAuthorizerRequestTypeRequest
+				require.NoError(t, json.Unmarshal(in.Payload, &req))
+				assert.Equal(t, "arn:aws:execute-api:eu-central-1:000000000000:local/local/GET/orders", req.MethodArn)
+
+				payload, err := json.Marshal(events.APIGatewayCustomAuthorizerResponse{
+					PrincipalID: "user-1",
+					PolicyDocument: events.APIGatewayCustomAuthorizerPolicy{
+						Version:   "2012-10-17",
+						Statement: []events.IAMPolicyStatement{{Action: []string{"execute-api:Invoke"}, Effect: tt.effect, Resource: []string{req.MethodArn}}},
+					},
+					Context: map[string]any{"role": "admin"},
+				})
+				require.NoError(t, err)
+				return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, nil
+			}}
+
+			a := &lambdaAuthorizer{cli: api, functionARN: testFunctionARN}
+
+			authorizerContext, err := a.Authorize(_ctx, Request{HTTPMethod: "GET", Path: "/orders"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, map[string]any{"role": "admin", "principalId": "user-1"}, authorizerContext)
+		})
+	}
+}
+
+func TestNewLambdaAuthorizer(t *testing.T) {
+	cli := lambda.New(lambda.Options{Region: "eu-central-1"})
+
+	_, err := NewLambdaAuthorizer(cli, "authorizer")
+	require.NoError(t, err, "function names are accepted")
+
+	_, err = NewLambdaAuthorizer(cli, "not a function")
+	require.Error(t, err)
+
+	a := &lambdaAuthorizer{functionARN: "authorizer"}
+	assert.Equal(t, "arn:aws:execute-api:::local/local/GET/orders", a.methodARN(Request{HTTPMethod: "GET", Path: "/orders"}))
+}
@@ -0,0 +1,107 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"time"
+)
+
+// ReplyChannel carries replies of async invocations keyed by correlation ID, e.g. SQSReplyQueue or DynamoDBReplyTable.
+type ReplyChannel interface {
+	Send(ctx context.Context, correlationID string, payload []byte) error
+	// Receive blocks until the reply of the correlation ID arrives or ctx is done.
+	Receive(ctx context.Context, correlationID string) ([]byte, error)
+}
+
+// asyncReply is the payload sent by ReplyHandler, Error is set if the handler failed.
+type asyncReply struct {
+	Response events.APIGatewayProxyResponse `json:"response"`
+	Error    *messages.InvokeResponse_Error `json:"error,omitempty"`
+}
+
+// InvokeAsyncAndAwait invokes req asynchronously with a correlation ID, taken from the context or generated,
+// and waits up to timeout, unless 0, for the reply sent by ReplyHandler on the function side. Handler errors are
+// returned as *InvocationError, non-2xx responses are returned with ErrUnexpectedStatus. The proxy envelope is
+// required to carry the CorrelationIDHeader header.
+func InvokeAsyncAndAwait(ctx context.Context, client Client, replies ReplyChannel, timeout time.Duration, req Request, opts ...Option) (*Response, error) {
+	id, ok := CorrelationIDFromContext(ctx)
+	if !ok {
+		id = randomUUID(newRandomRand())
+		ctx = ContextWithCorrelationID(ctx, id)
+	}
+
+	req.Headers = withHeader(req.Headers, CorrelationIDHeader, id)
+	req.Async = true
+
+	if _, err := client.Do(ctx, req, opts...); err != nil {
+		return nil, fmt.Errorf("client.Do: %w", err)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	payload, err := replies.Receive(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("replies.Receive[%s]: %w", id, err)
+	}
+
+	var reply asyncReply
+	if err := json.Unmarshal(payload, &reply); err != nil {
+		return nil, withKind(ErrorKindMarshal, fmt.Errorf("json.Unmarshal: %w", err))
+	}
+
+	if reply.Error != nil {
+		return nil, &InvocationError{FunctionError: "Unhandled", Message: reply.Error.Message, Type: reply.Error.Type}
+	}
+
+	resp := &Response{
+		StatusCode:      reply.Response.StatusCode,
+		Headers:         reply.Response.Headers,
+		Body:            reply.Response.Body,
+		IsBase64Encoded: reply.Response.IsBase64Encoded,
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp, fmt.Errorf("reply: %w", &ErrUnexpectedStatus{Code: resp.StatusCode, Body: resp.Body})
+	}
+
+	return resp, nil
+}
+
+// ReplyHandler wraps the function side handler to send the response or the error of requests carrying
+// the CorrelationIDHeader header to replies, see InvokeAsyncAndAwait. The invocation fails if the reply is not sent,
+// so that Lambda retries it.
+func ReplyHandler(replies ReplyChannel) func(next Handler) Handler {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			resp, err := next(ctx, req)
+
+			id, ok := headerValue(req.Headers, CorrelationIDHeader)
+			if !ok {
+				return resp, err
+			}
+
+			reply := asyncReply{Response: resp}
+			if err != nil {
+				reply.Error = &messages.InvokeResponse_Error{Message: err.Error(), Type: errorTypeName(err)}
+			}
+
+			payload, marshalErr := json.Marshal(reply)
+			if marshalErr != nil {
+				return resp, fmt.Errorf("json.Marshal: %w", marshalErr)
+			}
+
+			if sendErr := replies.Send(ctx, id, payload); sendErr != nil {
+				return resp, fmt.Errorf("replies.Send[%s]: %w", id, sendErr)
+			}
+
+			return resp, err
+		}
+	}
+}
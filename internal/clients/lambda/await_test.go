@@ -0,0 +1,84 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryReplies is an in-memory ReplyChannel.
+type memoryReplies struct {
+	mu      sync.Mutex
+	replies map[string][]byte
+}
+
+func (m *memoryReplies) Send(_ context.Context, correlationID string, payload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.replies == nil {
+		m.replies = make(map[string][]byte)
+	}
+	m.replies[correlationID] = payload
+
+	return nil
+}
+
+func (m *memoryReplies) Receive(ctx context.Context, correlationID string) ([]byte, error) {
+	for {
+		m.mu.Lock()
+		payload, ok := m.replies[correlationID]
+		m.mu.Unlock()
+		if ok {
+			return payload, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestInvokeAsyncAndAwait(t *testing.T) {
+	replies := &memoryReplies{}
+	handler := ReplyHandler(replies)(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		switch req.Path {
+		case "/error":
+			return events.APIGatewayProxyResponse{}, errors.New("boom")
+		case "/missing":
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusNotFound, Body: "not found"}, nil
+		}
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusCreated, Body: req.Body}, nil
+	})
+	cli := NewInProcess(handler)
+
+	resp, err := InvokeAsyncAndAwait(ContextWithCorrelationID(_ctx, "corr-1"), cli, replies, time.Second, Request{HTTPMethod: "POST", Path: "/orders", Body: []byte(`{"id":"1"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, `{"id":"1"}`, resp.Body)
+	assert.Contains(t, replies.replies, "corr-1")
+
+	_, err = InvokeAsyncAndAwait(_ctx, cli, replies, time.Second, Request{HTTPMethod: "POST", Path: "/error"})
+	var ie *InvocationError
+	require.ErrorAs(t, err, &ie)
+	assert.Equal(t, "boom", ie.Message)
+
+	resp, err = InvokeAsyncAndAwait(_ctx, cli, replies, time.Second, Request{HTTPMethod: "GET", Path: "/missing"})
+	var ues *ErrUnexpectedStatus
+	require.ErrorAs(t, err, &ues)
+	assert.Equal(t, "not found", resp.Body)
+
+	noReply := NewInProcess(func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+	_, err = InvokeAsyncAndAwait(_ctx, noReply, replies, 10*time.Millisecond, Request{HTTPMethod: "GET", Path: "/"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,221 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"math"
+	"slices"
+	"sync"
+)
+
+// BalanceStrategy selects the target of each invocation of NewBalanced clients.
+type BalanceStrategy int
+
+const (
+	// BalanceRoundRobin cycles through targets, each is picked Weight times per cycle, interleaved.
+	BalanceRoundRobin BalanceStrategy = iota
+	// BalanceLeastInflight picks the target with the fewest in-flight invocations relative to its weight.
+	BalanceLeastInflight
+	// BalanceWeightedRandom picks targets at random with probabilities proportional to their weights.
+	BalanceWeightedRandom
+)
+
+// WeightedTarget is a function name, a partial or a full ARN, and its weight, targets with weight 0 get no traffic.
+type WeightedTarget struct {
+	Function string
+	Weight   int
+}
+
+// NewBalanced returns a client spreading invocations over the targets, e.g. shards of a backend or the old and
+// the new implementation during a migration. Each target gets its own client built with the options, so that
+// metrics, events, caching, preflight and costs are of the picked target. HealthCheck checks the targets with
+// a positive weight, Costs sums all targets and FunctionARN resolves the first one.
+func NewBalanced(cli *lambda.Client, targets []WeightedTarget, strategy BalanceStrategy, opts ...Option) (Client, error) {
+	if cli == nil {
+		return nil, fmt.Errorf("lambda.NewFromConfig returned nil")
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("targets are empty")
+	}
+
+	var total int
+	for _, t := range targets {
+		if t.Weight < 0 {
+			return nil, fmt.Errorf("target[%s]: negative weight %d", t.Function, t.Weight)
+		}
+		total += t.Weight
+	}
+
+	if total == 0 {
+		return nil, fmt.Errorf("total weight is 0")
+	}
+
+	c := newBalanced(cli, targets, strategy, opts...)
+	for _, t := range c.balancer.clients {
+		if err := validateFunction(t.functionARN, t.strictARN); err != nil {
+			return nil, fmt.Errorf("target[%s]: validateFunction: %w", t.functionARN, err)
+		}
+		if err := t.validateAccount(); err != nil {
+			return nil, fmt.Errorf("target[%s]: c.validateAccount: %w", t.functionARN, err)
+		}
+	}
+
+	return c, nil
+}
+
+// newBalanced returns the client of the first target dispatching invocations to the clients of the picked targets.
+func newBalanced(cli lambdaAPI, targets []WeightedTarget, strategy BalanceStrategy, opts ...Option) *client {
+	b := &balancer{
+		strategy: strategy,
+		targets:  targets,
+		clients:  make([]*client, len(targets)),
+		current:  make([]int, len(targets)),
+		inflight: make([]int, len(targets)),
+	}
+	for i, t := range targets {
+		b.clients[i] = newClient(cli, t.Function, opts...)
+	}
+
+	c := newClient(cli, targets[0].Function, append(slices.Clone(opts), withBalancer(b))...)
+	b.rand = c.rand
+
+	return c
+}
+
+func withBalancer(b *balancer) Option {
+	return func(c *client) {
+		c.balancer = b
+	}
+}
+
+// balancer picks the target client of each invocation.
+type balancer struct {
+	strategy BalanceStrategy
+	targets  []WeightedTarget
+	clients  []*client
+	rand     *Rand
+
+	mu       sync.Mutex
+	current  []int
+	inflight []int
+}
+
+// do passes req to the client of the picked target with the call config of the balanced client.
+func (b *balancer) do(ctx context.Context, req *Request, cfg callConfig) (*Response, error) {
+	i := b.acquire()
+	defer b.release(i)
+
+	t := b.clients[i]
+
+	return t.do(t.withCallConfig(ctx, cfg), req)
+}
+
+// healthCheck checks the targets getting traffic and returns their joined errors.
+func (b *balancer) healthCheck(ctx context.Context, cfg callConfig) error {
+	var errs []error
+	for i, t := range b.clients {
+		if b.targets[i].Weight == 0 {
+			continue
+		}
+		if err := t.HealthCheck(t.withCallConfig(ctx, cfg)); err != nil {
+			errs = append(errs, fmt.Errorf("target[%s]: %w", t.functionARN, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (b *balancer) costs() CostStats {
+	var total CostStats
+	for _, t := range b.clients {
+		stats := t.Costs()
+		total.Invocations += stats.Invocations
+		total.BilledDuration += stats.BilledDuration
+		total.GBSeconds += stats.GBSeconds
+		total.EstimatedCost += stats.EstimatedCost
+	}
+
+	return total
+}
+
+func (b *balancer) close() {
+	for _, t := range b.clients {
+		t.Close()
+	}
+}
+
+func (b *balancer) acquire() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var i int
+	switch b.strategy {
+	case BalanceLeastInflight:
+		i = b.leastInflight()
+	case BalanceWeightedRandom:
+		i = b.weightedRandom()
+	default:
+		i = b.roundRobin()
+	}
+
+	b.inflight[i]++
+
+	return i
+}
+
+func (b *balancer) release(i int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inflight[i]--
+}
+
+// roundRobin is the smooth weighted round-robin of nginx, e.g. weights 2:1 pick a, b, a.
+func (b *balancer) roundRobin() int {
+	best, total := -1, 0
+	for i, t := range b.targets {
+		b.current[i] += t.Weight
+		total += t.Weight
+		if t.Weight > 0 && (best < 0 || b.current[i] > b.current[best]) {
+			best = i
+		}
+	}
+
+	b.current[best] -= total
+
+	return best
+}
+
+func (b *balancer) leastInflight() int {
+	best, bestLoad := -1, math.Inf(1)
+	for i, t := range b.targets {
+		if t.Weight == 0 {
+			continue
+		}
+		if load := float64(b.inflight[i]) / float64(t.Weight); load < bestLoad {
+			best, bestLoad = i, load
+		}
+	}
+
+	return best
+}
+
+func (b *balancer) weightedRandom() int {
+	var total int
+	for _, t := range b.targets {
+		total += t.Weight
+	}
+
+	n := b.rand.IntN(total)
+	for i, t := range b.targets {
+		if n < t.Weight {
+			return i
+		}
+		n -= t.Weight
+	}
+
+	return len(b.targets) - 1
+}
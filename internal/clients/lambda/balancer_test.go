@@ -0,0 +1,133 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func balancedCalls(api *fakeAPI) map[string]int {
+	counts := make(map[string]int)
+	for _, in := range api.calls() {
+		counts[*in.FunctionName]++
+	}
+
+	return counts
+}
+
+func TestWithBalancer_RoundRobin(t *testing.T) {
+	api := &fakeAPI{}
+	targets := []WeightedTarget{{Function: "orders-v1", Weight: 2}, {Function: "orders-v2", Weight: 1}, {Function: "orders-v3"}}
+	cli := newBalanced(api, targets, BalanceRoundRobin)
+
+	for range 6 {
+		_, err := cli.Invoke(_ctx, "GET", "/", nil)
+		require.NoError(t, err)
+	}
+
+	var order []string
+	for _, in := range api.calls() {
+		order = append(order, *in.FunctionName)
+	}
+	assert.Equal(t, []string{"orders-v1", "orders-v2", "orders-v1", "orders-v1", "orders-v2", "orders-v1"}, order)
+}
+
+func TestWithBalancer_WeightedRandom(t *testing.T) {
+	api := &fakeAPI{}
+	targets := []WeightedTarget{{Function: "orders-v1", Weight: 9}, {Function: "orders-v2", Weight: 1}}
+	cli := newBalanced(api, targets, BalanceWeightedRandom, WithRand(NewRand(1)))
+
+	for range 1000 {
+		_, err := cli.Invoke(_ctx, "GET", "/", nil)
+		require.NoError(t, err)
+	}
+
+	assert.InDelta(t, 100, balancedCalls(api)["orders-v2"], 30)
+}
+
+func TestWithBalancer_LeastInflight(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		if *in.FunctionName == "orders-v1" {
+			started <- struct{}{}
+			<-release
+		}
+		return proxyOutput(in, 200, "ok"), nil
+	}}
+	targets := []WeightedTarget{{Function: "orders-v1", Weight: 1}, {Function: "orders-v2", Weight: 1}}
+	cli := newBalanced(api, targets, BalanceLeastInflight)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := cli.Invoke(_ctx, "GET", "/slow", nil)
+		assert.NoError(t, err)
+	}()
+	<-started
+
+	for range 3 {
+		_, err := cli.Invoke(_ctx, "GET", "/", nil)
+		require.NoError(t, err)
+	}
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, map[string]int{"orders-v1": 1, "orders-v2": 3}, balancedCalls(api), "the busy target is avoided")
+}
+
+func TestNewBalanced_PerTargetClients(t *testing.T) {
+	api := &fakeAPI{}
+	targets := []WeightedTarget{{Function: "orders-v1", Weight: 1}, {Function: "orders-v2", Weight: 1}}
+
+	var mu sync.Mutex
+	var recorded []string
+	recorder := metricsRecorderFunc(func(m InvocationMetrics) {
+		mu.Lock()
+		defer mu.Unlock()
+		recorded = append(recorded, m.FunctionARN)
+	})
+	cli := newBalanced(api, targets, BalanceRoundRobin, WithMetrics(recorder), WithCache(Cache{TTL: time.Minute}))
+
+	for range 4 {
+		_, err := cli.Invoke(_ctx, "GET", "/orders", nil)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"orders-v1", "orders-v2", "orders-v1", "orders-v2"}, recorded, "metrics are of the picked target")
+	assert.Equal(t, map[string]int{"orders-v1": 1, "orders-v2": 1}, balancedCalls(api), "responses are cached per target")
+}
+
+func TestNewBalanced_HealthCheck(t *testing.T) {
+	api := &preflightAPI{state: types.StateActive}
+	api.invoke = func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return &lambda.InvokeOutput{StatusCode: http.StatusNoContent}, nil
+	}
+	targets := []WeightedTarget{{Function: "orders-v1", Weight: 1}, {Function: "orders-v2", Weight: 1}, {Function: "orders-v3"}}
+	cli := newBalanced(api, targets, BalanceRoundRobin, WithHealthCheck(HealthCheckConfig{DryRun: true}))
+
+	require.NoError(t, cli.HealthCheck(_ctx))
+
+	assert.Equal(t, map[string]int{"orders-v1": 1, "orders-v2": 1}, balancedCalls(&api.fakeAPI), "targets without traffic are not checked")
+	assert.EqualValues(t, 2, api.checks.Load())
+}
+
+func TestNewBalanced_Invalid(t *testing.T) {
+	cli := lambda.New(lambda.Options{})
+
+	_, err := NewBalanced(cli, nil, BalanceRoundRobin)
+	require.Error(t, err)
+
+	_, err = NewBalanced(cli, []WeightedTarget{{Function: "orders"}}, BalanceRoundRobin)
+	require.Error(t, err, "total weight is 0")
+
+	_, err = NewBalanced(cli, []WeightedTarget{{Function: "orders", Weight: 1}, {Function: "bad name!", Weight: 1}}, BalanceRoundRobin)
+	require.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package lambda
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Request is a single proxy invocation, see Client.Invoke.
+type Request struct {
+	HTTPMethod string
+	Path       string
+	// Query is sent as QueryStringParameters, with the last value of each parameter, and MultiValueQueryStringParameters.
+	Query   url.Values
+	Headers map[string]string
+	// MultiValueHeaders holds headers set multiple times, they are sent as MultiValueHeaders along with Headers,
+	// which get the last value of each, as API Gateway does.
+	MultiValueHeaders map[string][]string
+	Body              []byte
+	// IsBase64Encoded reports that Body is already base64 encoded.
+	IsBase64Encoded bool
+	// Async selects the Event invocation type.
+	Async bool
+}
+
+// Response is the APIGatewayProxyResponse returned by the function.
+type Response struct {
+	StatusCode        int
+	Headers           map[string]string
+	MultiValueHeaders map[string][]string
+	Body              string
+	IsBase64Encoded   bool
+	// Data is the decoded Body of a base64 encoded response, ContentType declares its type. Bytes returns the
+	// body whether or not it is base64 encoded.
+	Data []byte
+	// RequestID is the AWS request ID of the invocation.
+	RequestID string
+	// ExecutedVersion is the function version which handled a sync invocation.
+	ExecutedVersion string
+	// LogResult is the tail of the execution log of a sync invocation, see WithLogType.
+	LogResult string
+	// Stats are parsed from the REPORT line of the log tail, it is nil unless the log tail is requested.
+	Stats *ExecutionStats
+	// ColdStart reports that the invocation initialised a new instance, which took InitDuration.
+	// It is only detected when the log tail is requested, see WithLogType.
+	ColdStart    bool
+	InitDuration time.Duration
+	// EstimatedCost is in USD, see WithCostEstimation.
+	EstimatedCost float64
+}
+
+// Result is the outcome of a single Request, Err is set if the invocation failed.
+type Result struct {
+	// Index is the position of the Request in the batch or on the streaming input channel.
+	Index int
+	Body  string
+	Err   error
+}
+
+// InvokeBatch synchronously invokes requests using at most concurrency parallel invocations.
+// results are in the same order as requests, a failed invocation is reported in its Result.Err
+// and does not fail the batch. Use InvokeSeq to consume results as they complete.
+func (c *client) InvokeBatch(ctx context.Context, requests []Request, concurrency int) ([]Result, error) {
+	if err := validateConcurrency(concurrency); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(requests))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(concurrency, len(requests)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = c.invokeRequest(ctx, i, requests[i])
+			}
+		}()
+	}
+
+	for i := range requests {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+
+	return results, nil
+}
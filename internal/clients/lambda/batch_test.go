@@ -0,0 +1,67 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInvokeBatch(t *testing.T) {
+	var inflight, maxInflight atomic.Int32
+
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			m := maxInflight.Load()
+			if n <= m || maxInflight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		req := proxyRequest(in)
+		if req.Path == "/fail" {
+			return nil, errors.New("boom")
+		}
+		return proxyOutput(in, http.StatusOK, req.Path), nil
+	}}
+
+	cli := newClient(api, testFunctionARN)
+
+	requests := []Request{
+		{HTTPMethod: "GET", Path: "/a"},
+		{HTTPMethod: "GET", Path: "/fail"},
+		{HTTPMethod: "GET", Path: "/c"},
+		{HTTPMethod: "GET", Path: "/d"},
+		{HTTPMethod: "GET", Path: "/e"},
+	}
+
+	results, err := cli.InvokeBatch(_ctx, requests, 2)
+	require.NoError(t, err)
+	require.Len(t, results, len(requests))
+
+	for i, r := range results {
+		if requests[i].Path == "/fail" {
+			assert.Error(t, r.Err)
+			continue
+		}
+		require.NoError(t, r.Err)
+		assert.Equal(t, requests[i].Path, r.Body)
+	}
+
+	assert.LessOrEqual(t, maxInflight.Load(), int32(2))
+}
+
+func TestInvokeBatch_InvalidConcurrency(t *testing.T) {
+	cli := newClient(&fakeAPI{}, testFunctionARN)
+
+	_, err := cli.InvokeBatch(_ctx, nil, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+)
+
+// InvokeBinary synchronously invokes the function with a binary body, it is base64 encoded in the proxy request.
+// The response body is decoded if the function declares it base64 encoded.
+func (c *client) InvokeBinary(ctx context.Context, httpMethod, path string, body []byte, opts ...Option) ([]byte, error) {
+	ctx, err := c.withCallOptions(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("invoke[sync]: %w", err)
+	}
+
+	resp, err := c.do(ctx, &Request{
+		HTTPMethod:      httpMethod,
+		Path:            path,
+		Body:            []byte(base64.StdEncoding.EncodeToString(body)),
+		IsBase64Encoded: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invoke[sync]: %w", err)
+	}
+
+	decoded, err := resp.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("resp.Bytes: %w", err)
+	}
+
+	return decoded, nil
+}
+
+// Bytes returns the body, decoded if the function declared it base64 encoded.
+func (r *Response) Bytes() ([]byte, error) {
+	if r.IsBase64Encoded && r.Data != nil {
+		return r.Data, nil
+	}
+
+	return decodeBody(r.Body, r.IsBase64Encoded)
+}
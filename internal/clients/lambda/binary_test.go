@@ -0,0 +1,74 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestInvokeBinary(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		req := proxyRequest(in)
+		// echo the request body as is
+		payload, err := json.Marshal(events.APIGatewayProxyResponse{
+			StatusCode:      http.StatusOK,
+			Body:            req.Body,
+			IsBase64Encoded: req.IsBase64Encoded,
+		})
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, err
+	}}
+	cli := newClient(api, testFunctionARN)
+
+	got, err := cli.InvokeBinary(_ctx, "POST", "/images", png)
+	require.NoError(t, err)
+	assert.Equal(t, png, got)
+
+	req := proxyRequest(api.calls()[0])
+	assert.True(t, req.IsBase64Encoded)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(png), req.Body)
+}
+
+func TestResponse_Bytes(t *testing.T) {
+	got, err := (&Response{Body: "plain"}).Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("plain"), got)
+
+	_, err = (&Response{Body: "%%%", IsBase64Encoded: true}).Bytes()
+	assert.Error(t, err)
+}
+
+func TestDo_DecodesBinaryResponse(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		payload, err := json.Marshal(events.APIGatewayProxyResponse{
+			StatusCode:      http.StatusOK,
+			Headers:         map[string]string{"Content-Type": "image/png"},
+			Body:            base64.StdEncoding.EncodeToString(png),
+			IsBase64Encoded: true,
+		})
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, err
+	}}
+	cli := newClient(api, testFunctionARN)
+
+	resp, err := cli.Do(_ctx, Request{HTTPMethod: http.MethodGet, Path: "/logo.png"})
+	require.NoError(t, err)
+	assert.Equal(t, png, resp.Data)
+	assert.Equal(t, "image/png", resp.ContentType())
+
+	api.invoke = func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		payload, err := json.Marshal(events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "%%%", IsBase64Encoded: true})
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, err
+	}
+
+	_, err = cli.Do(_ctx, Request{HTTPMethod: http.MethodGet, Path: "/logo.png"})
+	assert.Equal(t, ErrorKindMarshal, KindOf(err))
+}
@@ -0,0 +1,89 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"strconv"
+	"time"
+)
+
+// BudgetHeader carries the milliseconds left until the caller's context deadline, see WithBudgetPropagation.
+const BudgetHeader = "X-Time-Budget-Ms"
+
+// ErrInsufficientBudget is matched by InsufficientBudgetError.
+var ErrInsufficientBudget = errors.New("insufficient time budget")
+
+// InsufficientBudgetError is returned without invoking the function when less than the floor of
+// WithBudgetPropagation remains until the context deadline.
+type InsufficientBudgetError struct {
+	Remaining time.Duration
+	Floor     time.Duration
+}
+
+func (e *InsufficientBudgetError) Error() string {
+	return fmt.Sprintf("insufficient time budget: %s remaining, floor %s", e.Remaining, e.Floor)
+}
+
+func (e *InsufficientBudgetError) Unwrap() error {
+	return ErrInsufficientBudget
+}
+
+// WithBudgetPropagation passes the time left until the context deadline in BudgetHeader, so that a chain of
+// Lambda-to-Lambda calls can shed work when the budget runs out, see BudgetHandler. Invocations, including
+// retries, are refused with InsufficientBudgetError if less than floor remains, floor 0 only propagates.
+// Contexts without a deadline are not limited.
+func WithBudgetPropagation(floor time.Duration) Option {
+	return func(c *client) {
+		c.budgetPropagation = true
+		c.budgetFloor = floor
+	}
+}
+
+// checkBudget refuses the invocation if less than the floor remains.
+func (c *client) checkBudget(ctx context.Context) error {
+	deadline, ok := ctx.Deadline()
+	if !ok || c.budgetFloor <= 0 {
+		return nil
+	}
+
+	if remaining := time.Until(deadline); remaining < c.budgetFloor {
+		return withKind(ErrorKindContext, &InsufficientBudgetError{Remaining: remaining, Floor: c.budgetFloor})
+	}
+
+	return nil
+}
+
+// withBudget returns headers with BudgetHeader set if ctx has a deadline, the caller's map is not modified.
+func withBudget(ctx context.Context, headers map[string]string) map[string]string {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return headers
+	}
+
+	remaining := max(time.Until(deadline).Milliseconds(), 0)
+
+	return withHeader(headers, BudgetHeader, strconv.FormatInt(remaining, 10))
+}
+
+// BudgetHandler wraps the function side handler to bound its context by BudgetHeader, so that clients created
+// WithBudgetPropagation inside the handler pass the remaining budget on. Invalid headers are ignored.
+func BudgetHandler(next Handler) Handler {
+	return func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		value, ok := headerValue(req.Headers, BudgetHeader)
+		if !ok {
+			return next(ctx, req)
+		}
+
+		ms, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || ms < 0 {
+			return next(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, time.Duration(ms)*time.Millisecond)
+		defer cancel()
+
+		return next(ctx, req)
+	}
+}
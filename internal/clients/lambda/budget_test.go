@@ -0,0 +1,58 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWithBudgetPropagation(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithBudgetPropagation(100*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(_ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := cli.Invoke(ctx, "GET", "/", nil)
+	require.NoError(t, err)
+
+	ms, err := strconv.Atoi(proxyRequest(api.calls()[0]).Headers[BudgetHeader])
+	require.NoError(t, err)
+	assert.InDelta(t, 10000, ms, 1000)
+
+	_, err = cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+	assert.NotContains(t, proxyRequest(api.calls()[1]).Headers, BudgetHeader)
+
+	ctx, cancel = context.WithTimeout(_ctx, 50*time.Millisecond)
+	defer cancel()
+
+	_, err = cli.Invoke(ctx, "GET", "/", nil)
+	var budgetErr *InsufficientBudgetError
+	require.ErrorAs(t, err, &budgetErr)
+	assert.Equal(t, 100*time.Millisecond, budgetErr.Floor)
+	assert.Equal(t, ErrorKindContext, KindOf(err))
+	assert.Len(t, api.calls(), 2)
+}
+
+func TestBudgetHandler(t *testing.T) {
+	var deadline time.Time
+	handler := BudgetHandler(func(ctx context.Context, _ events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		deadline, _ = ctx.Deadline()
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	_, err := handler(_ctx, events.APIGatewayProxyRequest{Headers: map[string]string{"x-time-budget-ms": "2000"}})
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(2*time.Second), deadline, 500*time.Millisecond)
+
+	deadline = time.Time{}
+	_, err = handler(_ctx, events.APIGatewayProxyRequest{Headers: map[string]string{BudgetHeader: "soon"}})
+	require.NoError(t, err)
+	assert.True(t, deadline.IsZero())
+}
@@ -0,0 +1,227 @@
+package lambda
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"hash"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore stores responses of Cache, implement it to share a cache between processes.
+type CacheStore interface {
+	Get(key string) (*Response, bool)
+	Set(key string, resp *Response, ttl time.Duration)
+}
+
+// Cache caches successful sync responses keyed by function, qualifier, method, path, query and body hash,
+// the VaryHeaders and the call scoped request context, for read-heavy callers of idempotent functions.
+// The result of WithAuthorizer is expected to derive from the VaryHeaders.
+type Cache struct {
+	// Store is an in-memory LRU of 1000 entries by default.
+	Store CacheStore
+	// TTL is the default time to live, override it per call with WithCacheTTL.
+	TTL time.Duration
+	// Methods are the cached HTTP methods, GET and HEAD by default.
+	Methods []string
+	// VaryHeaders are the request headers distinguishing cached responses, Authorization and Cookie by default.
+	VaryHeaders []string
+}
+
+// defaultVaryHeaders keep responses of different callers apart.
+var defaultVaryHeaders = []string{"Authorization", "Cookie"}
+
+// WithCache caches responses, it is installed as an interceptor at its position among WithInterceptors options.
+func WithCache(cache Cache) Option {
+	if cache.Store == nil {
+		cache.Store = NewLRUCache(1000)
+	}
+	if len(cache.Methods) == 0 {
+		cache.Methods = []string{http.MethodGet, http.MethodHead}
+	}
+	if len(cache.VaryHeaders) == 0 {
+		cache.VaryHeaders = defaultVaryHeaders
+	}
+
+	return func(c *client) {
+		c.call.cacheTTL = cache.TTL
+		c.interceptors = append(c.interceptors, c.cacheInterceptor(cache))
+	}
+}
+
+// WithCacheTTL overrides the time to live of Cache, zero disables caching.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(c *client) {
+		c.call.cacheTTL = ttl
+	}
+}
+
+func (c *client) cacheInterceptor(cache Cache) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		cfg := c.callConfig(ctx)
+		if req.Async || isWarmPing(ctx) || cfg.cacheTTL <= 0 || !slices.Contains(cache.Methods, req.HTTPMethod) {
+			return next(ctx, req)
+		}
+
+		key := c.varyKey(cfg, req, cache.VaryHeaders)
+		if resp, ok := cache.Store.Get(key); ok {
+			return cloneResponse(resp), nil
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		cache.Store.Set(key, cloneResponse(resp), cfg.cacheTTL)
+
+		return resp, nil
+	}
+}
+
+// cacheKey hashes the content of req.
+func (c *client) cacheKey(cfg callConfig, req *Request) string {
+	return hex.EncodeToString(c.contentHash(cfg, req).Sum(nil))
+}
+
+// varyKey hashes the content of req, the vary headers as sent and the call scoped request context,
+// so that responses of different callers are not shared.
+func (c *client) varyKey(cfg callConfig, req *Request, vary []string) string {
+	h := c.contentHash(cfg, req)
+
+	headers := cfg.withCookies(cfg.withDefaultHeaders(req.Headers))
+	for _, name := range vary {
+		v, _ := headerValue(headers, name)
+		h.Write([]byte(v))
+		h.Write([]byte{0})
+		for k, values := range req.MultiValueHeaders {
+			if strings.EqualFold(k, name) {
+				h.Write([]byte(strings.Join(values, "\x00")))
+			}
+		}
+		h.Write([]byte{0})
+	}
+
+	// map keys are sorted by encoding/json, unencodable values only weaken the key
+	requestContext, _ := json.Marshal(struct {
+		Authorizer     map[string]any
+		Identity       events.APIGatewayRequestIdentity
+		StageVariables map[string]string
+	}{cfg.authorizerContext, cfg.identity, cfg.stageVariables})
+	h.Write(requestContext)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *client) contentHash(cfg callConfig, req *Request) hash.Hash {
+	h := sha256.New()
+	for _, s := range []string{c.functionARN, cfg.qualifier, req.HTTPMethod, req.Path, req.Query.Encode()} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	h.Write(req.Body)
+
+	return h
+}
+
+// cloneResponse deep copies resp, so that cached responses are not mutated through the ones handed out.
+func cloneResponse(resp *Response) *Response {
+	clone := *resp
+	clone.Headers = maps.Clone(resp.Headers)
+	if resp.MultiValueHeaders != nil {
+		clone.MultiValueHeaders = make(map[string][]string, len(resp.MultiValueHeaders))
+		for k, values := range resp.MultiValueHeaders {
+			clone.MultiValueHeaders[k] = slices.Clone(values)
+		}
+	}
+	clone.Data = bytes.Clone(resp.Data)
+	if resp.Stats != nil {
+		stats := *resp.Stats
+		clone.Stats = &stats
+	}
+
+	return &clone
+}
+
+// LRUCache is an in-memory CacheStore evicting the least recently used entries.
+type LRUCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type lruEntry struct {
+	key       string
+	resp      *Response
+	expiresAt time.Time
+}
+
+// NewLRUCache returns a cache of at most size entries.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    max(size, 1),
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (l *LRUCache) Get(key string) (*Response, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := e.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		l.order.Remove(e)
+		delete(l.entries, key)
+		return nil, false
+	}
+
+	l.order.MoveToFront(e)
+
+	return entry.resp, true
+}
+
+func (l *LRUCache) Set(key string, resp *Response, ttl time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry := &lruEntry{key: key, resp: resp, expiresAt: time.Now().Add(ttl)}
+
+	if e, ok := l.entries[key]; ok {
+		e.Value = entry
+		l.order.MoveToFront(e)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(entry)
+
+	if l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of entries including expired ones which were not evicted yet.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
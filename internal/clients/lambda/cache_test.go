@@ -0,0 +1,99 @@
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWithCache(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithCache(Cache{TTL: time.Minute}))
+
+	for range 3 {
+		body, err := cli.Invoke(_ctx, "GET", "/orders/1", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", body)
+	}
+	assert.Len(t, api.calls(), 1)
+
+	_, err := cli.Invoke(_ctx, "GET", "/orders/2", nil)
+	require.NoError(t, err)
+	_, err = cli.Invoke(_ctx, "POST", "/orders/1", nil)
+	require.NoError(t, err)
+	_, err = cli.Invoke(_ctx, "GET", "/orders/1", nil, WithCacheTTL(0))
+	require.NoError(t, err)
+	_, err = cli.Invoke(_ctx, "GET", "/orders/1", nil, WithQualifier("canary"))
+	require.NoError(t, err)
+
+	assert.Len(t, api.calls(), 5)
+}
+
+func TestWithCacheVary(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithCache(Cache{TTL: time.Minute}))
+
+	calls := [][]Option{
+		{WithHeader("Authorization", "Bearer a")},
+		{WithHeader("authorization", "Bearer a")},
+		{WithHeader("Authorization", "Bearer b")},
+		{WithCookie(&http.Cookie{Name: "session", Value: "a"})},
+		{WithClaims(map[string]any{"sub": "a"})},
+		{WithClaims(map[string]any{"sub": "b"})},
+		{WithSourceIP("10.0.0.1")},
+	}
+	for _, opts := range calls {
+		_, err := cli.Invoke(_ctx, "GET", "/orders/1", nil, opts...)
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, api.calls(), 6, "equal Authorization is a hit regardless of its case")
+}
+
+func TestCloneResponse(t *testing.T) {
+	resp := &Response{
+		Headers:           map[string]string{"A": "1"},
+		MultiValueHeaders: map[string][]string{"B": {"1", "2"}},
+		Data:              []byte("data"),
+		Stats:             &ExecutionStats{MemorySize: 128},
+	}
+
+	clone := cloneResponse(resp)
+	clone.Headers["A"] = "2"
+	clone.MultiValueHeaders["B"][0] = "3"
+	clone.Data[0] = 'D'
+	clone.Stats.MemorySize = 256
+
+	assert.Equal(t, "1", resp.Headers["A"])
+	assert.Equal(t, []string{"1", "2"}, resp.MultiValueHeaders["B"])
+	assert.Equal(t, "data", string(resp.Data))
+	assert.Equal(t, 128, resp.Stats.MemorySize)
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+
+	cache.Set("a", &Response{Body: "a"}, time.Minute)
+	cache.Set("b", &Response{Body: "b"}, time.Minute)
+
+	_, ok := cache.Get("a")
+	require.True(t, ok)
+
+	cache.Set("c", &Response{Body: "c"}, time.Minute)
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok, "least recently used is evicted")
+	assert.Equal(t, 2, cache.Len())
+
+	cache.Set("expired", &Response{}, -time.Second)
+	_, ok = cache.Get("expired")
+	assert.False(t, ok)
+
+	for i := range 10 {
+		cache.Set(strconv.Itoa(i), &Response{}, time.Minute)
+	}
+	assert.Equal(t, 2, cache.Len())
+}
@@ -0,0 +1,148 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// callConfig holds the call scoped options.
+type callConfig struct {
+	headers   map[string]string
+	cookies   []*http.Cookie
+	qualifier string
+	logType   types.LogType
+	timeout   time.Duration
+	cacheTTL  time.Duration
+
+	idempotencyKey string
+	jmesPath       string
+
+	authorizerContext map[string]any
+	identity          events.APIGatewayRequestIdentity
+	stageVariables    map[string]string
+}
+
+// WithHeader adds a proxy request header, request headers take precedence.
+func WithHeader(name, value string) Option {
+	return func(c *client) {
+		c.call.headers = withHeader(c.call.headers, name, value)
+	}
+}
+
+// WithQualifier invokes the given version or alias of the function.
+func WithQualifier(qualifier string) Option {
+	return func(c *client) {
+		c.call.qualifier = qualifier
+	}
+}
+
+// WithLogType requests the tail of the execution log with types.LogTypeTail, it is returned in Response.LogResult.
+// Only sync invocations return logs.
+func WithLogType(logType types.LogType) Option {
+	return func(c *client) {
+		c.call.logType = logType
+	}
+}
+
+// WithTimeout bounds every invocation including retries, the context deadline applies if it is earlier.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *client) {
+		c.call.timeout = timeout
+	}
+}
+
+type callConfigKey struct{}
+
+// scopedCallConfig is the callConfig of a call of owner, so that ctx passed on to another client does not
+// leak the options of the outer call into it.
+type scopedCallConfig struct {
+	owner *client
+	cfg   callConfig
+}
+
+// withCallOptions returns ctx carrying the client defaults overridden by opts.
+// It fails with ErrNotCallScoped if an option touches more than the callConfig.
+func (c *client) withCallOptions(ctx context.Context, opts []Option) (context.Context, error) {
+	if len(opts) == 0 {
+		return ctx, nil
+	}
+
+	// call scoped options only touch scratch.call
+	scratch := &client{functionARN: c.functionARN, call: c.callConfig(ctx)}
+	scratch.call.headers = maps.Clone(scratch.call.headers)
+	scratch.call.cookies = slices.Clip(scratch.call.cookies)
+	for i, opt := range opts {
+		opt(scratch)
+		if !scratch.onlyCallScoped() {
+			return ctx, fmt.Errorf("opts[%d]: %w", i, ErrNotCallScoped)
+		}
+	}
+
+	return c.withCallConfig(ctx, scratch.call), nil
+}
+
+// onlyCallScoped reports whether nothing but call and functionARN of the scratch client was set.
+func (c *client) onlyCallScoped() bool {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := range t.NumField() {
+		switch t.Field(i).Name {
+		case "call", "functionARN":
+			continue
+		}
+
+		if !v.Field(i).IsZero() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// withCallConfig returns ctx carrying cfg for the calls of c.
+func (c *client) withCallConfig(ctx context.Context, cfg callConfig) context.Context {
+	return context.WithValue(ctx, callConfigKey{}, scopedCallConfig{owner: c, cfg: cfg})
+}
+
+// callConfig returns the per-call options of ctx or the client defaults.
+func (c *client) callConfig(ctx context.Context) callConfig {
+	if scoped, ok := ctx.Value(callConfigKey{}).(scopedCallConfig); ok && scoped.owner == c {
+		return scoped.cfg
+	}
+
+	return c.call
+}
+
+// withDefaultHeaders returns headers merged over the configured ones, the caller's map is not modified.
+func (cfg callConfig) withDefaultHeaders(headers map[string]string) map[string]string {
+	if len(cfg.headers) == 0 {
+		return headers
+	}
+
+	out := maps.Clone(cfg.headers)
+	maps.Copy(out, headers)
+
+	return out
+}
+
+// decodeLogResult decodes the base64 encoded log tail, it is dropped if invalid.
+func decodeLogResult(logResult *string) string {
+	if logResult == nil {
+		return ""
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(*logResult)
+	if err != nil {
+		return ""
+	}
+
+	return string(decoded)
+}
@@ -0,0 +1,71 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPerCallOptions(t *testing.T) {
+	var deadlines []time.Time
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		deadline, _ := ctx.Deadline()
+		deadlines = append(deadlines, deadline)
+		return proxyOutput(in, http.StatusOK, "ok"), nil
+	}}
+	cli := newClient(api, testFunctionARN, WithQualifier("live"), WithHeader("X-Team", "payments"), WithTimeout(time.Hour))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+
+	_, err = cli.Invoke(_ctx, "GET", "/", nil,
+		WithQualifier("canary"), WithHeader("X-Debug", "1"), WithTimeout(time.Second), WithLogType(types.LogTypeTail))
+	require.NoError(t, err)
+
+	_, err = cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/", Headers: map[string]string{"X-Team": "orders"}})
+	require.NoError(t, err)
+
+	calls := api.calls()
+
+	assert.Equal(t, "live", *calls[0].Qualifier)
+	assert.Equal(t, map[string]string{"X-Team": "payments"}, proxyRequest(calls[0]).Headers)
+	assert.Equal(t, types.LogTypeNone, calls[0].LogType)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), deadlines[0], time.Minute)
+
+	assert.Equal(t, "canary", *calls[1].Qualifier)
+	assert.Equal(t, map[string]string{"X-Team": "payments", "X-Debug": "1"}, proxyRequest(calls[1]).Headers)
+	assert.Equal(t, types.LogTypeTail, calls[1].LogType)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadlines[1], time.Minute)
+
+	assert.Equal(t, "live", *calls[2].Qualifier, "per-call options do not leak")
+	assert.Equal(t, map[string]string{"X-Team": "orders"}, proxyRequest(calls[2]).Headers, "request headers take precedence")
+}
+
+func TestPerCallOptionsScope(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithQualifier("live"))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil, WithRetryPolicy(RetryPolicy{}))
+	require.ErrorIs(t, err, ErrNotCallScoped)
+	assert.Empty(t, api.calls())
+
+	// ctx of a call passed on to another client does not carry its options
+	other := &fakeAPI{}
+	otherCli := newClient(other, testFunctionARN)
+	cli = newClient(api, testFunctionARN, WithInterceptors(func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		_, err := otherCli.Invoke(ctx, "GET", "/", nil)
+		require.NoError(t, err)
+		return next(ctx, req)
+	}))
+
+	_, err = cli.Invoke(_ctx, "GET", "/", nil, WithQualifier("canary"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "canary", *api.calls()[0].Qualifier)
+	assert.Nil(t, other.calls()[0].Qualifier)
+}
@@ -0,0 +1,87 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+)
+
+type callbackJob struct {
+	ctx      context.Context
+	req      Request
+	callback func(Result)
+}
+
+// InvokeWithCallback synchronously invokes the function on the dispatcher goroutine pool
+// and passes the outcome to callback, which runs on a dispatcher goroutine too.
+// It never blocks, invocations wait for a free dispatcher of the pool sized with WithConcurrency, so callbacks
+// may chain further invocations. The callback never runs on the caller goroutine, it gets ErrClientClosed after Close.
+func (c *client) InvokeWithCallback(ctx context.Context, req Request, callback func(Result)) {
+	c.dispatcherMu.RLock()
+	if c.dispatcherClosed {
+		c.dispatcherMu.RUnlock()
+		go callback(Result{Err: fmt.Errorf("invoke[callback]: %w", ErrClientClosed)})
+		return
+	}
+
+	c.dispatcherOnce.Do(c.startDispatchers)
+	c.enqueued.Add(1)
+	c.dispatcherMu.RUnlock()
+
+	job := callbackJob{ctx: ctx, req: req, callback: callback}
+
+	select {
+	case c.callbacks <- job:
+		c.enqueued.Done()
+	default:
+		// all dispatchers are busy, e.g. running the callback chaining this invocation
+		go c.enqueue(job)
+	}
+}
+
+// enqueue waits for a free dispatcher, Close waits for it before closing the queue.
+func (c *client) enqueue(job callbackJob) {
+	defer c.enqueued.Done()
+
+	select {
+	case c.callbacks <- job:
+	case <-job.ctx.Done():
+		job.callback(Result{Err: fmt.Errorf("invoke[callback]: %w", context.Cause(job.ctx))})
+	}
+}
+
+// startDispatchers starts the pool lazily, dispatchers live until Close.
+func (c *client) startDispatchers() {
+	c.callbacks = make(chan callbackJob)
+
+	for range c.concurrency {
+		c.dispatchers.Add(1)
+		go func() {
+			defer c.dispatchers.Done()
+			for job := range c.callbacks {
+				job.callback(c.invokeRequest(job.ctx, 0, job.req))
+			}
+		}()
+	}
+}
+
+// Close stops the dispatchers of InvokeWithCallback once the accepted invocations and their callbacks are done.
+// It is safe to call more than once.
+func (c *client) Close() {
+	c.dispatcherMu.Lock()
+	closing := !c.dispatcherClosed
+	c.dispatcherClosed = true
+	c.dispatcherMu.Unlock()
+
+	// no invocation is accepted once closed, the accepted ones are queued while dispatchers drain the queue
+	c.enqueued.Wait()
+
+	if closing && c.callbacks != nil {
+		close(c.callbacks)
+	}
+
+	c.dispatchers.Wait()
+
+	if c.balancer != nil {
+		c.balancer.close()
+	}
+}
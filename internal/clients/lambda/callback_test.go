@@ -0,0 +1,140 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestInvokeWithCallback(t *testing.T) {
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusOK, proxyRequest(in).Path), nil
+	}}
+
+	cli := newClient(api, testFunctionARN, WithConcurrency(2))
+
+	const n = 10
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		bodies []string
+	)
+
+	wg.Add(n)
+	for i := range n {
+		cli.InvokeWithCallback(_ctx, Request{HTTPMethod: "GET", Path: fmt.Sprintf("/%d", i)}, func(r Result) {
+			defer wg.Done()
+			require.NoError(t, r.Err)
+
+			mu.Lock()
+			bodies = append(bodies, r.Body)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	assert.Len(t, bodies, n)
+}
+
+func TestInvokeWithCallback_ContextCancelled(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		<-block
+		return proxyOutput(in, http.StatusOK, ""), nil
+	}}
+
+	cli := newClient(api, testFunctionARN, WithConcurrency(1))
+
+	// occupies the only dispatcher
+	cli.InvokeWithCallback(_ctx, Request{HTTPMethod: "GET", Path: "/"}, func(Result) {})
+
+	ctx, cancel := context.WithCancel(_ctx)
+	cancel()
+
+	results := make(chan Result, 1)
+	cli.InvokeWithCallback(ctx, Request{HTTPMethod: "GET", Path: "/"}, func(r Result) { results <- r })
+
+	assert.ErrorIs(t, (<-results).Err, context.Canceled)
+}
+
+func TestInvokeWithCallback_Close(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithConcurrency(2))
+
+	done := make(chan Result, 1)
+	cli.InvokeWithCallback(_ctx, Request{HTTPMethod: "GET", Path: "/"}, func(r Result) { done <- r })
+
+	cli.Close()
+	require.NoError(t, (<-done).Err, "accepted invocations complete before Close returns")
+
+	results := make(chan Result, 1)
+	cli.InvokeWithCallback(_ctx, Request{HTTPMethod: "GET", Path: "/"}, func(r Result) { results <- r })
+
+	assert.ErrorIs(t, (<-results).Err, ErrClientClosed)
+	assert.Len(t, api.calls(), 1)
+
+	cli.Close()
+}
+
+func TestInvokeWithCallback_Chained(t *testing.T) {
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusOK, proxyRequest(in).Path), nil
+	}}
+
+	cli := newClient(api, testFunctionARN, WithConcurrency(1))
+
+	done := make(chan Result, 1)
+	cli.InvokeWithCallback(_ctx, Request{HTTPMethod: "GET", Path: "/orders"}, func(r Result) {
+		require.NoError(t, r.Err)
+
+		// the only dispatcher is running this callback
+		cli.InvokeWithCallback(_ctx, Request{HTTPMethod: "GET", Path: "/orders/1"}, func(r Result) { done <- r })
+	})
+
+	r := <-done
+	require.NoError(t, r.Err)
+	assert.Equal(t, "/orders/1", r.Body)
+
+	cli.Close()
+	assert.Len(t, api.calls(), 2)
+}
+
+func TestInvokeWithCallback_CloseChained(t *testing.T) {
+	release := make(chan struct{})
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		if proxyRequest(in).Path == "/orders" {
+			<-release
+		}
+		return proxyOutput(in, http.StatusOK, ""), nil
+	}}
+
+	cli := newClient(api, testFunctionARN, WithConcurrency(1))
+
+	chained := make(chan Result, 1)
+	cli.InvokeWithCallback(_ctx, Request{HTTPMethod: "GET", Path: "/orders"}, func(Result) {
+		cli.InvokeWithCallback(_ctx, Request{HTTPMethod: "GET", Path: "/orders/1"}, func(r Result) { chained <- r })
+	})
+	cli.InvokeWithCallback(_ctx, Request{HTTPMethod: "GET", Path: "/orders/2"}, func(Result) {})
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		cli.Close()
+	}()
+	close(release)
+	<-closed
+
+	// the chained invocation is either accepted before Close or rejected after it
+	r := <-chained
+	if r.Err != nil {
+		assert.ErrorIs(t, r.Err, ErrClientClosed)
+	}
+}
@@ -0,0 +1,216 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// CanaryConfig configures a Canary.
+type CanaryConfig struct {
+	// Stable and Canary are qualifiers, i.e. versions or aliases, e.g. "live" and "canary".
+	Stable string
+	Canary string
+	// Weight is the percentage of invocations routed to Canary, e.g. 5.
+	Weight float64
+	// ErrorRate of Canary invocations within Window above which all traffic falls back to Stable, e.g. 0.05.
+	// Fallback is disabled if 0.
+	ErrorRate float64
+	// MinRequests to Canary within Window before ErrorRate is evaluated, 20 by default.
+	MinRequests int
+	// Window of error tracking, 1 minute by default.
+	Window time.Duration
+	// OnFallback is called once on fallback to Stable, if not nil.
+	OnFallback func(canary QualifierStats)
+}
+
+// QualifierStats counts invocations of a qualifier within the current window.
+type QualifierStats struct {
+	Qualifier string
+	Requests  int
+	Errors    int
+}
+
+// ErrorRate returns Errors/Requests, 0 without requests.
+func (s QualifierStats) ErrorRate() float64 {
+	if s.Requests == 0 {
+		return 0
+	}
+
+	return float64(s.Errors) / float64(s.Requests)
+}
+
+// Canary splits traffic between two qualifiers by weight and tracks errors per qualifier, install it with
+// WithCanary. The fallback to Stable is sticky until Reset is called, e.g. after a new canary deployment.
+type Canary struct {
+	cfg CanaryConfig
+
+	mu          sync.Mutex
+	windowStart time.Time
+	stable      QualifierStats
+	canary      QualifierStats
+	fellBack    bool
+	// onStateChange of every client the canary is installed into
+	onStateChange []func(ctx context.Context, from, to string)
+}
+
+// Breaker states of a Canary reported by BreakerStateChanged, it is open while all traffic falls back to Stable.
+const (
+	BreakerClosed = "closed"
+	BreakerOpen   = "open"
+)
+
+// NewCanary returns a canary routing cfg.Weight percent of invocations to cfg.Canary.
+func NewCanary(cfg CanaryConfig) *Canary {
+	if cfg.MinRequests <= 0 {
+		cfg.MinRequests = 20
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+
+	k := &Canary{cfg: cfg}
+	k.resetWindow(time.Now())
+
+	return k
+}
+
+// WithCanary routes every invocation to the Stable or the Canary qualifier of k, overriding WithQualifier.
+// Fallbacks and resets of k are emitted as BreakerStateChanged to WithEventSink sinks.
+func WithCanary(k *Canary) Option {
+	return func(c *client) {
+		c.canary = k
+	}
+}
+
+// subscribe emits state changes of k to the sinks of c, it is called once per client by newClient.
+func (k *Canary) subscribe(c *client) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.onStateChange = append(k.onStateChange, func(ctx context.Context, from, to string) {
+		c.emit(ctx, BreakerStateChanged{EventHeader: c.eventHeader(EventBreakerStateChanged), From: from, To: to})
+	})
+}
+
+// canaryRouted picks the qualifier of each invocation and records its outcome.
+func (c *client) canaryRouted(next Invoker) Invoker {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		qualifier := c.canary.pick(c.rand)
+
+		cfg := c.callConfig(ctx)
+		cfg.qualifier = qualifier
+
+		resp, err := next(c.withCallConfig(ctx, cfg), req)
+		c.canary.record(ctx, qualifier, err)
+
+		return resp, err
+	}
+}
+
+func (k *Canary) pick(r *Rand) string {
+	k.mu.Lock()
+	fellBack := k.fellBack
+	k.mu.Unlock()
+
+	if !fellBack && r.Sample(k.cfg.Weight/100) {
+		return k.cfg.Canary
+	}
+
+	return k.cfg.Stable
+}
+
+func (k *Canary) record(ctx context.Context, qualifier string, err error) {
+	failed := isCanaryFailure(err)
+
+	k.mu.Lock()
+
+	now := time.Now()
+	if now.Sub(k.windowStart) >= k.cfg.Window {
+		k.resetWindow(now)
+	}
+
+	stats := &k.stable
+	if qualifier == k.cfg.Canary {
+		stats = &k.canary
+	}
+	stats.Requests++
+	if failed {
+		stats.Errors++
+	}
+
+	fallback := !k.fellBack && k.cfg.ErrorRate > 0 && k.canary.Requests >= k.cfg.MinRequests &&
+		k.canary.ErrorRate() > k.cfg.ErrorRate
+	if fallback {
+		k.fellBack = true
+	}
+	canary := k.canary
+	onStateChange := k.onStateChange
+
+	k.mu.Unlock()
+
+	if !fallback {
+		return
+	}
+
+	if k.cfg.OnFallback != nil {
+		k.cfg.OnFallback(canary)
+	}
+	for _, fn := range onStateChange {
+		fn(ctx, BreakerClosed, BreakerOpen)
+	}
+}
+
+// isCanaryFailure tells errors of the function from errors of the caller, such as canceled contexts.
+func isCanaryFailure(err error) bool {
+	switch KindOf(err) {
+	case ErrorKindNone, ErrorKindContext, ErrorKindPayloadTooLarge, ErrorKindUnauthorized:
+		return false
+	case ErrorKindBadStatus:
+		var use *ErrUnexpectedStatus
+		return errors.As(err, &use) && use.Code >= 500
+	default:
+		return true
+	}
+}
+
+func (k *Canary) resetWindow(now time.Time) {
+	k.windowStart = now
+	k.stable = QualifierStats{Qualifier: k.cfg.Stable}
+	k.canary = QualifierStats{Qualifier: k.cfg.Canary}
+}
+
+// Stats returns the stable and the canary stats of the current window.
+func (k *Canary) Stats() (stable, canary QualifierStats) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.stable, k.canary
+}
+
+// FellBack reports whether all traffic falls back to Stable.
+func (k *Canary) FellBack() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	return k.fellBack
+}
+
+// Reset resumes routing to Canary and starts a new window.
+func (k *Canary) Reset() {
+	k.mu.Lock()
+	fellBack := k.fellBack
+	k.fellBack = false
+	k.resetWindow(time.Now())
+	onStateChange := k.onStateChange
+	k.mu.Unlock()
+
+	if !fellBack {
+		return
+	}
+
+	for _, fn := range onStateChange {
+		fn(context.Background(), BreakerOpen, BreakerClosed)
+	}
+}
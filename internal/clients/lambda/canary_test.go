@@ -0,0 +1,107 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestWithCanary(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		if *in.Qualifier == "canary" {
+			return proxyOutput(in, http.StatusInternalServerError, "broken"), nil
+		}
+		return proxyOutput(in, http.StatusOK, "ok"), nil
+	}}
+
+	var fallbacks []QualifierStats
+	canary := NewCanary(CanaryConfig{
+		Stable:      "live",
+		Canary:      "canary",
+		Weight:      20,
+		ErrorRate:   0.5,
+		MinRequests: 10,
+		OnFallback:  func(s QualifierStats) { fallbacks = append(fallbacks, s) },
+	})
+	var changes []BreakerStateChanged
+	sink := func(_ context.Context, e Event) {
+		if changed, ok := e.(BreakerStateChanged); ok {
+			changes = append(changes, changed)
+		}
+	}
+	cli := newClient(api, testFunctionARN, WithCanary(canary), WithRand(NewRand(1)), WithEventSink(sink))
+
+	for range 200 {
+		_, _ = cli.Invoke(_ctx, "GET", "/", nil)
+	}
+
+	require.Len(t, fallbacks, 1)
+	assert.Equal(t, QualifierStats{Qualifier: "canary", Requests: 10, Errors: 10}, fallbacks[0])
+	assert.True(t, canary.FellBack())
+
+	stable, canaryStats := canary.Stats()
+	assert.Equal(t, 190, stable.Requests)
+	assert.Zero(t, stable.Errors)
+	assert.Equal(t, 10, canaryStats.Requests)
+
+	canary.Reset()
+	assert.False(t, canary.FellBack())
+	canary.Reset()
+
+	require.Len(t, changes, 2, "emitted on fallback and on reset")
+	assert.Equal(t, EventBreakerStateChanged, changes[0].Type)
+	assert.Equal(t, testFunctionARN, changes[0].FunctionARN)
+	assert.Equal(t, []string{BreakerClosed, BreakerOpen}, []string{changes[0].From, changes[0].To})
+	assert.Equal(t, []string{BreakerOpen, BreakerClosed}, []string{changes[1].From, changes[1].To})
+
+	var routed int
+	for _, in := range api.calls() {
+		if *in.Qualifier == "canary" {
+			routed++
+		}
+	}
+	assert.Equal(t, 10, routed, "no traffic to the canary after the fallback")
+}
+
+func TestWithCanary_SubscribedOnce(t *testing.T) {
+	canary := NewCanary(CanaryConfig{Stable: "live", Canary: "canary", Weight: 5})
+
+	var changes int
+	sink := func(_ context.Context, e Event) {
+		if _, ok := e.(BreakerStateChanged); ok {
+			changes++
+		}
+	}
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithCanary(canary), WithEventSink(sink))
+
+	for range 3 {
+		_, err := cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/"}, WithCanary(canary))
+		require.ErrorIs(t, err, ErrNotCallScoped)
+	}
+
+	canary.fellBack = true
+	canary.Reset()
+
+	assert.Equal(t, 1, changes, "per-call options do not subscribe the scratch client")
+	assert.Len(t, canary.onStateChange, 1)
+}
+
+func TestWithCanary_Weight(t *testing.T) {
+	api := &fakeAPI{}
+	canary := NewCanary(CanaryConfig{Stable: "live", Canary: "canary", Weight: 5})
+	cli := newClient(api, testFunctionARN, WithCanary(canary), WithRand(NewRand(1)))
+
+	for range 2000 {
+		_, err := cli.Invoke(_ctx, "GET", "/", nil, WithQualifier("ignored"))
+		require.NoError(t, err)
+	}
+
+	stable, canaryStats := canary.Stats()
+	assert.InDelta(t, 100, canaryStats.Requests, 40)
+	assert.Equal(t, 2000, stable.Requests+canaryStats.Requests)
+	assert.Zero(t, canaryStats.Errors)
+	assert.NotEqual(t, "ignored", *api.calls()[0].Qualifier)
+}
@@ -0,0 +1,100 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CancelHeader marks a cancel event published by WithCancelEvents, CorrelationIDHeader identifies the canceled
+// invocation.
+const CancelHeader = "X-Lambda-Cancel"
+
+// CancelEvents configures WithCancelEvents.
+type CancelEvents struct {
+	// Timeout of publishing the cancel event, 5 seconds by default.
+	Timeout time.Duration
+	// OnPublished is called with the correlation ID and the outcome once the cancel event is published, if not nil.
+	OnPublished func(correlationID string, err error)
+}
+
+// WithCancelEvents publishes a cancel event for async invocations whose context is done before the Invoke API
+// call returns. Such invocations fail with ErrorKindContext, yet the event may have been queued by Lambda and
+// run later. The cancel event is an async invocation of the same method and path with an empty body, CancelHeader
+// and the CorrelationIDHeader of the canceled one, it is published in the background. Handlers supporting
+// cancellation honor it with CancelHandler, others treat it as a regular request.
+// Invocations whose context is done before they start are not sent and need no cancel event.
+// It enables WithCorrelationID.
+func WithCancelEvents(ce CancelEvents) Option {
+	if ce.Timeout <= 0 {
+		ce.Timeout = 5 * time.Second
+	}
+
+	return func(c *client) {
+		c.cancelEvents = &ce
+		c.correlationID = true
+	}
+}
+
+func (c *client) invokeCancellable(ctx context.Context, req *Request) (*Response, error) {
+	if ctx.Err() != nil {
+		return c.invoker(ctx, req)
+	}
+
+	resp, err := c.invoker(ctx, req)
+	if err == nil || ctx.Err() == nil || errors.Is(err, ErrInsufficientBudget) {
+		return resp, err
+	}
+
+	id, _ := CorrelationIDFromContext(ctx)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.cancelEvents.Timeout)
+		defer cancel()
+
+		err := c.publishCancel(ctx, req, id)
+		if c.cancelEvents.OnPublished != nil {
+			c.cancelEvents.OnPublished(id, err)
+		}
+	}()
+
+	return resp, err
+}
+
+func (c *client) publishCancel(ctx context.Context, req *Request, id string) error {
+	cancellation := &Request{
+		HTTPMethod: req.HTTPMethod,
+		Path:       req.Path,
+		Headers:    map[string]string{CorrelationIDHeader: id, CancelHeader: "true"},
+		Async:      true,
+	}
+
+	if _, err := c.invoker(ctx, cancellation); err != nil {
+		return fmt.Errorf("invoke[cancel]: %w", err)
+	}
+
+	return nil
+}
+
+// CancelHandler wraps the function side handler to pass cancel events published by WithCancelEvents to onCancel
+// instead of next, e.g. to mark the correlation ID canceled so that its invocation is skipped or stopped.
+func CancelHandler(onCancel func(ctx context.Context, correlationID string) error) func(next Handler) Handler {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			if value, _ := headerValue(req.Headers, CancelHeader); !strings.EqualFold(value, "true") {
+				return next(ctx, req)
+			}
+
+			id, _ := headerValue(req.Headers, CorrelationIDHeader)
+			if err := onCancel(ctx, id); err != nil {
+				return events.APIGatewayProxyResponse{}, fmt.Errorf("onCancel: %w", err)
+			}
+
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusAccepted}, nil
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithCancelEvents(t *testing.T) {
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		if proxyRequest(in).Headers[CancelHeader] == "" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &lambda.InvokeOutput{StatusCode: http.StatusAccepted}, nil
+	}}
+
+	published := make(chan string, 1)
+	cli := newClient(api, testFunctionARN, WithCancelEvents(CancelEvents{
+		OnPublished: func(id string, err error) {
+			assert.NoError(t, err)
+			published <- id
+		},
+	}))
+
+	ctx, cancel := context.WithTimeout(ContextWithCorrelationID(_ctx, "order-1"), 10*time.Millisecond)
+	defer cancel()
+
+	err := cli.InvokeAsync(ctx, "POST", "/orders", []byte(`{}`))
+	assert.Equal(t, ErrorKindContext, KindOf(err))
+
+	select {
+	case id := <-published:
+		assert.Equal(t, "order-1", id)
+	case <-time.After(time.Second):
+		t.Fatal("cancel event is not published")
+	}
+
+	calls := api.calls()
+	require.Len(t, calls, 2)
+	event := proxyRequest(calls[1])
+	assert.Equal(t, "true", event.Headers[CancelHeader])
+	assert.Equal(t, "order-1", event.Headers[CorrelationIDHeader])
+	assert.Equal(t, "/orders", event.Path)
+	assert.Empty(t, event.Body)
+}
+
+func TestWithCancelEvents_NotStarted(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithCancelEvents(CancelEvents{
+		OnPublished: func(string, error) { t.Error("cancel event is published") },
+	}))
+
+	ctx, cancel := context.WithCancel(_ctx)
+	cancel()
+
+	// the fake ignores ctx, the invocation is not canceled
+	require.NoError(t, cli.InvokeAsync(ctx, "POST", "/orders", nil))
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestCancelHandler(t *testing.T) {
+	var canceled string
+	handler := CancelHandler(func(_ context.Context, id string) error {
+		canceled = id
+		return nil
+	})(func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	resp, err := handler(_ctx, events.APIGatewayProxyRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, canceled)
+
+	resp, err = handler(_ctx, events.APIGatewayProxyRequest{Headers: map[string]string{CancelHeader: "true", CorrelationIDHeader: "order-1"}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "order-1", canceled)
+}
+
+func TestNewHTTPRequest_CancelHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	r.Header.Set(CancelHeader, "true")
+	r.Header.Set(CorrelationIDHeader, "corr-1")
+
+	req, err := newHTTPRequest(r, "", MaxSyncPayloadSize)
+	require.NoError(t, err)
+
+	_, ok := headerValue(req.Headers, CancelHeader)
+	assert.False(t, ok, "HTTP callers cannot forge cancel events")
+	assert.NotContains(t, req.MultiValueHeaders, CancelHeader)
+}
@@ -0,0 +1,23 @@
+package lambda
+
+import (
+	"github.com/fxamacker/cbor/v2"
+)
+
+// CBORCodec encodes bodies with CBOR (RFC 8949), it is more compact than JSON for payloads approaching the invoke limit.
+// Bodies are base64 encoded in the proxy envelope, struct fields use cbor or json tags.
+var CBORCodec Codec = cborCodec{}
+
+type cborCodec struct{}
+
+func (cborCodec) ContentType() string {
+	return "application/cbor"
+}
+
+func (cborCodec) Marshal(v any) ([]byte, error) {
+	return cbor.Marshal(v)
+}
+
+func (cborCodec) Unmarshal(data []byte, v any) error {
+	return cbor.Unmarshal(data, v)
+}
@@ -0,0 +1,50 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestCBORCodec(t *testing.T) {
+	type order struct {
+		ID    string   `json:"id"`
+		Items []string `json:"items"`
+	}
+
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		req := proxyRequest(in)
+
+		var o order
+		body, err := decodeBody(req.Body, req.IsBase64Encoded)
+		require.NoError(t, err)
+		require.NoError(t, CBORCodec.Unmarshal(body, &o))
+		o.Items = append(o.Items, "receipt")
+
+		encoded, err := CBORCodec.Marshal(o)
+		require.NoError(t, err)
+
+		payload, err := json.Marshal(events.APIGatewayProxyResponse{
+			StatusCode:      http.StatusOK,
+			Headers:         map[string]string{"Content-Type": "application/cbor"},
+			Body:            base64.StdEncoding.EncodeToString(encoded),
+			IsBase64Encoded: true,
+		})
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, err
+	}}
+	cli := newClient(api, testFunctionARN, WithCodec(CBORCodec))
+
+	got, err := CallAs[order](_ctx, cli, "POST", "/orders", order{ID: "42", Items: []string{"book"}})
+	require.NoError(t, err)
+	assert.Equal(t, order{ID: "42", Items: []string{"book", "receipt"}}, got)
+
+	req := proxyRequest(api.calls()[0])
+	assert.Equal(t, "application/cbor", req.Headers["Content-Type"])
+	assert.True(t, req.IsBase64Encoded)
+}
@@ -0,0 +1,77 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"time"
+)
+
+// ErrChaos is the transport failure injected by WithChaos.
+var ErrChaos = errors.New("chaos: injected failure")
+
+// Chaos configures faults injected by WithChaos, rates are probabilities in [0, 1].
+type Chaos struct {
+	// Latency is added before every Invoke API call, plus a random duration up to LatencyJitter.
+	Latency       time.Duration
+	LatencyJitter time.Duration
+	// ErrorRate fails calls with ErrChaos, they are classified as ErrorKindTransport.
+	ErrorRate float64
+	// ThrottleRate fails calls with TooManyRequestsException, they are classified as ErrorKindThrottled.
+	ThrottleRate float64
+	// TruncateRate cuts response payloads of sync calls in half.
+	TruncateRate float64
+}
+
+// WithChaos injects faults into Invoke API calls to test resilience logic, e.g. retry policies and fallbacks.
+// Faults are injected beneath retries and interceptors, where AWS would misbehave. Failed calls do not reach
+// the function, combine it with fakelambda or NewReplayClient to avoid touching AWS at all.
+// Randomness comes from WithRand.
+func WithChaos(chaos Chaos) Option {
+	return func(c *client) {
+		c.cli = &chaosAPI{lambdaAPI: c.cli, chaos: chaos, client: c}
+	}
+}
+
+type chaosAPI struct {
+	lambdaAPI
+	chaos  Chaos
+	client *client
+}
+
+func (a *chaosAPI) Invoke(ctx context.Context, params *lambda.InvokeInput, optFns ...func(*lambda.Options)) (*lambda.InvokeOutput, error) {
+	rnd := a.client.rand
+
+	if latency := a.chaos.Latency + rnd.Jitter(a.chaos.LatencyJitter); latency > 0 {
+		t := time.NewTimer(latency)
+		defer t.Stop()
+
+		select {
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		case <-t.C:
+		}
+	}
+
+	switch p := rnd.Float64(); {
+	case p < a.chaos.ErrorRate:
+		return nil, ErrChaos
+	case p < a.chaos.ErrorRate+a.chaos.ThrottleRate:
+		return nil, &types.TooManyRequestsException{Message: pointer.To("chaos: injected throttling")}
+	}
+
+	output, err := a.lambdaAPI.Invoke(ctx, params, optFns...)
+	if err != nil || output == nil || len(output.Payload) == 0 {
+		return output, err
+	}
+
+	if rnd.Sample(a.chaos.TruncateRate) {
+		truncated := *output
+		truncated.Payload = output.Payload[:len(output.Payload)/2]
+		return &truncated, nil
+	}
+
+	return output, nil
+}
@@ -0,0 +1,62 @@
+package lambda
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestWithChaos(t *testing.T) {
+	tests := []struct {
+		name  string
+		chaos Chaos
+		kind  ErrorKind
+	}{
+		{"error", Chaos{ErrorRate: 1}, ErrorKindTransport},
+		{"throttle", Chaos{ThrottleRate: 1}, ErrorKindThrottled},
+		{"truncate", Chaos{TruncateRate: 1}, ErrorKindMarshal},
+		{"none", Chaos{}, ErrorKindNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newClient(&fakeAPI{}, testFunctionARN, WithChaos(tt.chaos))
+
+			_, err := cli.Invoke(_ctx, "GET", "/", nil)
+			assert.Equal(t, tt.kind, KindOf(err), "%v", err)
+		})
+	}
+}
+
+func TestWithChaos_ErrorRate(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithChaos(Chaos{ErrorRate: 0.5}), WithRand(NewRand(1)))
+
+	var failed int
+	for range 200 {
+		if _, err := cli.Invoke(_ctx, "GET", "/", nil); err != nil {
+			require.ErrorIs(t, err, ErrChaos)
+			failed++
+		}
+	}
+
+	assert.InDelta(t, 100, failed, 30)
+	assert.Len(t, api.calls(), 200-failed, "failed calls do not reach the function")
+}
+
+func TestWithChaos_Latency(t *testing.T) {
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithChaos(Chaos{Latency: 20 * time.Millisecond}))
+
+	start := time.Now()
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(_ctx, time.Millisecond)
+	defer cancel()
+
+	_, err = cli.Invoke(ctx, "GET", "/", nil)
+	assert.Equal(t, ErrorKindContext, KindOf(err))
+}
@@ -0,0 +1,103 @@
+package lambda
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"strings"
+)
+
+// ChecksumHeader is the response header carrying the hex encoded SHA-256 of the response body.
+const ChecksumHeader = "X-Body-Checksum"
+
+// ErrChecksumMismatch is returned when the response body does not match its ChecksumHeader.
+var ErrChecksumMismatch = errors.New("response body checksum mismatch")
+
+// Handler is an API Gateway proxy handler as passed to aws-lambda-go lambda.Start.
+type Handler func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
+
+// ChecksumHandler wraps the function side handler to set ChecksumHeader on every response,
+// it is verified by clients created with WithChecksumVerification.
+func ChecksumHandler(next Handler) Handler {
+	return func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		resp, err := next(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		body, err := decodeBody(resp.Body, resp.IsBase64Encoded)
+		if err != nil {
+			return resp, fmt.Errorf("decodeBody: %w", err)
+		}
+
+		if resp.Headers == nil {
+			resp.Headers = make(map[string]string, 1)
+		}
+		resp.Headers[ChecksumHeader] = checksum(body)
+
+		return resp, nil
+	}
+}
+
+// WithChecksumVerification makes the client verify ChecksumHeader of every sync response,
+// detecting truncation or corruption of the body on its way from the handler.
+func WithChecksumVerification() Option {
+	return func(c *client) {
+		c.verifyChecksum = true
+	}
+}
+
+func verifyChecksum(r events.APIGatewayProxyResponse) error {
+	expected, ok := headerValue(r.Headers, ChecksumHeader)
+	if !ok {
+		return fmt.Errorf("header %s is missing", ChecksumHeader)
+	}
+
+	body, err := decodeBody(r.Body, r.IsBase64Encoded)
+	if err != nil {
+		return fmt.Errorf("decodeBody: %w", err)
+	}
+
+	if actual := checksum(body); actual != expected {
+		return fmt.Errorf("%w: expected %s, actual %s", ErrChecksumMismatch, expected, actual)
+	}
+
+	return nil
+}
+
+func checksum(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func decodeBody(body string, isBase64Encoded bool) ([]byte, error) {
+	if !isBase64Encoded {
+		return []byte(body), nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, fmt.Errorf("base64.DecodeString: %w", err)
+	}
+
+	return decoded, nil
+}
+
+// headerValue looks up a header case-insensitively, as handlers are inconsistent about canonical names.
+func headerValue(headers map[string]string, name string) (string, bool) {
+	if v, ok := headers[name]; ok {
+		return v, true
+	}
+
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+
+	return "", false
+}
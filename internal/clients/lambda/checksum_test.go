@@ -0,0 +1,65 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestChecksumVerification(t *testing.T) {
+	handler := ChecksumHandler(func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "complete body"}, nil
+	})
+
+	tests := []struct {
+		name    string
+		corrupt func(r *events.APIGatewayProxyResponse)
+		wantErr error
+	}{
+		{
+			name:    "intact",
+			corrupt: func(r *events.APIGatewayProxyResponse) {},
+		},
+		{
+			name:    "truncated",
+			corrupt: func(r *events.APIGatewayProxyResponse) { r.Body = r.Body[:5] },
+			wantErr: ErrChecksumMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+				resp, err := handler(ctx, proxyRequest(in))
+				require.NoError(t, err)
+				tt.corrupt(&resp)
+
+				payload, err := json.Marshal(resp)
+				require.NoError(t, err)
+				return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, nil
+			}}
+
+			cli := newClient(api, testFunctionARN, WithChecksumVerification())
+
+			out, err := cli.Invoke(_ctx, "GET", "/", nil)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "complete body", out)
+		})
+	}
+}
+
+func TestChecksumVerification_MissingHeader(t *testing.T) {
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithChecksumVerification())
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	assert.ErrorContains(t, err, ChecksumHeader)
+}
@@ -0,0 +1,132 @@
+package lambda
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"strings"
+)
+
+// ClaimCheckHeader carries the "s3://bucket/key" location of a body offloaded by the claim-check,
+// the body itself is empty then.
+const ClaimCheckHeader = "X-Claim-Check"
+
+// ObjectStore stores offloaded bodies, typically it wraps PutObject and GetObject of an *s3.Client.
+type ObjectStore interface {
+	Put(ctx context.Context, bucket, key string, body []byte) error
+	Get(ctx context.Context, bucket, key string) ([]byte, error)
+}
+
+// ClaimCheck offloads bodies of at least Threshold bytes to Bucket and passes their location in ClaimCheckHeader,
+// to get past the invoke payload limit. Objects are not deleted, configure a bucket lifecycle rule for Prefix.
+type ClaimCheck struct {
+	Store     ObjectStore
+	Bucket    string
+	Prefix    string
+	Threshold int
+}
+
+// WithClaimCheck offloads large proxy request bodies and fetches offloaded response bodies,
+// use ClaimCheckHandler on the function side. The raw envelope is not supported.
+func WithClaimCheck(cc ClaimCheck) Option {
+	return func(c *client) {
+		c.claimCheck = &cc
+	}
+}
+
+// ClaimCheckHandler wraps the function side handler to fetch offloaded request bodies and offload large response bodies.
+func ClaimCheckHandler(cc ClaimCheck) func(next Handler) Handler {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			headers, body, err := cc.fetch(ctx, req.Headers, req.Body)
+			if err != nil {
+				return events.APIGatewayProxyResponse{}, fmt.Errorf("fetch: %w", err)
+			}
+			req.Headers, req.Body = headers, body
+
+			resp, err := next(ctx, req)
+			if err != nil {
+				return resp, err
+			}
+
+			key, err := randomKey()
+			if err != nil {
+				return resp, fmt.Errorf("randomKey: %w", err)
+			}
+
+			resp.Headers, resp.Body, err = cc.offload(ctx, resp.Headers, resp.Body, key)
+			if err != nil {
+				return resp, fmt.Errorf("offload: %w", err)
+			}
+
+			return resp, nil
+		}
+	}
+}
+
+// offload stores body if it exceeds the threshold and returns headers with its location and the empty body.
+func (cc *ClaimCheck) offload(ctx context.Context, headers map[string]string, body, key string) (map[string]string, string, error) {
+	if len(body) < cc.Threshold {
+		return headers, body, nil
+	}
+
+	key = cc.Prefix + key
+	if err := cc.Store.Put(ctx, cc.Bucket, key, []byte(body)); err != nil {
+		return headers, body, fmt.Errorf("store.Put: %w", err)
+	}
+
+	return withHeader(headers, ClaimCheckHeader, "s3://"+cc.Bucket+"/"+key), "", nil
+}
+
+// fetch replaces the body with the offloaded one if headers carry its location.
+func (cc *ClaimCheck) fetch(ctx context.Context, headers map[string]string, body string) (map[string]string, string, error) {
+	location, ok := headerValue(headers, ClaimCheckHeader)
+	if !ok {
+		return headers, body, nil
+	}
+
+	bucket, key, err := cc.parseLocation(location)
+	if err != nil {
+		return headers, body, err
+	}
+
+	fetched, err := cc.Store.Get(ctx, bucket, key)
+	if err != nil {
+		return headers, body, fmt.Errorf("store.Get: %w", err)
+	}
+
+	out := make(map[string]string, len(headers))
+	for k, v := range headers {
+		if !strings.EqualFold(k, ClaimCheckHeader) {
+			out[k] = v
+		}
+	}
+
+	return out, string(fetched), nil
+}
+
+// parseLocation splits the "s3://bucket/key" location and rejects objects outside Bucket and Prefix, so a forged
+// header cannot read arbitrary objects with the store credentials.
+func (cc *ClaimCheck) parseLocation(location string) (string, string, error) {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(location, "s3://"), "/")
+	if !ok || !strings.HasPrefix(location, "s3://") {
+		return "", "", fmt.Errorf("invalid %s: %s", ClaimCheckHeader, location)
+	}
+
+	if bucket != cc.Bucket || !strings.HasPrefix(key, cc.Prefix) || len(key) == len(cc.Prefix) {
+		return "", "", fmt.Errorf("%s outside s3://%s/%s: %s", ClaimCheckHeader, cc.Bucket, cc.Prefix, location)
+	}
+
+	return bucket, key, nil
+}
+
+func randomKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
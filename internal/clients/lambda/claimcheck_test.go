@@ -0,0 +1,119 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (m *memoryStore) Put(_ context.Context, bucket, key string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.objects == nil {
+		m.objects = make(map[string][]byte)
+	}
+	m.objects[bucket+"/"+key] = body
+
+	return nil
+}
+
+func (m *memoryStore) Get(_ context.Context, bucket, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	body, ok := m.objects[bucket+"/"+key]
+	if !ok {
+		return nil, errors.New("no such key")
+	}
+
+	return body, nil
+}
+
+func TestWithClaimCheck(t *testing.T) {
+	store := &memoryStore{}
+	cc := ClaimCheck{Store: store, Bucket: "payloads", Prefix: "invoker/", Threshold: 1024}
+	large := strings.Repeat("x", 2048)
+
+	var handled events.APIGatewayProxyRequest
+	handler := ClaimCheckHandler(cc)(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		handled = req
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: strings.ToUpper(req.Body)}, nil
+	})
+
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		resp, err := handler(ctx, proxyRequest(in))
+		require.NoError(t, err)
+		assert.Empty(t, resp.Body, "response is offloaded")
+
+		payload, err := json.Marshal(resp)
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, err
+	}}
+	cli := newClient(api, testFunctionARN, WithClaimCheck(cc))
+
+	body, err := cli.Invoke(_ctx, "POST", "/", []byte(large))
+	require.NoError(t, err)
+
+	assert.Equal(t, strings.ToUpper(large), body)
+	assert.Equal(t, large, handled.Body)
+	assert.NotContains(t, handled.Headers, ClaimCheckHeader)
+
+	sent := proxyRequest(api.calls()[0])
+	assert.Empty(t, sent.Body)
+	assert.True(t, strings.HasPrefix(sent.Headers[ClaimCheckHeader], "s3://payloads/invoker/"))
+	assert.Len(t, store.objects, 2)
+}
+
+func TestClaimCheckHandler_ForeignLocation(t *testing.T) {
+	store := &memoryStore{}
+	require.NoError(t, store.Put(_ctx, "secrets", "invoker/creds", []byte("secret")))
+	require.NoError(t, store.Put(_ctx, "payloads", "other/creds", []byte("secret")))
+	cc := ClaimCheck{Store: store, Bucket: "payloads", Prefix: "invoker/", Threshold: 1024}
+
+	handler := ClaimCheckHandler(cc)(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		t.Fatalf("handler must not run, got body %q", req.Body)
+		return events.APIGatewayProxyResponse{}, nil
+	})
+
+	tests := []struct {
+		name     string
+		location string
+	}{
+		{name: "foreign bucket", location: "s3://secrets/invoker/creds"},
+		{name: "foreign prefix", location: "s3://payloads/other/creds"},
+		{name: "bare prefix", location: "s3://payloads/invoker/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(_ctx, events.APIGatewayProxyRequest{Headers: map[string]string{ClaimCheckHeader: tt.location}})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "outside s3://payloads/invoker/")
+		})
+	}
+}
+
+func TestNewHTTPRequest_ClaimCheckHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	r.Header.Set(ClaimCheckHeader, "s3://payloads/invoker/key")
+
+	req, err := newHTTPRequest(r, "", MaxSyncPayloadSize)
+	require.NoError(t, err)
+
+	_, ok := headerValue(req.Headers, ClaimCheckHeader)
+	assert.False(t, ok, "HTTP callers cannot forge claim checks")
+	assert.NotContains(t, req.MultiValueHeaders, ClaimCheckHeader)
+}
@@ -2,126 +2,514 @@ package lambda
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/AlekSi/pointer"
 	"github.com/aws/aws-lambda-go/events"
-	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"io"
+	"iter"
+	"log/slog"
+	"maps"
 	"net/http"
+	"sync"
+	"time"
 )
 
 //go:generate mockgen -destination=./client_mock.go -package=lambda -mock_names Client=MockClient . Client
 type Client interface {
-	Invoke(ctx context.Context, httpMethod, path string, body []byte) (string, error)
-	InvokeAsync(ctx context.Context, httpMethod, path string, body []byte) error
+	Invoke(ctx context.Context, httpMethod, path string, body []byte, opts ...Option) (string, error)
+	InvokeReader(ctx context.Context, httpMethod, path string, r io.Reader, opts ...Option) (string, error)
+	InvokeAsync(ctx context.Context, httpMethod, path string, body []byte, opts ...Option) error
+	Do(ctx context.Context, req Request, opts ...Option) (*Response, error)
+	InvokeBinary(ctx context.Context, httpMethod, path string, body []byte, opts ...Option) ([]byte, error)
+	InvokeTo(ctx context.Context, httpMethod, path string, body []byte, w io.Writer, opts ...Option) error
+	InvokeBatch(ctx context.Context, requests []Request, concurrency int) ([]Result, error)
+	InvokeStreaming(ctx context.Context, in <-chan Request) <-chan Result
+	InvokeSeq(ctx context.Context, requests iter.Seq[Request]) iter.Seq[Result]
+	InvokeFuture(ctx context.Context, httpMethod, path string, body []byte, opts ...Option) *Future
+	Call(ctx context.Context, httpMethod, path string, in, out any, opts ...Option) error
+	InvokeWithCallback(ctx context.Context, req Request, callback func(Result))
+	FunctionARN(ctx context.Context) (string, error)
+	HealthCheck(ctx context.Context) error
+	Costs() CostStats
+	Close()
+}
+
+// lambdaAPI is the subset of *lambda.Client used by client.
+type lambdaAPI interface {
+	Invoke(ctx context.Context, params *lambda.InvokeInput, optFns ...func(*lambda.Options)) (*lambda.InvokeOutput, error)
+	GetFunction(ctx context.Context, params *lambda.GetFunctionInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error)
 }
 
 type client struct {
-	cli         *lambda.Client
+	cli         lambdaAPI
 	functionARN string
+
+	softCancel         *SoftCancel
+	rand               *Rand
+	verifyChecksum     bool
+	concurrency        int
+	routeCodecs        []routeCodec
+	codec              Codec
+	authorizer         Authorizer
+	interceptors       []Interceptor
+	eventBuilders      []EventBuilder
+	sdkRetryer         aws.Retryer
+	throttleRecorders  []ThrottleRecorder
+	budgetPropagation  bool
+	budgetFloor        time.Duration
+	cancelEvents       *CancelEvents
+	envelope           Envelope
+	logger             *slog.Logger
+	eventSinks         []EventSink
+	xrayPropagation    bool
+	correlationID      bool
+	redaction          bool
+	json               JSONEngine
+	acceptStatuses     []int
+	clientErrorsAsData bool
+	compressThreshold  int
+	claimCheck         *ClaimCheck
+	strictARN          bool
+	region             string
+	roleCredentials    *roleCredentials
+	credentialManager  *CredentialManager
+	asyncTransport     AsyncTransport
+	preflight          *preflight
+	canary             *Canary
+	balancer           *balancer
+	resolver           FunctionResolver
+	healthCheck        HealthCheckConfig
+	httpClient         func(*lambda.Options)
+	fipsEndpoint       bool
+	dualStackEndpoint  bool
+	call               callConfig
+	retryPolicy        *RetryPolicy
+	invoker            Invoker
+	costs              costTracker
+
+	resolveMu   sync.Mutex
+	resolvedARN string
+
+	dispatcherMu     sync.RWMutex
+	dispatcherOnce   sync.Once
+	dispatchers      sync.WaitGroup
+	enqueued         sync.WaitGroup
+	callbacks        chan callbackJob
+	dispatcherClosed bool
 }
 
-func New(cli *lambda.Client, functionARN string) (Client, error) {
+// New returns a client of the target function: a function name, a partial or a full ARN, optionally qualified.
+func New(cli *lambda.Client, target string, opts ...Option) (Client, error) {
 	if cli == nil {
 		return nil, fmt.Errorf("lambda.NewFromConfig returned nil")
 	}
 
-	if _, err := arn.Parse(functionARN); err != nil {
-		return nil, fmt.Errorf("arn.Parse[%s]: %w", functionARN, err)
+	c := newClient(cli, target, opts...)
+
+	if err := validateFunction(target, c.strictARN); err != nil {
+		return nil, fmt.Errorf("validateFunction: %w", err)
+	}
+
+	if err := c.validateAccount(); err != nil {
+		return nil, fmt.Errorf("c.validateAccount: %w", err)
 	}
 
-	return &client{
+	return c, nil
+}
+
+func newClient(cli lambdaAPI, functionARN string, opts ...Option) *client {
+	c := &client{
 		cli:         cli,
 		functionARN: functionARN,
-	}, nil
+		rand:        newRandomRand(),
+		concurrency: defaultConcurrency,
+		json:        defaultJSON,
+		call:        callConfig{logType: types.LogTypeNone},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.logger != nil {
+		c.logger = c.logger.With(slog.String("function_arn", c.redact(c.functionARN)))
+	}
+
+	invoke := c.invoke
+	if c.retryPolicy != nil && c.retryPolicy.MaxAttempts > 1 {
+		invoke = c.retrying(invoke)
+	}
+	if c.preflight != nil {
+		invoke = c.preflighted(invoke)
+	}
+	if c.canary != nil {
+		invoke = c.canaryRouted(invoke)
+	}
+	// invocations of balanced clients run on the target clients, they emit the state changes
+	if c.canary != nil && c.balancer == nil {
+		c.canary.subscribe(c)
+	}
+
+	c.invoker = recoverInvoker(chainInterceptors(c.interceptors, invoke))
+
+	return c
 }
 
 // Invoke synchronously invokes the Lambda function with the given HTTP method and body.
 // input body is wrapped in APIGatewayProxyRequest
 // output body is extracted from APIGatewayProxyResponse
-func (c *client) Invoke(ctx context.Context, httpMethod, path string, body []byte) (string, error) {
-	out, err := c.invoke(ctx, false, httpMethod, path, body)
+func (c *client) Invoke(ctx context.Context, httpMethod, path string, body []byte, opts ...Option) (string, error) {
+	ctx, err := c.withCallOptions(ctx, opts)
 	if err != nil {
 		return "", fmt.Errorf("invoke[sync]: %w", err)
 	}
 
-	return out, nil
+	resp, err := c.do(ctx, &Request{HTTPMethod: httpMethod, Path: path, Body: body})
+	if err != nil {
+		return "", fmt.Errorf("invoke[sync]: %w", err)
+	}
+
+	return resp.Body, nil
 }
 
-func (c *client) InvokeAsync(ctx context.Context, httpMethod, path string, body []byte) error {
-	if _, err := c.invoke(ctx, true, httpMethod, path, body); err != nil {
+func (c *client) InvokeAsync(ctx context.Context, httpMethod, path string, body []byte, opts ...Option) error {
+	ctx, err := c.withCallOptions(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("invoke[async]: %w", err)
+	}
+
+	if _, err := c.do(ctx, &Request{HTTPMethod: httpMethod, Path: path, Body: body, Async: true}); err != nil {
 		return fmt.Errorf("invoke[async]: %w", err)
 	}
 
 	return nil
 }
 
-func (c *client) invoke(ctx context.Context, async bool, httpMethod, path string, body []byte) (out string, err error) {
-	req := events.APIGatewayProxyRequest{
-		Path:       path,
-		HTTPMethod: httpMethod,
-		Body:       string(body),
+// Do invokes req and returns the unwrapped proxy response including status code and headers.
+// The returned Response is non-nil whenever Lambda responded, even if err is set, so that RequestID is available.
+func (c *client) Do(ctx context.Context, req Request, opts ...Option) (*Response, error) {
+	ctx, err := c.withCallOptions(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("invoke[%s]: %w", invocationMode(req.Async), err)
 	}
 
-	payload, err := json.Marshal(req)
+	resp, err := c.do(ctx, &req)
 	if err != nil {
-		return "", fmt.Errorf("json.Marshal: %w", err)
+		return resp, fmt.Errorf("invoke[%s]: %w", invocationMode(req.Async), err)
 	}
 
-	invocationType := types.InvocationTypeRequestResponse
+	return resp, nil
+}
+
+func invocationMode(async bool) string {
 	if async {
+		return "async"
+	}
+
+	return "sync"
+}
+
+// do passes req through the interceptor chain, sync invocations are abandoned on ctx cancellation if soft-cancel is configured.
+func (c *client) do(ctx context.Context, req *Request) (*Response, error) {
+	cfg := c.callConfig(ctx)
+	if c.balancer != nil {
+		return c.balancer.do(ctx, req, cfg)
+	}
+
+	if cfg.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+		defer cancel()
+	}
+
+	req.Headers = cfg.withCookies(cfg.withDefaultHeaders(req.Headers))
+
+	var id string
+	if c.correlationID {
+		ctx, id = c.withCorrelationID(ctx, req)
+	}
+
+	resp, err := c.dispatch(ctx, req)
+	if err == nil && cfg.jmesPath != "" && !req.Async {
+		if err = c.extract(cfg.jmesPath, resp); err != nil {
+			err = fmt.Errorf("jmespath[%s]: %w", cfg.jmesPath, err)
+		}
+	}
+	if err != nil && id != "" {
+		err = fmt.Errorf("correlation id %s: %w", id, err)
+	}
+
+	return resp, c.redactError(err)
+}
+
+// apiOptions returns the per-call options of Lambda API calls overriding the *lambda.Client ones.
+func (c *client) apiOptions() []func(*lambda.Options) {
+	var optFns []func(*lambda.Options)
+
+	if c.region != "" {
+		optFns = append(optFns, func(o *lambda.Options) { o.Region = c.region })
+	}
+	if c.fipsEndpoint || c.dualStackEndpoint {
+		optFns = append(optFns, c.endpointOption)
+	}
+	if c.httpClient != nil {
+		optFns = append(optFns, c.httpClient)
+	}
+	if c.roleCredentials != nil {
+		optFns = append(optFns, func(o *lambda.Options) { c.roleCredentials.apiOption(o, c.credentialManager) })
+	}
+	if c.sdkRetryer != nil {
+		optFns = append(optFns, func(o *lambda.Options) { o.Retryer = c.sdkRetryer })
+	}
+
+	return optFns
+}
+
+func (c *client) dispatch(ctx context.Context, req *Request) (*Response, error) {
+	if c.softCancel != nil && !req.Async {
+		return c.invokeSoftCancel(ctx, req)
+	}
+	if c.cancelEvents != nil && req.Async {
+		return c.invokeCancellable(ctx, req)
+	}
+
+	return c.invoker(ctx, req)
+}
+
+// invoke wraps req in APIGatewayProxyRequest and unwraps APIGatewayProxyResponse for sync invocations,
+// unless the raw envelope is configured.
+// The returned Response is non-nil whenever Lambda responded, even if err is set, so that RequestID is available.
+func (c *client) invoke(ctx context.Context, req *Request) (*Response, error) {
+	if err := c.checkBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	payload, err := c.newPayload(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("newPayload: %w", err)
+	}
+
+	if err := validatePayloadSize(payload, req.Async); err != nil {
+		return nil, fmt.Errorf("validatePayloadSize: %w", err)
+	}
+
+	if req.Async && c.asyncTransport != nil {
+		return c.sendAsync(ctx, payload)
+	}
+
+	cfg := c.callConfig(ctx)
+
+	invocationType := types.InvocationTypeRequestResponse
+	if req.Async {
 		invocationType = types.InvocationTypeEvent
 	}
 
-	output, err := c.cli.Invoke(ctx, &lambda.InvokeInput{
-		FunctionName:   pointer.To(c.functionARN),
+	optFns := c.apiOptions()
+	if c.xrayPropagation {
+		if optFn, ok := traceHeaderOption(ctx); ok {
+			optFns = append(optFns, optFn)
+		}
+	}
+	if c.observesThrottles() {
+		optFns = append(optFns, c.throttleObserverOption(ctx))
+	}
+
+	output, err := c.invokeResolved(ctx, &lambda.InvokeInput{
 		InvocationType: invocationType,
-		LogType:        types.LogTypeNone,
+		LogType:        cfg.logType,
 		Payload:        payload,
-	})
+		Qualifier:      pointer.ToStringOrNil(cfg.qualifier),
+	}, optFns)
+	resp := &Response{RequestID: awsRequestID(output, err)}
 	if err != nil {
-		return "", fmt.Errorf("cli.Invoke: %w", err)
+		return resp, invokeError(c.accessDeniedError(err))
 	}
 
 	if output == nil {
-		return "", fmt.Errorf("output is nil")
+		return resp, withKind(ErrorKindTransport, fmt.Errorf("output is nil"))
+	}
+
+	resp.ExecutedVersion = pointer.Get(output.ExecutedVersion)
+	resp.LogResult = decodeLogResult(output.LogResult)
+	if resp.Stats = parseReport(resp.LogResult); resp.Stats != nil {
+		resp.InitDuration = resp.Stats.InitDuration
+		resp.ColdStart = resp.InitDuration > 0
 	}
 
 	if output.FunctionError != nil {
-		return "", fmt.Errorf("output.FunctionError: %s", *output.FunctionError)
+		return resp, fmt.Errorf("output.FunctionError: %w", newInvocationError(*output.FunctionError, output.Payload))
 	}
 
 	expectedStatus := http.StatusOK
-	if async {
+	if req.Async {
 		expectedStatus = http.StatusAccepted
 	}
 
 	if output.StatusCode != int32(expectedStatus) {
-		return "", fmt.Errorf("output.StatusCode: %d", output.StatusCode)
+		return resp, fmt.Errorf("output: %w", &ErrUnexpectedStatus{Code: int(output.StatusCode), Body: string(output.Payload)})
 	}
 
-	if async {
+	if req.Async {
 		if len(output.Payload) != 0 {
-			return "", fmt.Errorf("output.Payload is not empty for async invocation: [%s]", output.Payload)
+			return resp, fmt.Errorf("output.Payload is not empty for async invocation: [%s]", output.Payload)
 		}
-		return "", nil
+		return resp, nil
 	}
 
 	// sync invocation continues here
 	if len(output.Payload) == 0 {
-		return "", fmt.Errorf("output.Payload is empty for sync invocation")
+		return resp, fmt.Errorf("output.Payload is empty for sync invocation")
+	}
+
+	if c.envelope == EnvelopeRaw {
+		resp.StatusCode = http.StatusOK
+		resp.Body = string(output.Payload)
+		return resp, nil
 	}
 
 	var r events.APIGatewayProxyResponse
-	if err := json.Unmarshal(output.Payload, &r); err != nil {
-		return "", fmt.Errorf("json.Unmarshal: %w", err)
+	if err := c.json.Unmarshal(output.Payload, &r); err != nil {
+		return resp, withKind(ErrorKindMarshal, fmt.Errorf("json.Unmarshal: %w", err))
+	}
+
+	if c.claimCheck != nil && !c.streamsBody(ctx) {
+		r.Headers, r.Body, err = c.claimCheck.fetch(ctx, r.Headers, r.Body)
+		if err != nil {
+			return resp, fmt.Errorf("claimCheck.fetch: %w", err)
+		}
+	}
+
+	resp.StatusCode = r.StatusCode
+	resp.Headers = r.Headers
+	resp.MultiValueHeaders = r.MultiValueHeaders
+	resp.Body = r.Body
+	resp.IsBase64Encoded = r.IsBase64Encoded
+	if r.IsBase64Encoded {
+		if resp.Data, err = decodeBody(r.Body, true); err != nil {
+			return resp, withKind(ErrorKindMarshal, fmt.Errorf("decodeBody: %w", err))
+		}
+	}
+
+	if !c.accepts(r.StatusCode) {
+		return resp, fmt.Errorf("response: %w", &ErrUnexpectedStatus{Code: r.StatusCode, Body: r.Body})
+	}
+
+	if c.verifyChecksum {
+		if err := verifyChecksum(r); err != nil {
+			return resp, fmt.Errorf("verifyChecksum: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+func (c *client) newPayload(ctx context.Context, req *Request) ([]byte, error) {
+	if c.envelope == EnvelopeRaw {
+		return req.Body, nil
+	}
+
+	event, err := c.newEvent(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("newEvent: %w", err)
+	}
+
+	payload, err := c.json.Marshal(event)
+	if err != nil {
+		return nil, withKind(ErrorKindMarshal, fmt.Errorf("json.Marshal: %w", err))
+	}
+
+	return payload, nil
+}
+
+// newEvent builds the APIGatewayProxyRequest the way API Gateway would for req.
+func (c *client) newEvent(ctx context.Context, req *Request) (events.APIGatewayProxyRequest, error) {
+	event := events.APIGatewayProxyRequest{
+		Path:            req.Path,
+		HTTPMethod:      req.HTTPMethod,
+		Headers:         req.Headers,
+		Body:            string(req.Body),
+		IsBase64Encoded: req.IsBase64Encoded,
+	}
+
+	if len(req.MultiValueHeaders) > 0 {
+		event.Headers, event.MultiValueHeaders = mergeMultiValueHeaders(req.Headers, req.MultiValueHeaders)
+	}
+
+	if c.budgetPropagation {
+		event.Headers = withBudget(ctx, event.Headers)
+	}
+
+	if len(req.Query) > 0 {
+		event.QueryStringParameters = make(map[string]string, len(req.Query))
+		event.MultiValueQueryStringParameters = req.Query
+		for name, values := range req.Query {
+			if len(values) > 0 {
+				event.QueryStringParameters[name] = values[len(values)-1]
+			}
+		}
+	}
+
+	cfg := c.callConfig(ctx)
+	event.RequestContext.Identity = cfg.identity
+	event.StageVariables = cfg.stageVariables
+
+	if c.authorizer != nil {
+		authorizerContext, err := c.authorizer.Authorize(ctx, *req)
+		if err != nil {
+			return event, fmt.Errorf("authorizer.Authorize: %w", err)
+		}
+		event.RequestContext.Authorizer = authorizerContext
+	}
+	event.RequestContext.Authorizer = cfg.withAuthorizerContext(event.RequestContext.Authorizer)
+
+	if id, ok := CorrelationIDFromContext(ctx); ok && c.correlationID {
+		event.RequestContext.RequestID = id
+	}
+
+	if len(c.eventBuilders) > 0 {
+		// builders may modify headers of the caller's request otherwise
+		event.Headers = maps.Clone(event.Headers)
+		if event.Headers == nil {
+			event.Headers = make(map[string]string)
+		}
+		for _, build := range c.eventBuilders {
+			build(ctx, &event)
+		}
+	}
+
+	if err := c.compress(&event); err != nil {
+		return event, fmt.Errorf("compress: %w", err)
+	}
+
+	if c.claimCheck != nil {
+		var err error
+		event.Headers, event.Body, err = c.claimCheck.offload(ctx, event.Headers, event.Body, c.newUUID())
+		if err != nil {
+			return event, fmt.Errorf("claimCheck.offload: %w", err)
+		}
+	}
+
+	return event, nil
+}
+
+// awsRequestID extracts the AWS request ID from either a successful output or a response error.
+func awsRequestID(output *lambda.InvokeOutput, err error) string {
+	if output != nil {
+		if id, ok := awsmiddleware.GetRequestIDMetadata(output.ResultMetadata); ok {
+			return id
+		}
 	}
 
-	if r.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("response statusCode: %d", r.StatusCode)
+	var re *awshttp.ResponseError
+	if errors.As(err, &re) {
+		return re.ServiceRequestID()
 	}
 
-	return r.Body, nil
+	return ""
 }
@@ -0,0 +1,199 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// Codec encodes request bodies and decodes response bodies of Client.Call.
+// Implement it to use protobuf, msgpack or any other body encoding.
+type Codec interface {
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+var (
+	// JSONCodec encodes bodies with encoding/json, it is the default codec.
+	JSONCodec Codec = jsonCodec{}
+
+	// BinaryCodec passes []byte bodies through as is, responses are decoded into *[]byte.
+	BinaryCodec Codec = binaryCodec{}
+)
+
+// WithCodec sets the codec used by Client.Call for routes without a codec registered with WithRouteCodec,
+// JSONCodec by default.
+func WithCodec(codec Codec) Option {
+	return func(c *client) {
+		c.codec = codec
+	}
+}
+
+type routeCodec struct {
+	route route
+	codec Codec
+}
+
+// WithRouteCodec registers the codec used by Client.Call for requests matching the pattern,
+// e.g. "POST /orders/{id}" or "GET /downloads/{key+}". Routes are matched in registration order.
+// It panics if the pattern is invalid.
+func WithRouteCodec(pattern string, codec Codec) Option {
+	r := mustParseRoute(pattern)
+
+	return func(c *client) {
+		c.routeCodecs = append(c.routeCodecs, routeCodec{route: r, codec: codec})
+	}
+}
+
+// Call synchronously invokes the function with in encoded by the codec registered for the route
+// and decodes the response body into out, unless out is nil.
+// Content-Type and Accept are set to the codec content type, the response Content-Type must match it.
+// Bodies of non-textual content types are base64 encoded in the proxy envelope.
+func (c *client) Call(ctx context.Context, httpMethod, path string, in, out any, opts ...Option) error {
+	codec := c.codecFor(httpMethod, path)
+
+	req := &Request{
+		HTTPMethod: httpMethod,
+		Path:       path,
+		Headers:    map[string]string{"Content-Type": codec.ContentType(), "Accept": codec.ContentType()},
+	}
+
+	if in != nil {
+		body, err := codec.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("codec.Marshal: %w", err)
+		}
+
+		req.Body = body
+		if !isTextContentType(codec.ContentType()) {
+			req.Body = []byte(base64.StdEncoding.EncodeToString(body))
+			req.IsBase64Encoded = true
+		}
+	}
+
+	ctx, err := c.withCallOptions(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("invoke[sync]: %w", err)
+	}
+
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("invoke[sync]: %w", err)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := negotiate(codec, resp.Headers); err != nil {
+		return fmt.Errorf("negotiate: %w", err)
+	}
+
+	body, err := resp.Bytes()
+	if err != nil {
+		return fmt.Errorf("resp.Bytes: %w", err)
+	}
+
+	if err := codec.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("codec.Unmarshal: %w", err)
+	}
+
+	return nil
+}
+
+// CallAs is Client.Call with the response type as a type parameter.
+func CallAs[Out any](ctx context.Context, c Client, httpMethod, path string, in any, opts ...Option) (Out, error) {
+	var out Out
+	if err := c.Call(ctx, httpMethod, path, in, &out, opts...); err != nil {
+		return out, err
+	}
+
+	return out, nil
+}
+
+func (c *client) codecFor(httpMethod, path string) Codec {
+	for _, rc := range c.routeCodecs {
+		if _, ok := rc.route.match(httpMethod, path); ok {
+			return rc.codec
+		}
+	}
+
+	if c.codec != nil {
+		return c.codec
+	}
+
+	return JSONCodec
+}
+
+// isTextContentType reports whether bodies of the content type can be sent unencoded in the proxy envelope.
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/json",
+		mediaType == "application/xml",
+		mediaType == "application/x-www-form-urlencoded",
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+
+	return false
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) ContentType() string {
+	return "application/json"
+}
+
+func (jsonCodec) Accepts(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+type binaryCodec struct{}
+
+func (binaryCodec) ContentType() string {
+	return "application/octet-stream"
+}
+
+// Accepts any media type, bodies are passed through as is.
+func (binaryCodec) Accepts(string) bool {
+	return true
+}
+
+func (binaryCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("binary codec: unsupported type %T, expected []byte", v)
+	}
+
+	return b, nil
+}
+
+func (binaryCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("binary codec: unsupported type %T, expected *[]byte", v)
+	}
+
+	*b = append((*b)[:0], data...)
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestCall_RouteCodecs(t *testing.T) {
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		req := proxyRequest(in)
+
+		resp := events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Content-Type": req.Headers["Content-Type"]},
+			Body:       req.Body,
+		}
+		if req.Path == "/downloads/report.bin" {
+			resp.Body = base64.StdEncoding.EncodeToString([]byte{0xde, 0xad, 0xbe, 0xef})
+			resp.IsBase64Encoded = true
+		}
+
+		payload, err := json.Marshal(resp)
+		require.NoError(t, err)
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, nil
+	}}
+
+	cli := newClient(api, testFunctionARN,
+		WithRouteCodec("GET /downloads/{key+}", BinaryCodec),
+		WithRouteCodec("PUT /uploads/{key}", BinaryCodec),
+	)
+
+	type order struct {
+		ID string `json:"id"`
+	}
+
+	var o order
+	require.NoError(t, cli.Call(_ctx, "POST", "/orders", order{ID: "42"}, &o))
+	assert.Equal(t, "42", o.ID)
+
+	var download []byte
+	require.NoError(t, cli.Call(_ctx, "GET", "/downloads/report.bin", nil, &download))
+	assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, download)
+
+	require.NoError(t, cli.Call(_ctx, "PUT", "/uploads/report.bin", []byte{0x00, 0xff}, nil))
+
+	calls := api.calls()
+	require.Len(t, calls, 3)
+
+	orderReq := proxyRequest(calls[0])
+	assert.Equal(t, "application/json", orderReq.Headers["Content-Type"])
+	assert.False(t, orderReq.IsBase64Encoded)
+
+	uploadReq := proxyRequest(calls[2])
+	assert.Equal(t, "application/octet-stream", uploadReq.Headers["Content-Type"])
+	assert.True(t, uploadReq.IsBase64Encoded)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte{0x00, 0xff}), uploadReq.Body)
+}
+
+// gobCodec stands for protobuf or msgpack codecs of binary bodies.
+type gobCodec struct{}
+
+func (gobCodec) ContentType() string {
+	return "application/x-gob"
+}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(v)
+	return buf.Bytes(), err
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func TestCallAs_WithCodec(t *testing.T) {
+	type order struct {
+		ID    string
+		Total int
+	}
+
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		req := proxyRequest(in)
+		require.True(t, req.IsBase64Encoded)
+
+		payload, err := json.Marshal(events.APIGatewayProxyResponse{
+			StatusCode:      http.StatusOK,
+			Headers:         map[string]string{"Content-Type": req.Headers["Content-Type"]},
+			Body:            req.Body,
+			IsBase64Encoded: true,
+		})
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, err
+	}}
+	cli := newClient(api, testFunctionARN, WithCodec(gobCodec{}))
+
+	got, err := CallAs[order](_ctx, cli, "POST", "/orders", order{ID: "42", Total: 7})
+	require.NoError(t, err)
+	assert.Equal(t, order{ID: "42", Total: 7}, got)
+
+	assert.Equal(t, "application/x-gob", proxyRequest(api.calls()[0]).Headers["Content-Type"])
+}
+
+func TestCall_Negotiation(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		contentType := "application/problem+json"
+		if proxyRequest(in).Path == "/html" {
+			contentType = "text/html; charset=utf-8"
+		}
+
+		payload, err := json.Marshal(events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"content-type": contentType},
+			Body:       `{"title":"ok"}`,
+		})
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, err
+	}}
+	cli := newClient(api, testFunctionARN)
+
+	var out map[string]string
+	require.NoError(t, cli.Call(_ctx, "GET", "/json", nil, &out))
+	assert.Equal(t, "ok", out["title"])
+	assert.Equal(t, "application/json", proxyRequest(api.calls()[0]).Headers["Accept"])
+
+	err := cli.Call(_ctx, "GET", "/html", nil, &out)
+	require.ErrorIs(t, err, ErrContentTypeMismatch)
+	assert.ErrorContains(t, err, "expected application/json, got text/html; charset=utf-8")
+}
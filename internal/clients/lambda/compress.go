@@ -0,0 +1,114 @@
+package lambda
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// ContentEncodingHeader is set to "gzip" on compressed proxy requests.
+	ContentEncodingHeader = "Content-Encoding"
+	// CompressedBinaryHeader is set to "true" on compressed proxy requests whose body was base64 encoded,
+	// so that DecompressHandler encodes the decompressed body again.
+	CompressedBinaryHeader = "X-Compressed-Binary"
+)
+
+// maxDecompressedBody bounds the body inflated by DecompressHandler, as a small gzip bomb fits in any payload.
+const maxDecompressedBody = 64 << 20
+
+// WithRequestCompression gzips proxy request bodies of at least threshold bytes to stay under the invoke payload limit,
+// compressed bodies are base64 encoded and marked with ContentEncodingHeader. Use DecompressHandler on the function side.
+// The raw envelope is not compressed.
+func WithRequestCompression(threshold int) Option {
+	return func(c *client) {
+		c.compressThreshold = threshold
+	}
+}
+
+// compress gzips the body of event if it exceeds the threshold.
+func (c *client) compress(event *events.APIGatewayProxyRequest) error {
+	if c.compressThreshold <= 0 || len(event.Body) < c.compressThreshold {
+		return nil
+	}
+
+	if _, ok := headerValue(event.Headers, ContentEncodingHeader); ok {
+		return nil
+	}
+
+	body, err := decodeBody(event.Body, event.IsBase64Encoded)
+	if err != nil {
+		return fmt.Errorf("decodeBody: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return fmt.Errorf("zw.Write: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("zw.Close: %w", err)
+	}
+
+	event.Headers = withHeader(event.Headers, ContentEncodingHeader, "gzip")
+	if event.IsBase64Encoded {
+		event.Headers[CompressedBinaryHeader] = "true"
+	}
+	event.Body = base64.StdEncoding.EncodeToString(buf.Bytes())
+	event.IsBase64Encoded = true
+
+	return nil
+}
+
+// DecompressHandler wraps the function side handler to decompress request bodies gzipped by WithRequestCompression.
+// Binary bodies are passed base64 encoded as they were sent, decompressed bodies over 64 MiB are rejected.
+func DecompressHandler(next Handler) Handler {
+	return func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		if encoding, _ := headerValue(req.Headers, ContentEncodingHeader); !strings.EqualFold(encoding, "gzip") {
+			return next(ctx, req)
+		}
+
+		body, err := decodeBody(req.Body, req.IsBase64Encoded)
+		if err != nil {
+			return events.APIGatewayProxyResponse{}, fmt.Errorf("decodeBody: %w", err)
+		}
+
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return events.APIGatewayProxyResponse{}, fmt.Errorf("gzip.NewReader: %w", err)
+		}
+
+		decompressed, err := io.ReadAll(io.LimitReader(zr, maxDecompressedBody+1))
+		if err != nil {
+			return events.APIGatewayProxyResponse{}, fmt.Errorf("io.ReadAll: %w", err)
+		}
+		if len(decompressed) > maxDecompressedBody {
+			return events.APIGatewayProxyResponse{}, fmt.Errorf("decompressed body exceeds %d bytes", maxDecompressedBody)
+		}
+
+		binary, _ := headerValue(req.Headers, CompressedBinaryHeader)
+
+		headers := make(map[string]string, len(req.Headers))
+		for k, v := range req.Headers {
+			if !strings.EqualFold(k, ContentEncodingHeader) && !strings.EqualFold(k, CompressedBinaryHeader) {
+				headers[k] = v
+			}
+		}
+
+		req.Headers = headers
+		req.IsBase64Encoded = binary == "true" || !utf8.Valid(decompressed)
+		if req.IsBase64Encoded {
+			req.Body = base64.StdEncoding.EncodeToString(decompressed)
+		} else {
+			req.Body = string(decompressed)
+		}
+
+		return next(ctx, req)
+	}
+}
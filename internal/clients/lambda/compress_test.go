@@ -0,0 +1,95 @@
+package lambda
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWithRequestCompression(t *testing.T) {
+	large := strings.Repeat(`{"key":"value"},`, 1000)
+
+	var handled []events.APIGatewayProxyRequest
+	handler := DecompressHandler(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		handled = append(handled, req)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: "ok"}, nil
+	})
+
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		resp, err := handler(ctx, proxyRequest(in))
+		require.NoError(t, err)
+		return proxyOutput(in, resp.StatusCode, resp.Body), nil
+	}}
+	cli := newClient(api, testFunctionARN, WithRequestCompression(1024))
+
+	_, err := cli.Invoke(_ctx, "POST", "/", []byte(large))
+	require.NoError(t, err)
+	_, err = cli.Invoke(_ctx, "POST", "/", []byte("small"))
+	require.NoError(t, err)
+
+	sent := proxyRequest(api.calls()[0])
+	assert.Equal(t, "gzip", sent.Headers[ContentEncodingHeader])
+	assert.True(t, sent.IsBase64Encoded)
+	assert.Less(t, len(sent.Body), len(large)/10)
+
+	assert.Equal(t, large, handled[0].Body)
+	assert.False(t, handled[0].IsBase64Encoded)
+	assert.NotContains(t, handled[0].Headers, ContentEncodingHeader)
+
+	assert.Empty(t, proxyRequest(api.calls()[1]).Headers[ContentEncodingHeader])
+	assert.Equal(t, "small", handled[1].Body)
+}
+
+func TestDecompressHandler_Binary(t *testing.T) {
+	binary := bytes.Repeat([]byte{0x00, 0xff}, 1024)
+
+	var handled events.APIGatewayProxyRequest
+	handler := DecompressHandler(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		handled = req
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		resp, err := handler(ctx, proxyRequest(in))
+		require.NoError(t, err)
+		return proxyOutput(in, resp.StatusCode, resp.Body), nil
+	}}
+	cli := newClient(api, testFunctionARN, WithRequestCompression(1024))
+
+	_, err := cli.InvokeBinary(_ctx, "POST", "/", binary)
+	require.NoError(t, err)
+
+	require.True(t, handled.IsBase64Encoded)
+	decoded, err := base64.StdEncoding.DecodeString(handled.Body)
+	require.NoError(t, err)
+	assert.Equal(t, binary, decoded)
+	assert.NotContains(t, handled.Headers, CompressedBinaryHeader)
+}
+
+func TestDecompressHandler_Limit(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(make([]byte, maxDecompressedBody+1))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	handler := DecompressHandler(func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		t.Fatal("handler must not run")
+		return events.APIGatewayProxyResponse{}, nil
+	})
+
+	_, err = handler(_ctx, events.APIGatewayProxyRequest{
+		Headers:         map[string]string{ContentEncodingHeader: "gzip"},
+		Body:            base64.StdEncoding.EncodeToString(buf.Bytes()),
+		IsBase64Encoded: true,
+	})
+	assert.ErrorContains(t, err, "exceeds")
+}
@@ -0,0 +1,191 @@
+package lambda
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Config declares named functions, see LoadConfig:
+//
+//	defaults:
+//	  region: eu-central-1
+//	  timeout: 5s
+//	functions:
+//	  orders:
+//	    function: orders-service
+//	    qualifier: live
+//	    retry: {maxAttempts: 3, baseDelay: 100ms}
+//	  billing:
+//	    function: arn:aws:lambda:eu-central-1:111111111111:function:billing
+//	    role: {arn: arn:aws:iam::111111111111:role/invoker, externalId: orders}
+type Config struct {
+	// Defaults apply to every function, function fields override them.
+	Defaults  FunctionConfig            `json:"defaults" yaml:"defaults"`
+	Functions map[string]FunctionConfig `json:"functions" yaml:"functions"`
+}
+
+// FunctionConfig declares a function, zero fields are not configured.
+type FunctionConfig struct {
+	// Function is a function name, a partial or a full ARN.
+	Function  string       `json:"function" yaml:"function"`
+	Region    string       `json:"region" yaml:"region"`
+	Qualifier string       `json:"qualifier" yaml:"qualifier"`
+	Timeout   Duration     `json:"timeout" yaml:"timeout"`
+	Retry     *RetryConfig `json:"retry" yaml:"retry"`
+	// Role is assumed to invoke the function, e.g. owned by another account.
+	Role *RoleConfig `json:"role" yaml:"role"`
+}
+
+// RoleConfig declares an AssumeRole.
+type RoleConfig struct {
+	ARN         string   `json:"arn" yaml:"arn"`
+	ExternalID  string   `json:"externalId" yaml:"externalId"`
+	SessionName string   `json:"sessionName" yaml:"sessionName"`
+	Duration    Duration `json:"duration" yaml:"duration"`
+}
+
+// RetryConfig declares a RetryPolicy with IsRetryable errors retried.
+type RetryConfig struct {
+	MaxAttempts int      `json:"maxAttempts" yaml:"maxAttempts"`
+	BaseDelay   Duration `json:"baseDelay" yaml:"baseDelay"`
+	MaxDelay    Duration `json:"maxDelay" yaml:"maxDelay"`
+}
+
+// Duration is a time.Duration written as a string such as "1.5s" in config files.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// LoadConfig reads a YAML (.yaml, .yml) or JSON config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	var cfg Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &cfg)
+	default:
+		err = json.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal[%s]: %w", path, err)
+	}
+
+	return &cfg, nil
+}
+
+// NewRegistryFromConfig returns a registry of the functions of the config file, opts are applied to every function
+// before the configured ones.
+func NewRegistryFromConfig(cli *lambda.Client, path string, opts ...Option) (*Registry, error) {
+	r, err := NewRegistry(cli, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("NewRegistry: %w", err)
+	}
+
+	if err := r.RegisterConfig(path); err != nil {
+		return nil, fmt.Errorf("r.RegisterConfig: %w", err)
+	}
+
+	return r, nil
+}
+
+// RegisterConfig registers every function of the config file under its name.
+func (r *Registry) RegisterConfig(path string, opts ...Option) error {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return fmt.Errorf("LoadConfig: %w", err)
+	}
+
+	for name, fc := range cfg.Functions {
+		fc = fc.withDefaults(cfg.Defaults)
+		if fc.Function == "" {
+			return fmt.Errorf("function[%s]: function is empty", name)
+		}
+
+		if err := r.Register(name, fc.Function, append(fc.options(), opts...)...); err != nil {
+			return fmt.Errorf("r.Register[%s]: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (fc FunctionConfig) withDefaults(defaults FunctionConfig) FunctionConfig {
+	if fc.Region == "" {
+		fc.Region = defaults.Region
+	}
+	if fc.Qualifier == "" {
+		fc.Qualifier = defaults.Qualifier
+	}
+	if fc.Timeout == 0 {
+		fc.Timeout = defaults.Timeout
+	}
+	if fc.Retry == nil {
+		fc.Retry = defaults.Retry
+	}
+	if fc.Role == nil {
+		fc.Role = defaults.Role
+	}
+
+	return fc
+}
+
+func (fc FunctionConfig) options() []Option {
+	var opts []Option
+
+	if fc.Region != "" {
+		opts = append(opts, WithRegion(fc.Region))
+	}
+	if fc.Qualifier != "" {
+		opts = append(opts, WithQualifier(fc.Qualifier))
+	}
+	if fc.Timeout > 0 {
+		opts = append(opts, WithTimeout(time.Duration(fc.Timeout)))
+	}
+	if fc.Retry != nil {
+		opts = append(opts, WithRetryPolicy(RetryPolicy{
+			MaxAttempts: fc.Retry.MaxAttempts,
+			BaseDelay:   time.Duration(fc.Retry.BaseDelay),
+			MaxDelay:    time.Duration(fc.Retry.MaxDelay),
+		}))
+	}
+	if fc.Role != nil {
+		opts = append(opts, WithAssumeRole(AssumeRole{
+			RoleARN:     fc.Role.ARN,
+			ExternalID:  fc.Role.ExternalID,
+			SessionName: fc.Role.SessionName,
+			Duration:    time.Duration(fc.Role.Duration),
+		}))
+	}
+
+	return opts
+}
+
+// WithRegion overrides the region of the *lambda.Client for calls of the function.
+func WithRegion(region string) Option {
+	return func(c *client) {
+		c.region = region
+	}
+}
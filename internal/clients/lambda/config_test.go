@@ -0,0 +1,69 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cfg, err := LoadConfig("testdata/functions.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, FunctionConfig{
+		Region:  "eu-central-1",
+		Timeout: Duration(5 * time.Second),
+		Retry:   &RetryConfig{MaxAttempts: 3, BaseDelay: Duration(time.Millisecond)},
+	}, cfg.Defaults)
+	assert.Equal(t, FunctionConfig{
+		Function: "arn:aws:lambda:eu-west-1:000000000000:function:payments",
+		Region:   "eu-west-1",
+		Timeout:  Duration(1500 * time.Millisecond),
+	}, cfg.Functions["payments"])
+
+	cfg, err = LoadConfig("testdata/functions.json")
+	require.NoError(t, err)
+	assert.Equal(t, FunctionConfig{Function: "orders", Qualifier: "live", Timeout: Duration(2 * time.Second)}, cfg.Functions["orders"])
+}
+
+func TestRegistry_RegisterConfig(t *testing.T) {
+	var attempts int
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(5*time.Second), deadline, time.Second)
+
+		if attempts++; attempts < 3 {
+			return nil, errors.New("connection reset")
+		}
+		return proxyOutput(in, 200, "ok"), nil
+	}}
+
+	registry := newRegistry(api)
+	require.NoError(t, registry.RegisterConfig("testdata/functions.yaml"))
+	assert.Equal(t, []string{"orders", "payments"}, registry.Names())
+
+	_, err := registry.Invoke(_ctx, "orders", "GET", "/", nil)
+	require.NoError(t, err, "retried with the default policy")
+
+	in := api.calls()[0]
+	assert.Equal(t, "orders", *in.FunctionName)
+	assert.Equal(t, "live", *in.Qualifier)
+	assert.Equal(t, "eu-central-1", api.options(0).Region)
+}
+
+func TestRegistry_RegisterConfig_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "functions.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("functions:\n  orders:\n    qualifier: live\n"), 0o600))
+
+	require.Error(t, newRegistry(&fakeAPI{}).RegisterConfig(path))
+
+	require.NoError(t, os.WriteFile(path, []byte("functions:\n  orders:\n    function: orders\n    timeout: soon\n"), 0o600))
+	require.Error(t, newRegistry(&fakeAPI{}).RegisterConfig(path))
+}
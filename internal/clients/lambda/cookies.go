@@ -0,0 +1,47 @@
+package lambda
+
+import (
+	"maps"
+	"net/http"
+	"strings"
+)
+
+// WithCookie adds a cookie to the Cookie header of the proxy request, after the cookies of the request header.
+// Invalid cookies are dropped.
+func WithCookie(cookie *http.Cookie) Option {
+	return func(c *client) {
+		c.call.cookies = append(c.call.cookies, cookie)
+	}
+}
+
+// withCookies returns headers with the cookies appended to the Cookie header, the caller's map is not modified.
+func (cfg callConfig) withCookies(headers map[string]string) map[string]string {
+	if len(cfg.cookies) == 0 {
+		return headers
+	}
+
+	var pairs []string
+	if cookie := headers["Cookie"]; cookie != "" {
+		pairs = append(pairs, cookie)
+	}
+
+	for _, cookie := range cfg.cookies {
+		if cookie.Valid() != nil {
+			continue
+		}
+		pairs = append(pairs, (&http.Cookie{Name: cookie.Name, Value: cookie.Value, Quoted: cookie.Quoted}).String())
+	}
+
+	out := maps.Clone(headers)
+	if out == nil {
+		out = make(map[string]string, 1)
+	}
+	out["Cookie"] = strings.Join(pairs, "; ")
+
+	return out
+}
+
+// Cookies parses the Set-Cookie headers of the response, invalid ones are dropped.
+func (r *Response) Cookies() []*http.Cookie {
+	return (&http.Response{Header: r.HTTPHeader()}).Cookies()
+}
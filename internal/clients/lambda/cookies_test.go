@@ -0,0 +1,46 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestWithCookie(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		req := proxyRequest(in)
+
+		assert.Equal(t, "theme=dark; session=abc; lang=en", req.Headers["Cookie"])
+
+		payload, err := json.Marshal(events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			MultiValueHeaders: map[string][]string{
+				"Set-Cookie": {"session=def; Path=/; HttpOnly", "lang=de"},
+			},
+		})
+		require.NoError(t, err)
+
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, nil
+	}}
+
+	c := newClient(api, testFunctionARN, WithCookie(&http.Cookie{Name: "session", Value: "abc"}))
+
+	headers := map[string]string{"Cookie": "theme=dark"}
+	resp, err := c.Do(_ctx, Request{HTTPMethod: http.MethodGet, Headers: headers},
+		WithCookie(&http.Cookie{Name: "lang", Value: "en"}), WithCookie(&http.Cookie{Name: "in valid"}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "theme=dark", headers["Cookie"])
+
+	cookies := resp.Cookies()
+	require.Len(t, cookies, 2)
+	assert.Equal(t, "session", cookies[0].Name)
+	assert.Equal(t, "def", cookies[0].Value)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, "lang", cookies[1].Name)
+}
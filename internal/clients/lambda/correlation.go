@@ -0,0 +1,73 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// CorrelationIDHeader is the proxy request header carrying the correlation ID.
+const CorrelationIDHeader = "X-Correlation-Id"
+
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a context carrying the correlation ID.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID set by ContextWithCorrelationID.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// WithCorrelationID takes the correlation ID from the context or generates a UUID, and injects it
+// into the proxy request as the CorrelationIDHeader header and the request context request ID.
+// The ID is added to the context seen by interceptors, to WithLogger logs and to returned errors.
+func WithCorrelationID() Option {
+	return func(c *client) {
+		c.correlationID = true
+	}
+}
+
+// withCorrelationID ensures ctx and req carry a correlation ID.
+func (c *client) withCorrelationID(ctx context.Context, req *Request) (context.Context, string) {
+	id, ok := CorrelationIDFromContext(ctx)
+	if !ok {
+		id = c.newUUID()
+		ctx = ContextWithCorrelationID(ctx, id)
+	}
+
+	req.Headers = withHeader(req.Headers, CorrelationIDHeader, id)
+
+	return ctx, id
+}
+
+// newUUID generates a version 4 UUID from the client Rand.
+func (c *client) newUUID() string {
+	return randomUUID(c.rand)
+}
+
+func randomUUID(r *Rand) string {
+	var b [16]byte
+	for i := 0; i < len(b); i += 8 {
+		u := r.Uint64()
+		for j := range 8 {
+			b[i+j] = byte(u >> (8 * j))
+		}
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// withHeader returns a copy of headers with the header set, the caller's map is not modified.
+func withHeader(headers map[string]string, name, value string) map[string]string {
+	out := make(map[string]string, len(headers)+1)
+	maps.Copy(out, headers)
+	out[name] = value
+
+	return out
+}
@@ -0,0 +1,56 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func TestWithCorrelationID(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithCorrelationID())
+
+	_, err := cli.Invoke(ContextWithCorrelationID(_ctx, "corr-1"), "GET", "/", nil)
+	require.NoError(t, err)
+
+	event := proxyRequest(api.calls()[0])
+	assert.Equal(t, "corr-1", event.Headers[CorrelationIDHeader])
+	assert.Equal(t, "corr-1", event.RequestContext.RequestID)
+}
+
+func TestWithCorrelationID_Generated(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithCorrelationID(), WithRand(NewRand(1)))
+
+	headers := map[string]string{"Accept": "application/json"}
+	require.NoError(t, cli.InvokeAsync(_ctx, "POST", "/", nil))
+	_, err := cli.InvokeBatch(_ctx, []Request{{HTTPMethod: "GET", Path: "/", Headers: headers}}, 1)
+	require.NoError(t, err)
+
+	first := proxyRequest(api.calls()[0]).Headers[CorrelationIDHeader]
+	second := proxyRequest(api.calls()[1]).Headers[CorrelationIDHeader]
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, first)
+	assert.NotEqual(t, first, second)
+	assert.Len(t, headers, 1, "caller headers must not be modified")
+}
+
+func TestWithCorrelationID_LogsAndErrors(t *testing.T) {
+	api := &fakeAPI{invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, errors.New("boom")
+	}}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	cli := newClient(api, testFunctionARN, WithCorrelationID(), WithLogger(logger))
+
+	_, err := cli.Invoke(ContextWithCorrelationID(_ctx, "corr-1"), "GET", "/", nil)
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "correlation id corr-1")
+	assert.Contains(t, buf.String(), `"correlation_id":"corr-1"`)
+}
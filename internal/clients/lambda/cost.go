@@ -0,0 +1,86 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"sync"
+	"time"
+)
+
+// Pricing is the on-demand price of Lambda invocations in USD.
+type Pricing struct {
+	PerGBSecond float64
+	PerRequest  float64
+}
+
+// DefaultPricing is the us-east-1 price of x86 functions, check the pricing page of the region and architecture.
+var DefaultPricing = Pricing{
+	PerGBSecond: 0.0000166667,
+	PerRequest:  0.0000002,
+}
+
+// CostStats are the totals of invocations with an estimated cost.
+type CostStats struct {
+	Invocations    int64
+	BilledDuration time.Duration
+	GBSeconds      float64
+	// EstimatedCost is in USD, free tier and Savings Plans are not taken into account.
+	EstimatedCost float64
+}
+
+// WithCostEstimation estimates the cost of sync invocations from billed duration and memory size of the REPORT line,
+// it requests the log tail unless WithLogType is passed explicitly. The cost is set in Response.EstimatedCost
+// and aggregated in Client.Costs. It is installed as an interceptor at its position among WithInterceptors options.
+func WithCostEstimation(pricing Pricing) Option {
+	return func(c *client) {
+		if c.call.logType == types.LogTypeNone {
+			c.call.logType = types.LogTypeTail
+		}
+
+		c.interceptors = append(c.interceptors, func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+			resp, err := next(ctx, req)
+			if resp != nil && resp.Stats != nil {
+				resp.EstimatedCost = c.costs.add(pricing, resp.Stats)
+			}
+
+			return resp, err
+		})
+	}
+}
+
+// Costs returns the totals of WithCostEstimation, they are zero if it is not enabled.
+func (c *client) Costs() CostStats {
+	if c.balancer != nil {
+		return c.balancer.costs()
+	}
+
+	return c.costs.stats()
+}
+
+type costTracker struct {
+	mu    sync.Mutex
+	total CostStats
+}
+
+// add accumulates the invocation and returns its estimated cost.
+func (t *costTracker) add(pricing Pricing, stats *ExecutionStats) float64 {
+	gbSeconds := stats.BilledDuration.Seconds() * float64(stats.MemorySize) / 1024
+	cost := gbSeconds*pricing.PerGBSecond + pricing.PerRequest
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.total.Invocations++
+	t.total.BilledDuration += stats.BilledDuration
+	t.total.GBSeconds += gbSeconds
+	t.total.EstimatedCost += cost
+
+	return cost
+}
+
+func (t *costTracker) stats() CostStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.total
+}
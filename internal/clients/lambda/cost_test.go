@@ -0,0 +1,48 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithCostEstimation(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		out := proxyOutput(in, http.StatusOK, "ok")
+		out.LogResult = pointer.To(base64.StdEncoding.EncodeToString([]byte(
+			"REPORT RequestId: 1\tDuration: 999.1 ms\tBilled Duration: 1000 ms\tMemory Size: 2048 MB\tMax Memory Used: 64 MB\t\n")))
+		return out, nil
+	}}
+	cli := newClient(api, testFunctionARN, WithCostEstimation(Pricing{PerGBSecond: 0.5, PerRequest: 0.25}))
+
+	resp, err := cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/"})
+	require.NoError(t, err)
+	assert.InDelta(t, 1.25, resp.EstimatedCost, 1e-9)
+	assert.Equal(t, types.LogTypeTail, api.calls()[0].LogType)
+
+	_, err = cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+
+	costs := cli.Costs()
+	assert.Equal(t, int64(2), costs.Invocations)
+	assert.Equal(t, 2*time.Second, costs.BilledDuration)
+	assert.InDelta(t, 4, costs.GBSeconds, 1e-9)
+	assert.InDelta(t, 2.5, costs.EstimatedCost, 1e-9)
+}
+
+func TestWithCostEstimation_NoLogTail(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithCostEstimation(DefaultPricing), WithLogType(types.LogTypeNone))
+
+	resp, err := cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/"})
+	require.NoError(t, err)
+	assert.Zero(t, resp.EstimatedCost)
+	assert.Zero(t, cli.Costs())
+}
@@ -0,0 +1,219 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"sync"
+	"time"
+)
+
+// CredentialManagerConfig configures a CredentialManager.
+type CredentialManagerConfig struct {
+	// RefreshBefore is how long before expiry credentials are refreshed in the background, 5 minutes by default.
+	// Credentials expiring within half of it are refreshed on the invoke path. For credentials living shorter than
+	// twice RefreshBefore the background refresh happens at half and the invoke path one at three quarters of
+	// their lifetime, so that STS is not called continuously.
+	RefreshBefore time.Duration
+	// RetryInterval is the delay between failed background refreshes, 10 seconds by default.
+	RetryInterval time.Duration
+	// Timeout of a single STS call, 30 seconds by default.
+	Timeout time.Duration
+	// OnError is called with errors of failed background refreshes, if not nil.
+	OnError func(roleARN string, err error)
+}
+
+// CredentialManager caches temporary credentials of assumed roles, one entry per role shared by every client,
+// and refreshes them in the background before expiry, so invocations neither wait for STS nor fail with ExpiredToken.
+type CredentialManager struct {
+	cfg CredentialManagerConfig
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	roles map[roleKey]*managedCredentials
+}
+
+// roleKey identifies credentials of a role, roles differing only in the STS client share credentials.
+type roleKey struct {
+	roleARN     string
+	externalID  string
+	sessionName string
+	duration    time.Duration
+}
+
+// NewCredentialManager returns a manager, call Close to stop background refreshes.
+func NewCredentialManager(cfg CredentialManagerConfig) *CredentialManager {
+	if cfg.RefreshBefore <= 0 {
+		cfg.RefreshBefore = 5 * time.Minute
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = 10 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &CredentialManager{
+		cfg:    cfg,
+		ctx:    ctx,
+		cancel: cancel,
+		roles:  make(map[roleKey]*managedCredentials),
+	}
+}
+
+// WithCredentialManager resolves credentials of roles configured with WithAssumeRole through m,
+// share it between clients, e.g. as a Registry option.
+func WithCredentialManager(m *CredentialManager) Option {
+	return func(c *client) {
+		c.credentialManager = m
+	}
+}
+
+// Close stops background refreshes, credentials are still refreshed on the invoke path afterwards.
+func (m *CredentialManager) Close() {
+	m.cancel()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, mc := range m.roles {
+		mc.stop()
+	}
+}
+
+func (m *CredentialManager) credentials(role AssumeRole, o *lambda.Options) *managedCredentials {
+	key := roleKey{roleARN: role.RoleARN, externalID: role.ExternalID, sessionName: role.SessionName, duration: role.Duration}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mc, ok := m.roles[key]
+	if !ok {
+		mc = &managedCredentials{m: m, roleARN: role.RoleARN, provider: role.provider(o)}
+		m.roles[key] = mc
+	}
+
+	return mc
+}
+
+// managedCredentials is an aws.CredentialsProvider serving cached credentials of a role.
+type managedCredentials struct {
+	m        *CredentialManager
+	roleARN  string
+	provider aws.CredentialsProvider
+
+	// fetchMu serializes fetches on the invoke path and in the background.
+	fetchMu sync.Mutex
+
+	mu       sync.RWMutex
+	creds    aws.Credentials
+	lifetime time.Duration
+	timer    *time.Timer
+}
+
+func (mc *managedCredentials) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	if creds, ok := mc.cached(); ok {
+		return creds, nil
+	}
+
+	mc.fetchMu.Lock()
+	defer mc.fetchMu.Unlock()
+
+	if creds, ok := mc.cached(); ok {
+		return creds, nil
+	}
+
+	creds, err := mc.fetch(ctx)
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("assume role %s: %w", mc.roleARN, err)
+	}
+
+	return creds, nil
+}
+
+// cached returns credentials which do not expire within half of RefreshBefore or a quarter of their lifetime.
+func (mc *managedCredentials) cached() (aws.Credentials, bool) {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	if !mc.creds.HasKeys() {
+		return aws.Credentials{}, false
+	}
+	if mc.creds.CanExpire && time.Until(mc.creds.Expires) < min(mc.m.cfg.RefreshBefore/2, mc.lifetime/4) {
+		return aws.Credentials{}, false
+	}
+
+	return mc.creds, true
+}
+
+// fetch calls STS, stores the credentials and schedules the next refresh, fetchMu must be held.
+func (mc *managedCredentials) fetch(ctx context.Context) (aws.Credentials, error) {
+	ctx, cancel := context.WithTimeout(ctx, mc.m.cfg.Timeout)
+	defer cancel()
+
+	creds, err := mc.provider.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+
+	lifetime := time.Until(creds.Expires)
+
+	mc.mu.Lock()
+	mc.creds, mc.lifetime = creds, lifetime
+	mc.mu.Unlock()
+
+	if creds.CanExpire {
+		mc.schedule(max(lifetime-mc.m.cfg.RefreshBefore, lifetime/2))
+	}
+
+	return creds, nil
+}
+
+func (mc *managedCredentials) schedule(d time.Duration) {
+	if mc.m.ctx.Err() != nil {
+		return
+	}
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.timer != nil {
+		mc.timer.Stop()
+	}
+	mc.timer = time.AfterFunc(max(d, 0), mc.refresh)
+}
+
+// refresh fetches credentials in the background, retrying failures every RetryInterval.
+func (mc *managedCredentials) refresh() {
+	mc.fetchMu.Lock()
+	defer mc.fetchMu.Unlock()
+
+	if mc.m.ctx.Err() != nil {
+		return
+	}
+
+	if _, err := mc.fetch(mc.m.ctx); err != nil {
+		if mc.m.cfg.OnError != nil {
+			mc.m.cfg.OnError(mc.roleARN, err)
+		}
+		mc.schedule(mc.m.cfg.RetryInterval)
+	}
+}
+
+// stop cancels the scheduled refresh, waiting for a running one.
+func (mc *managedCredentials) stop() {
+	mc.fetchMu.Lock()
+	defer mc.fetchMu.Unlock()
+
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	if mc.timer != nil {
+		mc.timer.Stop()
+	}
+}
@@ -0,0 +1,82 @@
+package lambda
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCredentialManager(t *testing.T) {
+	stsClient := &fakeSTS{expiresIn: time.Second}
+
+	var mu sync.Mutex
+	var refreshErrs []error
+	m := NewCredentialManager(CredentialManagerConfig{
+		RefreshBefore: 800 * time.Millisecond,
+		RetryInterval: 10 * time.Millisecond,
+		OnError: func(roleARN string, err error) {
+			assert.Equal(t, testRoleARN, roleARN)
+			mu.Lock()
+			defer mu.Unlock()
+			refreshErrs = append(refreshErrs, err)
+		},
+	})
+	t.Cleanup(m.Close)
+
+	api := &fakeAPI{}
+	registry := newRegistry(api, WithCredentialManager(m))
+	require.NoError(t, registry.Register("orders", "orders", WithAssumeRole(AssumeRole{RoleARN: testRoleARN, STS: stsClient})))
+	require.NoError(t, registry.Register("payments", "payments", WithAssumeRole(AssumeRole{RoleARN: testRoleARN, STS: stsClient})))
+
+	for _, name := range []string{"orders", "payments"} {
+		_, err := registry.Do(_ctx, name, Request{HTTPMethod: http.MethodGet, Path: "/"})
+		require.NoError(t, err)
+	}
+
+	creds, err := api.options(1).Credentials.Retrieve(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ASIA1", creds.AccessKeyID)
+	assert.Equal(t, 1, stsClient.calls(), "credentials are shared per role")
+
+	require.Eventually(t, func() bool { return stsClient.calls() == 2 }, time.Second, 5*time.Millisecond, "refreshed in the background")
+
+	creds, err = api.options(0).Credentials.Retrieve(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "ASIA2", creds.AccessKeyID)
+
+	stsClient.fail(errors.New("sts unavailable"))
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(refreshErrs) >= 2
+	}, 2*time.Second, 5*time.Millisecond, "failed refreshes are retried")
+
+	m.Close()
+	calls := stsClient.calls()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, calls, stsClient.calls(), "no refreshes after Close")
+}
+
+func TestCredentialManager_ShortLived(t *testing.T) {
+	stsClient := &fakeSTS{expiresIn: 200 * time.Millisecond}
+
+	m := NewCredentialManager(CredentialManagerConfig{})
+	t.Cleanup(m.Close)
+
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithCredentialManager(m), WithAssumeRole(AssumeRole{RoleARN: testRoleARN, STS: stsClient}))
+
+	for range 10 {
+		_, err := cli.Invoke(_ctx, http.MethodGet, "/", nil)
+		require.NoError(t, err)
+		_, err = api.options(0).Credentials.Retrieve(_ctx)
+		require.NoError(t, err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Equal(t, 1, stsClient.calls(), "credentials shorter lived than RefreshBefore are not fetched continuously")
+}
@@ -0,0 +1,102 @@
+package lambda
+
+import (
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/smithy-go"
+	"strings"
+)
+
+// ErrAccountMismatch is returned by New and Registry.Register when the function ARN account differs from
+// the account of the role configured with WithAssumeRole.
+var ErrAccountMismatch = errors.New("function account does not match role account")
+
+// AccessDeniedError is returned when Lambda or STS denied access, Principal is the assumed role session
+// or "default credentials" of the *lambda.Client.
+type AccessDeniedError struct {
+	Principal string
+	Function  string
+	Err       error
+}
+
+func (e *AccessDeniedError) Error() string {
+	return fmt.Sprintf("access denied for principal %s to function %s: %v", e.Principal, e.Function, e.Err)
+}
+
+func (e *AccessDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// accessDeniedCodes are error codes of Lambda and STS APIs denying access.
+var accessDeniedCodes = []string{"AccessDeniedException", "AccessDenied"}
+
+// accessDeniedError wraps err into AccessDeniedError naming the principal, other errors are returned as is.
+func (c *client) accessDeniedError(err error) error {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	for _, code := range accessDeniedCodes {
+		if apiErr.ErrorCode() == code {
+			return &AccessDeniedError{Principal: c.principal(), Function: c.functionARN, Err: err}
+		}
+	}
+
+	return err
+}
+
+func (c *client) principal() string {
+	if c.roleCredentials == nil {
+		return "default credentials"
+	}
+
+	return c.roleCredentials.role.sessionARN()
+}
+
+// sessionARN returns the ARN of the assumed role session, or the role ARN if it cannot be parsed.
+func (r AssumeRole) sessionARN() string {
+	a, err := arn.Parse(r.RoleARN)
+	if err != nil || !strings.HasPrefix(a.Resource, "role/") {
+		return r.RoleARN
+	}
+
+	name := a.Resource[strings.LastIndex(a.Resource, "/")+1:]
+
+	return arn.ARN{
+		Partition: a.Partition,
+		Service:   "sts",
+		AccountID: a.AccountID,
+		Resource:  "assumed-role/" + name + "/" + r.SessionName,
+	}.String()
+}
+
+// validateAccount checks that a function given by a full or partial ARN is in the account of the assumed role,
+// function names resolve to the role account.
+func (c *client) validateAccount() error {
+	if c.roleCredentials == nil {
+		return nil
+	}
+
+	role, err := arn.Parse(c.roleCredentials.role.RoleARN)
+	if err != nil {
+		return fmt.Errorf("arn.Parse[%s]: %w", c.roleCredentials.role.RoleARN, err)
+	}
+
+	if account := functionAccount(c.functionARN); account != "" && account != role.AccountID {
+		return fmt.Errorf("%w: function %s, role %s", ErrAccountMismatch, account, role.AccountID)
+	}
+
+	return nil
+}
+
+// functionAccount returns the account ID of a full or partial function ARN, empty for function names.
+func functionAccount(function string) string {
+	m := functionNamePattern.FindStringSubmatch(function)
+	if m == nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(m[5], ":")
+}
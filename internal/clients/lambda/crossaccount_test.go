@@ -0,0 +1,107 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testRoleARN = "arn:aws:iam::111111111111:role/team/invoker"
+
+func TestValidateAccount(t *testing.T) {
+	tests := []struct {
+		function string
+		wantErr  bool
+	}{
+		{function: "arn:aws:lambda:eu-central-1:111111111111:function:orders"},
+		{function: "111111111111:function:orders:live"},
+		{function: "orders"},
+		{function: "arn:aws:lambda:eu-central-1:222222222222:function:orders", wantErr: true},
+		{function: "222222222222:function:orders", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			err := newRegistry(&fakeAPI{}).Register("orders", tt.function, WithAssumeRole(AssumeRole{RoleARN: testRoleARN, STS: &fakeSTS{}}))
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrAccountMismatch)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	require.NoError(t, newRegistry(&fakeAPI{}).Register("orders", testFunctionARN), "no role")
+}
+
+func TestAccessDeniedError(t *testing.T) {
+	denied := &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized to perform: lambda:InvokeFunction"}
+	api := &fakeAPI{invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, denied
+	}}
+
+	function := "arn:aws:lambda:eu-central-1:111111111111:function:orders"
+	cli := newClient(api, function, WithAssumeRole(AssumeRole{RoleARN: testRoleARN, SessionName: "orders", STS: &fakeSTS{}}))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+
+	var ade *AccessDeniedError
+	require.ErrorAs(t, err, &ade)
+	assert.Equal(t, "arn:aws:sts::111111111111:assumed-role/invoker/orders", ade.Principal)
+	assert.Equal(t, function, ade.Function)
+	assert.ErrorIs(t, err, denied)
+	assert.Equal(t, ErrorKindTransport, KindOf(err))
+
+	_, err = newClient(api, function).Invoke(_ctx, "GET", "/", nil)
+	require.ErrorAs(t, err, &ade)
+	assert.Equal(t, "default credentials", ade.Principal)
+
+	api.invoke = func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, errors.New("connection reset")
+	}
+	_, err = cli.Invoke(_ctx, "GET", "/", nil)
+	assert.False(t, errors.As(err, &ade))
+}
+
+func TestRegistry_RegisterConfig_Role(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "functions.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+defaults:
+  role: {arn: "arn:aws:iam::000000000000:role/invoker", externalId: orders}
+functions:
+  payments:
+    function: arn:aws:lambda:eu-central-1:000000000000:function:payments
+  billing:
+    function: arn:aws:lambda:eu-central-1:111111111111:function:billing
+    role: {arn: "arn:aws:iam::111111111111:role/invoker", duration: 30m}
+`), 0o600))
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, &RoleConfig{ARN: "arn:aws:iam::111111111111:role/invoker", Duration: Duration(30 * time.Minute)}, cfg.Functions["billing"].Role)
+
+	registry := newRegistry(&fakeAPI{})
+	require.NoError(t, registry.RegisterConfig(path))
+
+	for name, role := range map[string]string{"payments": "arn:aws:iam::000000000000:role/invoker", "billing": "arn:aws:iam::111111111111:role/invoker"} {
+		c, err := registry.Client(name)
+		require.NoError(t, err)
+		assert.Equal(t, role, c.(*client).roleCredentials.role.RoleARN)
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+defaults:
+  role: {arn: "arn:aws:iam::000000000000:role/invoker"}
+functions:
+  billing:
+    function: arn:aws:lambda:eu-central-1:111111111111:function:billing
+`), 0o600))
+	require.ErrorIs(t, newRegistry(&fakeAPI{}).RegisterConfig(path), ErrAccountMismatch)
+}
@@ -0,0 +1,187 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"time"
+)
+
+// AsyncConfig is the EventInvokeConfig of a function: how async invocations are retried and where their
+// outcomes are sent. Zero fields are not configured, i.e. Lambda defaults apply.
+type AsyncConfig struct {
+	// MaxRetryAttempts is 0 to 2 retries of failed invocations, the pointer tells 0 from not configured.
+	MaxRetryAttempts *int32
+	// MaxEventAge is 1 minute to 6 hours, older events are discarded.
+	MaxEventAge time.Duration
+	// OnSuccess and OnFailure are ARNs of SQS queues, SNS topics, functions or event buses.
+	OnSuccess string
+	OnFailure string
+}
+
+// eventInvokeConfigAPI is the subset of *lambda.Client used by PutAsyncConfig and GetAsyncConfig.
+type eventInvokeConfigAPI interface {
+	PutFunctionEventInvokeConfig(ctx context.Context, params *lambda.PutFunctionEventInvokeConfigInput, optFns ...func(*lambda.Options)) (*lambda.PutFunctionEventInvokeConfigOutput, error)
+	GetFunctionEventInvokeConfig(ctx context.Context, params *lambda.GetFunctionEventInvokeConfigInput, optFns ...func(*lambda.Options)) (*lambda.GetFunctionEventInvokeConfigOutput, error)
+}
+
+// PutAsyncConfig replaces the EventInvokeConfig of the function, optionally qualified by a version or an alias.
+func PutAsyncConfig(ctx context.Context, cli *lambda.Client, function, qualifier string, cfg AsyncConfig) error {
+	return putAsyncConfig(ctx, cli, function, qualifier, cfg)
+}
+
+func putAsyncConfig(ctx context.Context, cli eventInvokeConfigAPI, function, qualifier string, cfg AsyncConfig) error {
+	in := &lambda.PutFunctionEventInvokeConfigInput{
+		FunctionName:         pointer.To(function),
+		Qualifier:            pointer.ToStringOrNil(qualifier),
+		MaximumRetryAttempts: cfg.MaxRetryAttempts,
+	}
+
+	if cfg.MaxEventAge > 0 {
+		in.MaximumEventAgeInSeconds = pointer.To(int32(cfg.MaxEventAge / time.Second))
+	}
+
+	if cfg.OnSuccess != "" || cfg.OnFailure != "" {
+		in.DestinationConfig = &types.DestinationConfig{}
+		if cfg.OnSuccess != "" {
+			in.DestinationConfig.OnSuccess = &types.OnSuccess{Destination: pointer.To(cfg.OnSuccess)}
+		}
+		if cfg.OnFailure != "" {
+			in.DestinationConfig.OnFailure = &types.OnFailure{Destination: pointer.To(cfg.OnFailure)}
+		}
+	}
+
+	if _, err := cli.PutFunctionEventInvokeConfig(ctx, in); err != nil {
+		return fmt.Errorf("cli.PutFunctionEventInvokeConfig: %w", err)
+	}
+
+	return nil
+}
+
+// GetAsyncConfig returns the EventInvokeConfig of the function, nil if none is configured.
+func GetAsyncConfig(ctx context.Context, cli *lambda.Client, function, qualifier string) (*AsyncConfig, error) {
+	return getAsyncConfig(ctx, cli, function, qualifier)
+}
+
+func getAsyncConfig(ctx context.Context, cli eventInvokeConfigAPI, function, qualifier string) (*AsyncConfig, error) {
+	output, err := cli.GetFunctionEventInvokeConfig(ctx, &lambda.GetFunctionEventInvokeConfigInput{
+		FunctionName: pointer.To(function),
+		Qualifier:    pointer.ToStringOrNil(qualifier),
+	})
+	if err != nil {
+		var rnf *types.ResourceNotFoundException
+		if errors.As(err, &rnf) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cli.GetFunctionEventInvokeConfig: %w", err)
+	}
+
+	cfg := &AsyncConfig{
+		MaxRetryAttempts: output.MaximumRetryAttempts,
+		MaxEventAge:      time.Duration(pointer.Get(output.MaximumEventAgeInSeconds)) * time.Second,
+	}
+
+	if dc := output.DestinationConfig; dc != nil {
+		if dc.OnSuccess != nil {
+			cfg.OnSuccess = pointer.Get(dc.OnSuccess.Destination)
+		}
+		if dc.OnFailure != nil {
+			cfg.OnFailure = pointer.Get(dc.OnFailure.Destination)
+		}
+	}
+
+	return cfg, nil
+}
+
+// DestinationRecord is the invocation record Lambda sends to destinations of async invocations.
+type DestinationRecord struct {
+	Version         string                     `json:"version"`
+	Timestamp       time.Time                  `json:"timestamp"`
+	RequestContext  DestinationRequestContext  `json:"requestContext"`
+	RequestPayload  json.RawMessage            `json:"requestPayload"`
+	ResponseContext DestinationResponseContext `json:"responseContext"`
+	ResponsePayload json.RawMessage            `json:"responsePayload"`
+}
+
+// DestinationRequestContext describes the invocation of a DestinationRecord.
+type DestinationRequestContext struct {
+	// RequestID is the Response.RequestID returned by InvokeAsync.
+	RequestID   string `json:"requestId"`
+	FunctionARN string `json:"functionArn"`
+	// Condition is "Success", "RetriesExhausted" or "EventAgeExceeded".
+	Condition              string `json:"condition"`
+	ApproximateInvokeCount int    `json:"approximateInvokeCount"`
+}
+
+// DestinationResponseContext describes the last attempt of a DestinationRecord.
+type DestinationResponseContext struct {
+	StatusCode      int    `json:"statusCode"`
+	ExecutedVersion string `json:"executedVersion"`
+	FunctionError   string `json:"functionError"`
+}
+
+// Err returns the InvocationError of the last attempt if the function failed, or an error naming the condition
+// if the event was discarded otherwise, nil on success.
+func (r *DestinationRecord) Err() error {
+	if r.ResponseContext.FunctionError != "" {
+		return newInvocationError(r.ResponseContext.FunctionError, r.ResponsePayload)
+	}
+
+	if r.RequestContext.Condition != "" && r.RequestContext.Condition != "Success" {
+		return fmt.Errorf("async invocation %s: %s", r.RequestContext.RequestID, r.RequestContext.Condition)
+	}
+
+	return nil
+}
+
+// matches reports whether the record is of the invocation with the request ID or the correlation ID.
+func (r *DestinationRecord) matches(id string) bool {
+	if r.RequestContext.RequestID == id {
+		return true
+	}
+
+	var event struct {
+		Headers map[string]string `json:"headers"`
+	}
+	if err := json.Unmarshal(r.RequestPayload, &event); err != nil {
+		return false
+	}
+
+	correlationID, ok := headerValue(event.Headers, CorrelationIDHeader)
+
+	return ok && correlationID == id
+}
+
+// DestinationQueue polls an SQS queue configured as a destination of async invocations, typically OnFailure,
+// to learn the fate of a given invocation.
+type DestinationQueue struct {
+	poller *sqsPoller
+}
+
+// NewDestinationQueue returns a poller of the destination queue.
+func NewDestinationQueue(cli *sqs.Client, queueURL string) *DestinationQueue {
+	return &DestinationQueue{poller: newSQSPoller(cli, queueURL)}
+}
+
+// Await long-polls the queue until the record of the invocation arrives or ctx is done. id is either the
+// Response.RequestID returned by InvokeAsync or the correlation ID of WithCorrelationID. The matching message is
+// deleted, messages of other invocations are made visible again right away. Concurrent Await calls share a poller.
+func (q *DestinationQueue) Await(ctx context.Context, id string) (*DestinationRecord, error) {
+	var record DestinationRecord
+
+	_, err := q.poller.receive(ctx, func(msg sqstypes.Message) bool {
+		record = DestinationRecord{}
+		return json.Unmarshal([]byte(pointer.Get(msg.Body)), &record) == nil && record.matches(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
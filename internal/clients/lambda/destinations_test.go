@@ -0,0 +1,131 @@
+package lambda
+
+import (
+	"context"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type fakeEventInvokeConfig struct {
+	put *lambda.PutFunctionEventInvokeConfigInput
+}
+
+func (f *fakeEventInvokeConfig) PutFunctionEventInvokeConfig(_ context.Context, in *lambda.PutFunctionEventInvokeConfigInput, _ ...func(*lambda.Options)) (*lambda.PutFunctionEventInvokeConfigOutput, error) {
+	f.put = in
+	return &lambda.PutFunctionEventInvokeConfigOutput{}, nil
+}
+
+func (f *fakeEventInvokeConfig) GetFunctionEventInvokeConfig(context.Context, *lambda.GetFunctionEventInvokeConfigInput, ...func(*lambda.Options)) (*lambda.GetFunctionEventInvokeConfigOutput, error) {
+	if f.put == nil {
+		return nil, &types.ResourceNotFoundException{}
+	}
+
+	return &lambda.GetFunctionEventInvokeConfigOutput{
+		MaximumRetryAttempts:     f.put.MaximumRetryAttempts,
+		MaximumEventAgeInSeconds: f.put.MaximumEventAgeInSeconds,
+		DestinationConfig:        f.put.DestinationConfig,
+	}, nil
+}
+
+func TestAsyncConfig(t *testing.T) {
+	api := &fakeEventInvokeConfig{}
+
+	cfg, err := getAsyncConfig(_ctx, api, "orders", "")
+	require.NoError(t, err)
+	assert.Nil(t, cfg, "not configured")
+
+	want := AsyncConfig{
+		MaxRetryAttempts: pointer.To(int32(0)),
+		MaxEventAge:      time.Hour,
+		OnFailure:        "arn:aws:sqs:eu-central-1:000000000000:orders-failures",
+	}
+	require.NoError(t, putAsyncConfig(_ctx, api, "orders", "live", want))
+	assert.Equal(t, "live", *api.put.Qualifier)
+	assert.Equal(t, int32(3600), *api.put.MaximumEventAgeInSeconds)
+	assert.Nil(t, api.put.DestinationConfig.OnSuccess)
+
+	cfg, err = getAsyncConfig(_ctx, api, "orders", "live")
+	require.NoError(t, err)
+	assert.Equal(t, want, *cfg)
+}
+
+type fakeDestinationQueue struct {
+	batches [][]string
+
+	deleted []string
+	visible []string
+}
+
+func (f *fakeDestinationQueue) ReceiveMessage(ctx context.Context, _ *sqs.ReceiveMessageInput, _ ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	if len(f.batches) == 0 {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	var output sqs.ReceiveMessageOutput
+	for i, body := range f.batches[0] {
+		output.Messages = append(output.Messages, sqstypes.Message{Body: pointer.To(body), ReceiptHandle: pointer.To(string(rune('a' + i)))})
+	}
+	f.batches = f.batches[1:]
+
+	return &output, nil
+}
+
+func (f *fakeDestinationQueue) DeleteMessage(_ context.Context, in *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.deleted = append(f.deleted, *in.ReceiptHandle)
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeDestinationQueue) ChangeMessageVisibility(_ context.Context, in *sqs.ChangeMessageVisibilityInput, _ ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.visible = append(f.visible, *in.ReceiptHandle)
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+const testDestinationRecord = `{
+	"version": "1.0",
+	"timestamp": "2024-01-02T03:04:05.678Z",
+	"requestContext": {"requestId": "request-2", "functionArn": "arn:aws:lambda:eu-central-1:000000000000:function:orders:$LATEST", "condition": "RetriesExhausted", "approximateInvokeCount": 3},
+	"requestPayload": {"path": "/orders", "headers": {"x-correlation-id": "corr-2"}},
+	"responseContext": {"statusCode": 200, "executedVersion": "$LATEST", "functionError": "Unhandled"},
+	"responsePayload": {"errorMessage": "payment declined", "errorType": "PaymentError"}
+}`
+
+func TestDestinationQueue_Await(t *testing.T) {
+	queue := &fakeDestinationQueue{batches: [][]string{
+		{`{"requestContext": {"requestId": "request-1"}}`},
+		{"not json", testDestinationRecord},
+	}}
+	q := &DestinationQueue{poller: newSQSPoller(queue, "orders-failures")}
+
+	record, err := q.Await(_ctx, "corr-2")
+	require.NoError(t, err)
+	assert.Equal(t, "request-2", record.RequestContext.RequestID)
+	assert.Equal(t, 3, record.RequestContext.ApproximateInvokeCount)
+	assert.Equal(t, []string{"b"}, queue.deleted)
+	assert.Equal(t, []string{"a", "a"}, queue.visible, "other messages are released")
+
+	var ie *InvocationError
+	require.ErrorAs(t, record.Err(), &ie)
+	assert.Equal(t, "PaymentError", ie.Type)
+
+	ctx, cancel := context.WithTimeout(_ctx, 10*time.Millisecond)
+	defer cancel()
+
+	_, err = q.Await(ctx, "request-3")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDestinationRecord_Err(t *testing.T) {
+	record := DestinationRecord{RequestContext: DestinationRequestContext{RequestID: "request-1", Condition: "EventAgeExceeded"}}
+	require.ErrorContains(t, record.Err(), "EventAgeExceeded")
+
+	record.RequestContext.Condition = "Success"
+	require.NoError(t, record.Err())
+}
@@ -0,0 +1,197 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrFunctionNotFound is returned when no function has the tags.
+	ErrFunctionNotFound = errors.New("function not found")
+	// ErrAmbiguousFunction is returned when several functions have the tags.
+	ErrAmbiguousFunction = errors.New("several functions found")
+)
+
+// discoveryAPI is the subset of *lambda.Client used by TagResolver.
+type discoveryAPI interface {
+	lambda.ListFunctionsAPIClient
+	ListTags(ctx context.Context, params *lambda.ListTagsInput, optFns ...func(*lambda.Options)) (*lambda.ListTagsOutput, error)
+}
+
+// TagResolver discovers the function having all the tags, e.g. service=orders and env=prod, so that
+// deployments rotating function names do not require config changes. By default it lists every function of the
+// region and its tags, see NewTagResolverFunc for a cheaper lookup. The resolved ARN is cached for TTL, expired
+// ARNs are served while a single discovery runs in the background.
+type TagResolver struct {
+	find func(ctx context.Context, tags map[string]string) ([]string, error)
+	tags map[string]string
+	ttl  time.Duration
+
+	mu         sync.Mutex
+	arn        string
+	resolvedAt time.Time
+	discovery  *discovery
+}
+
+// discovery is an in-flight lookup shared by the callers of Resolve.
+type discovery struct {
+	done chan struct{}
+	arn  string
+	err  error
+}
+
+// discoveryTimeout bounds a discovery, it outlives the Resolve call which started it.
+const discoveryTimeout = time.Minute
+
+// NewTagResolver returns a resolver caching the ARN for ttl, 5 minutes if 0.
+func NewTagResolver(cli *lambda.Client, tags map[string]string, ttl time.Duration) *TagResolver {
+	return newTagResolver(cli, tags, ttl)
+}
+
+// NewTagResolverFunc returns a resolver of the function ARNs find returns for tags, caching the ARN for ttl,
+// 5 minutes if 0. Back find with GetResources of the Resource Groups Tagging API filtered by the tags and the
+// lambda:function resource type, which takes a call per 100 functions instead of a ListTags call per function.
+func NewTagResolverFunc(find func(ctx context.Context, tags map[string]string) ([]string, error), tags map[string]string, ttl time.Duration) *TagResolver {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+
+	return &TagResolver{find: find, tags: tags, ttl: ttl}
+}
+
+func newTagResolver(cli discoveryAPI, tags map[string]string, ttl time.Duration) *TagResolver {
+	return NewTagResolverFunc(func(ctx context.Context, tags map[string]string) ([]string, error) {
+		return listTagged(ctx, cli, tags)
+	}, tags, ttl)
+}
+
+// NewFromTags returns a client of the function discovered by tags, see TagResolver. The function is discovered
+// before returning and again every 5 minutes or when it disappears, see WithResolver.
+func NewFromTags(ctx context.Context, cli *lambda.Client, tags map[string]string, opts ...Option) (Client, error) {
+	resolver := NewTagResolver(cli, tags, 0)
+
+	arn, err := resolver.Resolve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Resolve: %w", err)
+	}
+
+	return New(cli, arn, append(opts, WithResolver(resolver))...)
+}
+
+// Resolve returns the ARN of the function having the tags, ErrFunctionNotFound or ErrAmbiguousFunction.
+// An expired ARN is returned while it is discovered again.
+func (r *TagResolver) Resolve(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	if r.arn != "" && time.Since(r.resolvedAt) < r.ttl {
+		defer r.mu.Unlock()
+		return r.arn, nil
+	}
+
+	d := r.discovery
+	if d == nil {
+		d = &discovery{done: make(chan struct{})}
+		r.discovery = d
+		go r.discover(context.WithoutCancel(ctx), d)
+	}
+	stale := r.arn
+	r.mu.Unlock()
+
+	if stale != "" {
+		return stale, nil
+	}
+
+	select {
+	case <-d.done:
+		return d.arn, d.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// Invalidate drops the cached ARN, the next Resolve discovers the function again.
+func (r *TagResolver) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.arn = ""
+}
+
+func (r *TagResolver) discover(ctx context.Context, d *discovery) {
+	ctx, cancel := context.WithTimeout(ctx, discoveryTimeout)
+	defer cancel()
+
+	d.arn, d.err = r.lookup(ctx)
+
+	r.mu.Lock()
+	if d.err == nil {
+		r.arn, r.resolvedAt = d.arn, time.Now()
+	}
+	r.discovery = nil
+	r.mu.Unlock()
+
+	close(d.done)
+}
+
+func (r *TagResolver) lookup(ctx context.Context) (string, error) {
+	found, err := r.find(ctx, r.tags)
+	if err != nil {
+		return "", err
+	}
+
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("tags %v: %w", r.tags, ErrFunctionNotFound)
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("tags %v: %w: %s", r.tags, ErrAmbiguousFunction, strings.Join(found, ", "))
+	}
+}
+
+// listTagged lists every function and its tags, returning the ARNs of the functions having tags.
+func listTagged(ctx context.Context, cli discoveryAPI, tags map[string]string) ([]string, error) {
+	var found []string
+
+	pages := lambda.NewListFunctionsPaginator(cli, &lambda.ListFunctionsInput{})
+	functions := paginate(pages.HasMorePages, func() (*lambda.ListFunctionsOutput, error) {
+		return pages.NextPage(ctx)
+	}, func(page *lambda.ListFunctionsOutput) []types.FunctionConfiguration {
+		return page.Functions
+	})
+
+	for fn, err := range functions {
+		if err != nil {
+			return nil, fmt.Errorf("cli.ListFunctions: %w", err)
+		}
+
+		arn := pointer.Get(fn.FunctionArn)
+
+		output, err := cli.ListTags(ctx, &lambda.ListTagsInput{Resource: pointer.To(arn)})
+		if err != nil {
+			return nil, fmt.Errorf("cli.ListTags[%s]: %w", arn, err)
+		}
+
+		if hasTags(output.Tags, tags) {
+			found = append(found, arn)
+		}
+	}
+
+	return found, nil
+}
+
+func hasTags(tags, want map[string]string) bool {
+	for k, v := range want {
+		if tags[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,120 @@
+package lambda
+
+import (
+	"context"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeDiscovery serves functions one per page with their tags.
+type fakeDiscovery struct {
+	functions map[string]map[string]string
+	order     []string
+	lists     int
+}
+
+func (f *fakeDiscovery) ListFunctions(_ context.Context, in *lambda.ListFunctionsInput, _ ...func(*lambda.Options)) (*lambda.ListFunctionsOutput, error) {
+	f.lists++
+
+	i := 0
+	if in.Marker != nil {
+		i = len(*in.Marker)
+	}
+
+	output := &lambda.ListFunctionsOutput{}
+	if i < len(f.order) {
+		output.Functions = []types.FunctionConfiguration{{FunctionArn: pointer.To(f.order[i])}}
+	}
+	if i+1 < len(f.order) {
+		output.NextMarker = pointer.To(pointer.Get(in.Marker) + "x")
+	}
+
+	return output, nil
+}
+
+func (f *fakeDiscovery) ListTags(_ context.Context, in *lambda.ListTagsInput, _ ...func(*lambda.Options)) (*lambda.ListTagsOutput, error) {
+	return &lambda.ListTagsOutput{Tags: f.functions[*in.Resource]}, nil
+}
+
+func TestTagResolver(t *testing.T) {
+	const (
+		ordersProd = "arn:aws:lambda:eu-central-1:000000000000:function:orders-prod-a1b2"
+		ordersDev  = "arn:aws:lambda:eu-central-1:000000000000:function:orders-dev-c3d4"
+	)
+	api := &fakeDiscovery{
+		functions: map[string]map[string]string{
+			ordersDev:       {"service": "orders", "env": "dev"},
+			ordersProd:      {"service": "orders", "env": "prod", "team": "checkout"},
+			testFunctionARN: {},
+		},
+		order: []string{testFunctionARN, ordersDev, ordersProd},
+	}
+	resolver := newTagResolver(api, map[string]string{"service": "orders", "env": "prod"}, 0)
+
+	arn, err := resolver.Resolve(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, ordersProd, arn)
+	assert.Equal(t, 3, api.lists, "every page is listed")
+
+	_, err = resolver.Resolve(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, api.lists, "cached")
+
+	resolver.Invalidate()
+	_, err = resolver.Resolve(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 6, api.lists)
+
+	_, err = newTagResolver(api, map[string]string{"service": "payments"}, 0).Resolve(_ctx)
+	require.ErrorIs(t, err, ErrFunctionNotFound)
+
+	_, err = newTagResolver(api, map[string]string{"service": "orders"}, 0).Resolve(_ctx)
+	require.ErrorIs(t, err, ErrAmbiguousFunction)
+	assert.ErrorContains(t, err, ordersDev)
+}
+
+func TestTagResolver_Stale(t *testing.T) {
+	const (
+		before = "arn:aws:lambda:eu-central-1:000000000000:function:orders-a1b2"
+		after  = "arn:aws:lambda:eu-central-1:000000000000:function:orders-c3d4"
+	)
+
+	var finds atomic.Int32
+	release := make(chan struct{})
+	resolver := NewTagResolverFunc(func(_ context.Context, tags map[string]string) ([]string, error) {
+		assert.Equal(t, map[string]string{"service": "orders"}, tags)
+		if finds.Add(1) == 1 {
+			return []string{before}, nil
+		}
+		<-release
+		return []string{after}, nil
+	}, map[string]string{"service": "orders"}, time.Millisecond)
+
+	arn, err := resolver.Resolve(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, before, arn)
+
+	time.Sleep(2 * time.Millisecond)
+
+	for range 3 {
+		arn, err = resolver.Resolve(_ctx)
+		require.NoError(t, err)
+		assert.Equal(t, before, arn, "expired ARN is served during discovery")
+	}
+	require.Eventually(t, func() bool { return finds.Load() == 2 }, time.Second, time.Millisecond)
+	_, err = resolver.Resolve(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), finds.Load(), "a single discovery")
+
+	close(release)
+	require.Eventually(t, func() bool {
+		arn, err := resolver.Resolve(_ctx)
+		return err == nil && arn == after
+	}, time.Second, time.Millisecond)
+}
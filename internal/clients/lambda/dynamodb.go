@@ -0,0 +1,104 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"strconv"
+	"time"
+)
+
+// dynamoDBAPI is the subset of *dynamodb.Client used by DynamoDBReplyTable.
+type dynamoDBAPI interface {
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// DynamoDBReplyConfig configures a DynamoDBReplyTable.
+type DynamoDBReplyConfig struct {
+	Table string
+	// Key is the string partition key of the table, "correlationId" by default.
+	Key string
+	// TTL sets the "expiresAt" attribute of replies, enable TTL on it to expire replies that nobody awaits anymore.
+	// 1 hour by default.
+	TTL time.Duration
+	// PollInterval between consistent reads of the reply, 500ms by default.
+	PollInterval time.Duration
+}
+
+// DynamoDBReplyTable is a ReplyChannel storing replies as items keyed by correlation ID with the "payload" attribute.
+type DynamoDBReplyTable struct {
+	cli dynamoDBAPI
+	cfg DynamoDBReplyConfig
+}
+
+// NewDynamoDBReplyTable returns a reply channel over cfg.Table.
+func NewDynamoDBReplyTable(cli *dynamodb.Client, cfg DynamoDBReplyConfig) *DynamoDBReplyTable {
+	return newDynamoDBReplyTable(cli, cfg)
+}
+
+func newDynamoDBReplyTable(cli dynamoDBAPI, cfg DynamoDBReplyConfig) *DynamoDBReplyTable {
+	if cfg.Key == "" {
+		cfg.Key = "correlationId"
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Hour
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 500 * time.Millisecond
+	}
+
+	return &DynamoDBReplyTable{cli: cli, cfg: cfg}
+}
+
+func (t *DynamoDBReplyTable) Send(ctx context.Context, correlationID string, payload []byte) error {
+	expiresAt := time.Now().Add(t.cfg.TTL).Unix()
+
+	_, err := t.cli.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: pointer.To(t.cfg.Table),
+		Item: map[string]ddbtypes.AttributeValue{
+			t.cfg.Key:   &ddbtypes.AttributeValueMemberS{Value: correlationID},
+			"payload":   &ddbtypes.AttributeValueMemberS{Value: string(payload)},
+			"expiresAt": &ddbtypes.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt, 10)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("cli.PutItem: %w", err)
+	}
+
+	return nil
+}
+
+// Receive polls the item of the correlation ID and deletes it once read.
+func (t *DynamoDBReplyTable) Receive(ctx context.Context, correlationID string) ([]byte, error) {
+	key := map[string]ddbtypes.AttributeValue{t.cfg.Key: &ddbtypes.AttributeValueMemberS{Value: correlationID}}
+
+	for {
+		output, err := t.cli.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName:      pointer.To(t.cfg.Table),
+			Key:            key,
+			ConsistentRead: pointer.To(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cli.GetItem: %w", err)
+		}
+
+		if payload, ok := output.Item["payload"].(*ddbtypes.AttributeValueMemberS); ok {
+			if _, err := t.cli.DeleteItem(ctx, &dynamodb.DeleteItemInput{TableName: pointer.To(t.cfg.Table), Key: key}); err != nil {
+				return nil, fmt.Errorf("cli.DeleteItem: %w", err)
+			}
+			return []byte(payload.Value), nil
+		}
+
+		timer := time.NewTimer(t.cfg.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
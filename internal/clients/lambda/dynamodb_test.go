@@ -0,0 +1,70 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeDynamoDB struct {
+	mu    sync.Mutex
+	items map[string]map[string]ddbtypes.AttributeValue
+	gets  int
+}
+
+func (f *fakeDynamoDB) PutItem(_ context.Context, in *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.items == nil {
+		f.items = make(map[string]map[string]ddbtypes.AttributeValue)
+	}
+	f.items[in.Item["id"].(*ddbtypes.AttributeValueMemberS).Value] = in.Item
+
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamoDB) GetItem(_ context.Context, in *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.gets++
+
+	return &dynamodb.GetItemOutput{Item: f.items[in.Key["id"].(*ddbtypes.AttributeValueMemberS).Value]}, nil
+}
+
+func (f *fakeDynamoDB) DeleteItem(_ context.Context, in *dynamodb.DeleteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.items, in.Key["id"].(*ddbtypes.AttributeValueMemberS).Value)
+
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestDynamoDBReplyTable(t *testing.T) {
+	db := &fakeDynamoDB{}
+	table := newDynamoDBReplyTable(db, DynamoDBReplyConfig{Table: "replies", Key: "id", PollInterval: time.Millisecond})
+
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		assert.NoError(t, table.Send(_ctx, "corr-1", []byte(`{"response":{"statusCode":200}}`)))
+	}()
+
+	payload, err := table.Receive(_ctx, "corr-1")
+	require.NoError(t, err)
+	assert.Equal(t, `{"response":{"statusCode":200}}`, string(payload))
+	assert.Greater(t, db.gets, 1, "polled until sent")
+	assert.Empty(t, db.items, "deleted once read")
+
+	require.NoError(t, table.Send(_ctx, "corr-2", nil))
+	expiresAt, err := strconv.ParseInt(db.items["corr-2"]["expiresAt"].(*ddbtypes.AttributeValueMemberN).Value, 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(time.Hour).Unix(), expiresAt, 5)
+}
@@ -0,0 +1,30 @@
+package lambda
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+)
+
+// WithFIPSEndpoint calls FIPS 140-2 validated Lambda endpoints, e.g. lambda-fips.us-gov-west-1.amazonaws.com.
+func WithFIPSEndpoint() Option {
+	return func(c *client) {
+		c.fipsEndpoint = true
+	}
+}
+
+// WithDualStackEndpoint calls Lambda endpoints resolving to both IPv4 and IPv6 addresses,
+// combined with WithFIPSEndpoint it selects FIPS dual-stack endpoints.
+func WithDualStackEndpoint() Option {
+	return func(c *client) {
+		c.dualStackEndpoint = true
+	}
+}
+
+func (c *client) endpointOption(o *lambda.Options) {
+	if c.fipsEndpoint {
+		o.EndpointOptions.UseFIPSEndpoint = aws.FIPSEndpointStateEnabled
+	}
+	if c.dualStackEndpoint {
+		o.EndpointOptions.UseDualStackEndpoint = aws.DualStackEndpointStateEnabled
+	}
+}
@@ -0,0 +1,40 @@
+package lambda
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestWithFIPSEndpoint(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []Option
+		wantFIPS      aws.FIPSEndpointState
+		wantDualStack aws.DualStackEndpointState
+	}{
+		{name: "default"},
+		{name: "fips", opts: []Option{WithFIPSEndpoint()}, wantFIPS: aws.FIPSEndpointStateEnabled},
+		{name: "dual-stack", opts: []Option{WithDualStackEndpoint()}, wantDualStack: aws.DualStackEndpointStateEnabled},
+		{
+			name:          "fips dual-stack",
+			opts:          []Option{WithFIPSEndpoint(), WithDualStackEndpoint()},
+			wantFIPS:      aws.FIPSEndpointStateEnabled,
+			wantDualStack: aws.DualStackEndpointStateEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{}
+			_, err := newClient(api, testFunctionARN, tt.opts...).Do(_ctx, Request{HTTPMethod: http.MethodGet, Path: "/"})
+			require.NoError(t, err)
+
+			o := api.options(0)
+			assert.Equal(t, tt.wantFIPS, o.EndpointOptions.UseFIPSEndpoint)
+			assert.Equal(t, tt.wantDualStack, o.EndpointOptions.UseDualStackEndpoint)
+		})
+	}
+}
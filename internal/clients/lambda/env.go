@@ -0,0 +1,88 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"os"
+	"time"
+)
+
+// NewFromEnv returns a client configured by environment variables, e.g. for prefix ORDERS:
+//
+//	ORDERS_FUNCTION_ARN  function name, partial or full ARN (required)
+//	ORDERS_QUALIFIER     version or alias
+//	ORDERS_TIMEOUT       invocation timeout, e.g. 5s
+//	ORDERS_REGION        region, the default AWS config region if empty
+//	ORDERS_ENDPOINT      Lambda endpoint override, e.g. http://localhost:4566 for LocalStack
+//
+// AWS credentials and other settings come from the default AWS config. opts are applied after the env ones.
+func NewFromEnv(prefix string, opts ...Option) (Client, error) {
+	env, err := loadEnvConfig(prefix, os.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("loadEnvConfig: %w", err)
+	}
+
+	var cfgOpts []func(*config.LoadOptions) error
+	if env.region != "" {
+		cfgOpts = append(cfgOpts, config.WithRegion(env.region))
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), cfgOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("config.LoadDefaultConfig: %w", err)
+	}
+
+	cli := lambda.NewFromConfig(cfg, func(o *lambda.Options) {
+		if env.endpoint != "" {
+			o.BaseEndpoint = &env.endpoint
+		}
+	})
+
+	return New(cli, env.functionARN, append(env.options(), opts...)...)
+}
+
+type envConfig struct {
+	functionARN string
+	qualifier   string
+	timeout     time.Duration
+	region      string
+	endpoint    string
+}
+
+func loadEnvConfig(prefix string, getenv func(string) string) (envConfig, error) {
+	env := envConfig{
+		functionARN: getenv(prefix + "_FUNCTION_ARN"),
+		qualifier:   getenv(prefix + "_QUALIFIER"),
+		region:      getenv(prefix + "_REGION"),
+		endpoint:    getenv(prefix + "_ENDPOINT"),
+	}
+
+	if env.functionARN == "" {
+		return env, fmt.Errorf("%s_FUNCTION_ARN is not set", prefix)
+	}
+
+	if s := getenv(prefix + "_TIMEOUT"); s != "" {
+		timeout, err := time.ParseDuration(s)
+		if err != nil {
+			return env, fmt.Errorf("%s_TIMEOUT: %w", prefix, err)
+		}
+		env.timeout = timeout
+	}
+
+	return env, nil
+}
+
+func (env envConfig) options() []Option {
+	var opts []Option
+
+	if env.qualifier != "" {
+		opts = append(opts, WithQualifier(env.qualifier))
+	}
+	if env.timeout > 0 {
+		opts = append(opts, WithTimeout(env.timeout))
+	}
+
+	return opts
+}
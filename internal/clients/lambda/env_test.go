@@ -0,0 +1,35 @@
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("ORDERS_FUNCTION_ARN", "orders")
+	t.Setenv("ORDERS_QUALIFIER", "live")
+	t.Setenv("ORDERS_TIMEOUT", "3s")
+	t.Setenv("ORDERS_REGION", "eu-west-1")
+	t.Setenv("ORDERS_ENDPOINT", "http://localhost:4566")
+
+	cli, err := NewFromEnv("ORDERS")
+	require.NoError(t, err)
+
+	c := cli.(*client)
+	assert.Equal(t, "orders", c.functionARN)
+	assert.Equal(t, "live", c.call.qualifier)
+	assert.Equal(t, 3*time.Second, c.call.timeout)
+}
+
+func TestLoadEnvConfig(t *testing.T) {
+	env := map[string]string{"PAYMENTS_FUNCTION_ARN": "payments", "PAYMENTS_TIMEOUT": "soon"}
+	getenv := func(key string) string { return env[key] }
+
+	_, err := loadEnvConfig("PAYMENTS", getenv)
+	require.ErrorContains(t, err, "PAYMENTS_TIMEOUT")
+
+	_, err = loadEnvConfig("ORDERS", getenv)
+	require.ErrorContains(t, err, "ORDERS_FUNCTION_ARN is not set")
+}
@@ -0,0 +1,19 @@
+package lambda
+
+// Envelope selects how bodies are exchanged with the function.
+type Envelope int
+
+const (
+	// EnvelopeProxy wraps the request in APIGatewayProxyRequest and unwraps APIGatewayProxyResponse, it is the default.
+	EnvelopeProxy Envelope = iota
+	// EnvelopeRaw sends the request body as the invocation payload and returns the response payload as the body,
+	// for functions which are not API Gateway proxy handlers. HTTP method, path and headers are not sent.
+	EnvelopeRaw
+)
+
+// WithEnvelope sets the envelope, EnvelopeProxy by default.
+func WithEnvelope(e Envelope) Option {
+	return func(c *client) {
+		c.envelope = e
+	}
+}
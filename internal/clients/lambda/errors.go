@@ -0,0 +1,146 @@
+package lambda
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+)
+
+var (
+	// ErrFunctionError is returned when the function handler or runtime failed, i.e. FunctionError is set in the Invoke API output.
+	ErrFunctionError = errors.New("function error")
+	// ErrThrottled is returned when the Invoke API call is rejected due to the concurrency or request rate limits.
+	ErrThrottled = errors.New("throttled")
+	// ErrNotCallScoped is returned when an Option passed per call configures the client rather than the call,
+	// e.g. WithRetryPolicy, pass it to the constructor instead.
+	ErrNotCallScoped = errors.New("option is not call scoped")
+	// ErrClientClosed is passed to the callbacks of InvokeWithCallback after Close.
+	ErrClientClosed = errors.New("client closed")
+)
+
+// ErrUnexpectedStatus is returned for an unexpected Invoke API or proxy response status code,
+// Body is the response body for diagnosis.
+type ErrUnexpectedStatus struct {
+	Code int
+	Body string
+}
+
+func (e *ErrUnexpectedStatus) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("unexpected status code: %d", e.Code)
+	}
+
+	return fmt.Sprintf("unexpected status code: %d: %s", e.Code, e.Body)
+}
+
+// invokeError wraps an Invoke API error, adding ErrThrottled for throttling.
+func invokeError(err error) error {
+	var tmr *types.TooManyRequestsException
+	if errors.As(err, &tmr) {
+		return fmt.Errorf("cli.Invoke: %w: %w", ErrThrottled, err)
+	}
+
+	return withKind(ErrorKindTransport, fmt.Errorf("cli.Invoke: %w", err))
+}
+
+// InvocationError is the error payload returned when the function handler or runtime failed,
+// branch on Type to handle specific handler errors. It matches ErrFunctionError.
+type InvocationError struct {
+	// FunctionError is "Unhandled" for runtime errors or the FunctionError value set by the handler.
+	FunctionError string
+	Message       string
+	Type          string
+	// StackTrace frames as reported by the runtime, Go frames are formatted as "label path:line".
+	StackTrace []string
+}
+
+func (e *InvocationError) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("%s: %s", e.FunctionError, e.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", e.FunctionError, e.Type, e.Message)
+}
+
+func (e *InvocationError) Unwrap() error {
+	return ErrFunctionError
+}
+
+// newInvocationError parses the error payload, unparsable payloads are kept as the message.
+func newInvocationError(functionError string, payload []byte) *InvocationError {
+	e := &InvocationError{FunctionError: functionError}
+
+	var p struct {
+		ErrorMessage string            `json:"errorMessage"`
+		ErrorType    string            `json:"errorType"`
+		StackTrace   []json.RawMessage `json:"stackTrace"`
+	}
+	if err := json.Unmarshal(payload, &p); err != nil {
+		e.Message = string(payload)
+		return e
+	}
+
+	e.Message = p.ErrorMessage
+	e.Type = p.ErrorType
+
+	for _, raw := range p.StackTrace {
+		// Node.js and Python report strings
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			e.StackTrace = append(e.StackTrace, s)
+			continue
+		}
+
+		// Go reports frames
+		var frame struct {
+			Path  string `json:"path"`
+			Line  int    `json:"line"`
+			Label string `json:"label"`
+		}
+		if err := json.Unmarshal(raw, &frame); err == nil {
+			e.StackTrace = append(e.StackTrace, fmt.Sprintf("%s %s:%d", frame.Label, frame.Path, frame.Line))
+		}
+	}
+
+	return e
+}
+
+// Invoke payload limits.
+const (
+	MaxSyncPayloadSize  = 6 * 1024 * 1024
+	MaxAsyncPayloadSize = 256 * 1024
+)
+
+// ErrPayloadTooLarge is matched by PayloadTooLargeError.
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// PayloadTooLargeError is returned before calling the Invoke API if the marshaled payload exceeds the limit
+// of the invocation type, consider WithRequestCompression or WithClaimCheck.
+type PayloadTooLargeError struct {
+	Size  int
+	Limit int
+	Async bool
+}
+
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("payload too large for %s invocation: %d bytes, limit %d bytes", invocationMode(e.Async), e.Size, e.Limit)
+}
+
+func (e *PayloadTooLargeError) Unwrap() error {
+	return ErrPayloadTooLarge
+}
+
+// validatePayloadSize checks the payload against the limit of the invocation type.
+func validatePayloadSize(payload []byte, async bool) error {
+	limit := MaxSyncPayloadSize
+	if async {
+		limit = MaxAsyncPayloadSize
+	}
+
+	if len(payload) > limit {
+		return &PayloadTooLargeError{Size: len(payload), Limit: limit, Async: async}
+	}
+
+	return nil
+}
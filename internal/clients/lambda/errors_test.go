@@ -0,0 +1,107 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	t.Run("function error", func(t *testing.T) {
+		cli := newClient(&fakeAPI{invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+			return &lambda.InvokeOutput{StatusCode: http.StatusOK, FunctionError: pointer.To("Unhandled")}, nil
+		}}, testFunctionARN)
+
+		_, err := cli.Invoke(_ctx, "GET", "/", nil)
+		assert.ErrorIs(t, err, ErrFunctionError)
+	})
+
+	t.Run("throttled", func(t *testing.T) {
+		cli := newClient(&fakeAPI{invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+			return nil, fmt.Errorf("operation error: %w", &types.TooManyRequestsException{})
+		}}, testFunctionARN)
+
+		err := cli.InvokeAsync(_ctx, "GET", "/", nil)
+		assert.ErrorIs(t, err, ErrThrottled)
+
+		var tmr *types.TooManyRequestsException
+		assert.ErrorAs(t, err, &tmr, "the SDK error is kept")
+	})
+
+	t.Run("unexpected status", func(t *testing.T) {
+		cli := newClient(&fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+			return proxyOutput(in, http.StatusNotFound, `{"message":"not found"}`), nil
+		}}, testFunctionARN)
+
+		_, err := cli.Invoke(_ctx, "GET", "/", nil)
+
+		var use *ErrUnexpectedStatus
+		require.ErrorAs(t, err, &use)
+		assert.Equal(t, http.StatusNotFound, use.Code)
+		assert.Equal(t, `{"message":"not found"}`, use.Body)
+	})
+}
+
+func TestInvocationError(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    InvocationError
+	}{
+		{
+			name:    "go",
+			payload: `{"errorMessage":"boom","errorType":"errorString","stackTrace":[{"path":"main.go","line":12,"label":"handler"}]}`,
+			want:    InvocationError{FunctionError: "Unhandled", Message: "boom", Type: "errorString", StackTrace: []string{"handler main.go:12"}},
+		},
+		{
+			name:    "node",
+			payload: `{"errorType":"TypeError","errorMessage":"x is undefined","stackTrace":["TypeError: x is undefined","    at handler (/var/task/index.js:3:9)"]}`,
+			want: InvocationError{FunctionError: "Unhandled", Message: "x is undefined", Type: "TypeError",
+				StackTrace: []string{"TypeError: x is undefined", "    at handler (/var/task/index.js:3:9)"}},
+		},
+		{
+			name:    "not json",
+			payload: `Task timed out`,
+			want:    InvocationError{FunctionError: "Unhandled", Message: "Task timed out"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newClient(&fakeAPI{invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+				return &lambda.InvokeOutput{StatusCode: http.StatusOK, FunctionError: pointer.To("Unhandled"), Payload: []byte(tt.payload)}, nil
+			}}, testFunctionARN)
+
+			_, err := cli.Invoke(_ctx, "GET", "/", nil)
+			require.ErrorIs(t, err, ErrFunctionError)
+
+			var ie *InvocationError
+			require.ErrorAs(t, err, &ie)
+			assert.Equal(t, tt.want, *ie)
+		})
+	}
+}
+
+func TestPayloadTooLarge(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithEnvelope(EnvelopeRaw))
+
+	err := cli.InvokeAsync(_ctx, "POST", "/", make([]byte, MaxAsyncPayloadSize+1))
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+
+	var ptl *PayloadTooLargeError
+	require.ErrorAs(t, err, &ptl)
+	assert.Equal(t, PayloadTooLargeError{Size: MaxAsyncPayloadSize + 1, Limit: MaxAsyncPayloadSize, Async: true}, *ptl)
+	assert.Equal(t, ErrorKindPayloadTooLarge, KindOf(err))
+
+	_, err = cli.Invoke(_ctx, "POST", "/", make([]byte, MaxSyncPayloadSize+1))
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+
+	assert.Empty(t, api.calls(), "the Invoke API is not called")
+}
@@ -0,0 +1,104 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+)
+
+// DefaultDetailType is the detail-type of events put by EventBridgeTransport unless configured.
+const DefaultDetailType = "Lambda Invocation"
+
+// eventBridgeAPI is the subset of *eventbridge.Client used by EventBridgeTransport.
+type eventBridgeAPI interface {
+	PutEvents(ctx context.Context, params *eventbridge.PutEventsInput, optFns ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error)
+}
+
+// EventBridgeConfig configures an EventBridgeTransport.
+type EventBridgeConfig struct {
+	// EventBusName is a name or an ARN, the default bus if empty.
+	EventBusName string
+	Source       string
+	// DetailType defaults to DefaultDetailType.
+	DetailType string
+}
+
+// EventBridgeTransport is an AsyncTransport putting the wrapped events to an event bus, so that several consumers
+// can subscribe to invocations with rules matching Source and DetailType. The detail of events must be a JSON object,
+// which the proxy envelope always is. Wrap the function side handler with EventBridgeHandler.
+type EventBridgeTransport struct {
+	cli eventBridgeAPI
+	cfg EventBridgeConfig
+}
+
+// NewEventBridgeTransport returns a transport putting events to cfg.EventBusName, see WithAsyncTransport.
+func NewEventBridgeTransport(cli *eventbridge.Client, cfg EventBridgeConfig) (*EventBridgeTransport, error) {
+	if cli == nil {
+		return nil, fmt.Errorf("eventbridge.NewFromConfig returned nil")
+	}
+
+	if cfg.Source == "" {
+		return nil, fmt.Errorf("source is empty")
+	}
+
+	return newEventBridgeTransport(cli, cfg), nil
+}
+
+func newEventBridgeTransport(cli eventBridgeAPI, cfg EventBridgeConfig) *EventBridgeTransport {
+	if cfg.DetailType == "" {
+		cfg.DetailType = DefaultDetailType
+	}
+
+	return &EventBridgeTransport{cli: cli, cfg: cfg}
+}
+
+// Send puts payload as the event detail and returns the event ID.
+func (t *EventBridgeTransport) Send(ctx context.Context, payload []byte) (string, error) {
+	output, err := t.cli.PutEvents(ctx, &eventbridge.PutEventsInput{Entries: []ebtypes.PutEventsRequestEntry{{
+		EventBusName: pointer.ToStringOrNil(t.cfg.EventBusName),
+		Source:       pointer.To(t.cfg.Source),
+		DetailType:   pointer.To(t.cfg.DetailType),
+		Detail:       pointer.To(string(payload)),
+	}}})
+	if err != nil {
+		return "", fmt.Errorf("cli.PutEvents: %w", err)
+	}
+
+	if len(output.Entries) != 1 {
+		return "", fmt.Errorf("output.Entries: got %d, want 1", len(output.Entries))
+	}
+
+	// PutEvents succeeds with failed entries, e.g. on throttling
+	entry := output.Entries[0]
+	if entry.ErrorCode != nil {
+		return "", fmt.Errorf("output.Entries[0]: %s: %s", *entry.ErrorCode, pointer.Get(entry.ErrorMessage))
+	}
+
+	return pointer.Get(entry.EventId), nil
+}
+
+// EventBridgeHandler adapts the function side handler to events put by EventBridgeTransport and delivered by a rule.
+// Errors and 5xx responses fail the invocation to be retried by EventBridge.
+func EventBridgeHandler(next Handler) func(ctx context.Context, event events.CloudWatchEvent) error {
+	return func(ctx context.Context, event events.CloudWatchEvent) error {
+		var req events.APIGatewayProxyRequest
+		if err := json.Unmarshal(event.Detail, &req); err != nil {
+			return fmt.Errorf("json.Unmarshal: %w", err)
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if resp.StatusCode >= 500 {
+			return &ErrUnexpectedStatus{Code: resp.StatusCode, Body: resp.Body}
+		}
+
+		return nil
+	}
+}
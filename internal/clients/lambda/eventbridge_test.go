@@ -0,0 +1,65 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type fakeEventBridge struct {
+	entry ebtypes.PutEventsResultEntry
+	puts  []*eventbridge.PutEventsInput
+}
+
+func (f *fakeEventBridge) PutEvents(_ context.Context, in *eventbridge.PutEventsInput, _ ...func(*eventbridge.Options)) (*eventbridge.PutEventsOutput, error) {
+	f.puts = append(f.puts, in)
+
+	return &eventbridge.PutEventsOutput{Entries: []ebtypes.PutEventsResultEntry{f.entry}}, nil
+}
+
+func TestWithAsyncTransport_EventBridge(t *testing.T) {
+	bus := &fakeEventBridge{entry: ebtypes.PutEventsResultEntry{EventId: pointer.To("event-1")}}
+	transport := newEventBridgeTransport(bus, EventBridgeConfig{EventBusName: "orders", Source: "orders.api"})
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithAsyncTransport(transport))
+
+	resp, err := cli.Do(_ctx, Request{HTTPMethod: "POST", Path: "/orders", Async: true})
+	require.NoError(t, err)
+	assert.Equal(t, "event-1", resp.RequestID)
+
+	entry := bus.puts[0].Entries[0]
+	assert.Equal(t, "orders", *entry.EventBusName)
+	assert.Equal(t, "orders.api", *entry.Source)
+	assert.Equal(t, DefaultDetailType, *entry.DetailType)
+
+	var event events.APIGatewayProxyRequest
+	require.NoError(t, json.Unmarshal([]byte(*entry.Detail), &event))
+	assert.Equal(t, "/orders", event.Path)
+
+	bus.entry = ebtypes.PutEventsResultEntry{ErrorCode: pointer.To("ThrottlingException"), ErrorMessage: pointer.To("rate exceeded")}
+	err = cli.InvokeAsync(_ctx, "POST", "/orders", nil)
+	require.ErrorContains(t, err, "ThrottlingException: rate exceeded")
+}
+
+func TestEventBridgeHandler(t *testing.T) {
+	handler := EventBridgeHandler(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		switch req.Path {
+		case "/error":
+			return events.APIGatewayProxyResponse{}, errors.New("boom")
+		case "/unavailable":
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusServiceUnavailable}, nil
+		}
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	require.NoError(t, handler(_ctx, events.CloudWatchEvent{Detail: json.RawMessage(`{"path":"/orders"}`)}))
+	require.Error(t, handler(_ctx, events.CloudWatchEvent{Detail: json.RawMessage(`{"path":"/error"}`)}))
+	require.ErrorAs(t, handler(_ctx, events.CloudWatchEvent{Detail: json.RawMessage(`{"path":"/unavailable"}`)}), new(*ErrUnexpectedStatus))
+}
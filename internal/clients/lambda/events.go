@@ -0,0 +1,146 @@
+package lambda
+
+import (
+	"context"
+	"time"
+)
+
+// EventSchemaVersion is the version of the hook event structs, it is increased on breaking changes only,
+// new fields may be added within a version.
+const EventSchemaVersion = 1
+
+// Event types, stable across schema versions.
+const (
+	EventInvocationStarted   = "invocation.started"
+	EventInvocationFinished  = "invocation.finished"
+	EventRetryScheduled      = "retry.scheduled"
+	EventBreakerStateChanged = "breaker.state_changed"
+	EventThrottled           = "invocation.throttled"
+)
+
+// Event is implemented by every struct passed to an EventSink, switch on its concrete type or Header().Type.
+type Event interface {
+	Header() EventHeader
+}
+
+// EventHeader is embedded into every event.
+type EventHeader struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	FunctionARN   string    `json:"functionArn"`
+}
+
+func (h EventHeader) Header() EventHeader {
+	return h
+}
+
+type InvocationStarted struct {
+	EventHeader
+	HTTPMethod   string `json:"httpMethod"`
+	Path         string `json:"path"`
+	Async        bool   `json:"async"`
+	RequestBytes int    `json:"requestBytes"`
+}
+
+type InvocationFinished struct {
+	EventHeader
+	HTTPMethod    string        `json:"httpMethod"`
+	Path          string        `json:"path"`
+	Async         bool          `json:"async"`
+	RequestID     string        `json:"requestId,omitempty"`
+	StatusCode    int           `json:"statusCode,omitempty"`
+	ResponseBytes int           `json:"responseBytes"`
+	Duration      time.Duration `json:"durationNs"`
+	Error         string        `json:"error,omitempty"`
+	ErrorClass    string        `json:"errorClass,omitempty"`
+}
+
+type RetryScheduled struct {
+	EventHeader
+	HTTPMethod string        `json:"httpMethod"`
+	Path       string        `json:"path"`
+	Attempt    int           `json:"attempt"`
+	Delay      time.Duration `json:"delayNs"`
+	Error      string        `json:"error"`
+}
+
+// Throttled is a throttled attempt retried by the SDK, see WithSDKRetry.
+type Throttled struct {
+	EventHeader
+	Attempt int           `json:"attempt"`
+	Delay   time.Duration `json:"delayNs"`
+	Error   string        `json:"error"`
+}
+
+// BreakerStateChanged is a fallback of a Canary to Stable or its Reset, From and To are BreakerClosed or BreakerOpen.
+type BreakerStateChanged struct {
+	EventHeader
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// EventSink receives hook events, it is called synchronously on the invocation path.
+type EventSink func(ctx context.Context, e Event)
+
+// WithEventSink passes hook events to the sink, invocation events are emitted by an interceptor
+// at its position among WithInterceptors options.
+func WithEventSink(sink EventSink) Option {
+	return func(c *client) {
+		c.eventSinks = append(c.eventSinks, sink)
+		c.interceptors = append(c.interceptors, c.eventsInterceptor(sink))
+	}
+}
+
+func (c *client) eventHeader(eventType string) EventHeader {
+	return EventHeader{
+		SchemaVersion: EventSchemaVersion,
+		Type:          eventType,
+		Time:          time.Now(),
+		FunctionARN:   c.redact(c.functionARN),
+	}
+}
+
+// emit passes e to every sink, it is used by components other than the events interceptor.
+func (c *client) emit(ctx context.Context, e Event) {
+	for _, sink := range c.eventSinks {
+		sink(ctx, e)
+	}
+}
+
+func (c *client) eventsInterceptor(sink EventSink) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		started := c.eventHeader(EventInvocationStarted)
+
+		sink(ctx, InvocationStarted{
+			EventHeader:  started,
+			HTTPMethod:   req.HTTPMethod,
+			Path:         req.Path,
+			Async:        req.Async,
+			RequestBytes: len(req.Body),
+		})
+
+		resp, err := next(ctx, req)
+
+		finished := InvocationFinished{
+			EventHeader: c.eventHeader(EventInvocationFinished),
+			HTTPMethod:  req.HTTPMethod,
+			Path:        req.Path,
+			Async:       req.Async,
+			ErrorClass:  errorClass(err),
+		}
+		finished.Duration = finished.Time.Sub(started.Time)
+		if resp != nil {
+			finished.RequestID = resp.RequestID
+			finished.StatusCode = resp.StatusCode
+			finished.ResponseBytes = len(resp.Body)
+		}
+		if err != nil {
+			finished.Error = c.redact(err.Error())
+		}
+
+		sink(ctx, finished)
+
+		return resp, err
+	}
+}
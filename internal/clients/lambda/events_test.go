@@ -0,0 +1,42 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWithEventSink(t *testing.T) {
+	var events []Event
+
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithEventSink(func(ctx context.Context, e Event) {
+		events = append(events, e)
+	}))
+
+	_, err := cli.Invoke(_ctx, "GET", "/orders", nil)
+	require.NoError(t, err)
+
+	require.Len(t, events, 2)
+
+	started, ok := events[0].(InvocationStarted)
+	require.True(t, ok)
+	assert.Equal(t, EventSchemaVersion, started.SchemaVersion)
+	assert.Equal(t, EventInvocationStarted, started.Type)
+	assert.Equal(t, "/orders", started.Path)
+
+	finished, ok := events[1].(InvocationFinished)
+	require.True(t, ok)
+	assert.Equal(t, EventInvocationFinished, finished.Header().Type)
+	assert.Equal(t, 200, finished.StatusCode)
+
+	data, err := json.Marshal(finished)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, float64(EventSchemaVersion), decoded["schemaVersion"])
+	assert.Equal(t, "invocation.finished", decoded["type"])
+	assert.Equal(t, testFunctionARN, decoded["functionArn"])
+}
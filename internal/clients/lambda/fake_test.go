@@ -0,0 +1,95 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const testFunctionARN = "arn:aws:lambda:eu-central-1:000000000000:function:my-function"
+
+var _ctx = context.Background()
+
+// fakeAPI is an in-memory lambdaAPI recording every input.
+type fakeAPI struct {
+	mu     sync.Mutex
+	inputs []*lambda.InvokeInput
+	optFns [][]func(*lambda.Options)
+
+	invoke func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error)
+}
+
+func (f *fakeAPI) Invoke(ctx context.Context, in *lambda.InvokeInput, optFns ...func(*lambda.Options)) (*lambda.InvokeOutput, error) {
+	f.mu.Lock()
+	f.inputs = append(f.inputs, in)
+	f.optFns = append(f.optFns, optFns)
+	f.mu.Unlock()
+
+	if f.invoke == nil {
+		return proxyOutput(in, http.StatusOK, "ok"), nil
+	}
+
+	return f.invoke(ctx, in)
+}
+
+// GetFunction resolves names to ARNs in the account and region of testFunctionARN.
+func (f *fakeAPI) GetFunction(_ context.Context, in *lambda.GetFunctionInput, _ ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	name := *in.FunctionName
+	if i := strings.LastIndex(name, ":function:"); i >= 0 {
+		name = name[i+len(":function:"):]
+	}
+
+	return &lambda.GetFunctionOutput{Configuration: &types.FunctionConfiguration{
+		FunctionArn: pointer.To("arn:aws:lambda:eu-central-1:000000000000:function:" + name),
+	}}, nil
+}
+
+func (f *fakeAPI) calls() []*lambda.InvokeInput {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*lambda.InvokeInput(nil), f.inputs...)
+}
+
+// options applies the per-call options of the i-th call.
+func (f *fakeAPI) options(i int) lambda.Options {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var o lambda.Options
+	for _, fn := range f.optFns[i] {
+		fn(&o)
+	}
+
+	return o
+}
+
+// proxyOutput builds the output Lambda returns for the given input and proxy response.
+func proxyOutput(in *lambda.InvokeInput, statusCode int, body string) *lambda.InvokeOutput {
+	if in.InvocationType == "Event" {
+		return &lambda.InvokeOutput{StatusCode: http.StatusAccepted}
+	}
+
+	payload, err := json.Marshal(events.APIGatewayProxyResponse{StatusCode: statusCode, Body: body})
+	if err != nil {
+		panic(err)
+	}
+
+	return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}
+}
+
+// proxyRequest decodes the event sent with the given input.
+func proxyRequest(in *lambda.InvokeInput) events.APIGatewayProxyRequest {
+	var req events.APIGatewayProxyRequest
+	if err := json.Unmarshal(in.Payload, &req); err != nil {
+		panic(err)
+	}
+
+	return req
+}
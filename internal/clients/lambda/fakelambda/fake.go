@@ -0,0 +1,156 @@
+// Package fakelambda runs Go handlers in-process behind a real lambda.Client,
+// so that unit tests exercise the same envelope wrapping and unwrapping without LocalStack or mocks.
+package fakelambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awslambda "github.com/aws/aws-sdk-go-v2/service/lambda"
+	"io"
+	"lambda-invoker/internal/clients/lambda"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// FunctionARN is the ARN of the fake function.
+const FunctionARN = "arn:aws:lambda:eu-central-1:000000000000:function:fake"
+
+// Fake is an in-process Lambda function serving proxy requests with handlers registered per method and path.
+type Fake struct {
+	mux *lambda.Mux
+
+	mu       sync.Mutex
+	requests []events.APIGatewayProxyRequest
+}
+
+func New() *Fake {
+	return &Fake{mux: lambda.NewMux()}
+}
+
+// Handle registers the handler serving requests matching the pattern, e.g. "GET /orders/{id}",
+// see lambda.Mux. Requests matching no route get 404 Not Found.
+func (f *Fake) Handle(pattern string, handler lambda.Handler) {
+	f.mux.Handle(pattern, handler)
+}
+
+// Client returns a client of the fake function, handlers run on the invoking goroutine,
+// async invocations included. Handler errors and panics are returned as function errors.
+func (f *Fake) Client(opts ...lambda.Option) (lambda.Client, error) {
+	cli := awslambda.New(awslambda.Options{
+		Region:       "eu-central-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String("http://lambda.fake"),
+		HTTPClient:   httpClient{fake: f},
+	})
+
+	c, err := lambda.New(cli, FunctionARN, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("lambda.New: %w", err)
+	}
+
+	return c, nil
+}
+
+// Requests returns the proxy requests received so far, in order.
+func (f *Fake) Requests() []events.APIGatewayProxyRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]events.APIGatewayProxyRequest(nil), f.requests...)
+}
+
+// invoke runs the handler and returns the invocation payload and the function error type, if any.
+func (f *Fake) invoke(ctx context.Context, payload []byte) (out []byte, functionError string) {
+	var req events.APIGatewayProxyRequest
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return errorPayload(fmt.Errorf("json.Unmarshal: %w", err)), "Unhandled"
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			out, functionError = errorPayload(fmt.Errorf("panic: %v", r)), "Unhandled"
+		}
+	}()
+
+	resp, err := f.mux.Invoke(ctx, req)
+	if err != nil {
+		return errorPayload(err), "Unhandled"
+	}
+
+	out, err = json.Marshal(resp)
+	if err != nil {
+		return errorPayload(fmt.Errorf("json.Marshal: %w", err)), "Unhandled"
+	}
+
+	return out, ""
+}
+
+// errorPayload is the payload Lambda returns for errors of Go handlers.
+func errorPayload(err error) []byte {
+	out, _ := json.Marshal(map[string]string{
+		"errorMessage": err.Error(),
+		"errorType":    fmt.Sprintf("%T", err),
+	})
+
+	return out
+}
+
+// httpClient serves the Invoke and GetFunction API calls of the SDK.
+type httpClient struct {
+	fake *Fake
+}
+
+func (c httpClient) Do(req *http.Request) (*http.Response, error) {
+	var payload []byte
+	if req.Body != nil {
+		var err error
+		if payload, err = io.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("io.ReadAll: %w", err)
+		}
+	}
+
+	header := http.Header{"Content-Type": []string{"application/json"}}
+
+	switch {
+	case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/invocations"):
+		out, functionError := c.fake.invoke(req.Context(), payload)
+		header.Set("X-Amz-Executed-Version", "$LATEST")
+		if functionError != "" {
+			header.Set("X-Amz-Function-Error", functionError)
+		}
+
+		if req.Header.Get("X-Amz-Invocation-Type") == "Event" {
+			return response(req, http.StatusAccepted, header, nil), nil
+		}
+		return response(req, http.StatusOK, header, out), nil
+
+	case req.Method == http.MethodGet && strings.HasPrefix(req.URL.Path, "/2015-03-31/functions/"):
+		out, err := json.Marshal(map[string]any{"Configuration": map[string]string{"FunctionArn": FunctionARN}})
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %w", err)
+		}
+		return response(req, http.StatusOK, header, out), nil
+	}
+
+	header.Set("X-Amzn-ErrorType", "ResourceNotFoundException")
+	return response(req, http.StatusNotFound, header, []byte(`{"message":"unsupported API call"}`)), nil
+}
+
+func response(req *http.Request, statusCode int, header http.Header, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    statusCode,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,67 @@
+package fakelambda
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lambda-invoker/internal/clients/lambda"
+	"net/http"
+	"testing"
+)
+
+var _ctx = context.Background()
+
+func TestFake(t *testing.T) {
+	fake := New()
+	fake.Handle("GET /orders/{id}", func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: `{"id":"` + req.PathParameters["id"] + `"}`}, nil
+	})
+	fake.Handle("POST /orders", func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, errors.New("out of stock")
+	})
+	fake.Handle("POST /events", func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, nil
+	})
+
+	cli, err := fake.Client()
+	require.NoError(t, err)
+
+	var out struct{ ID string }
+	require.NoError(t, cli.Call(_ctx, "GET", "/orders/42", nil, &out))
+	assert.Equal(t, "42", out.ID)
+
+	_, err = cli.Invoke(_ctx, "POST", "/orders", []byte(`{}`))
+	require.ErrorIs(t, err, lambda.ErrFunctionError)
+	assert.Contains(t, err.Error(), "out of stock")
+
+	_, err = cli.Invoke(_ctx, "GET", "/payments", nil)
+	var statusErr *lambda.ErrUnexpectedStatus
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotFound, statusErr.Code)
+
+	require.NoError(t, cli.InvokeAsync(_ctx, "POST", "/events", []byte(`{}`)))
+
+	requests := fake.Requests()
+	require.Len(t, requests, 4)
+	assert.Equal(t, "/events", requests[3].Path)
+
+	arn, err := cli.FunctionARN(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, FunctionARN, arn)
+}
+
+func TestFake_Panic(t *testing.T) {
+	fake := New()
+	fake.Handle("GET /", func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		panic("boom")
+	})
+
+	cli, err := fake.Client()
+	require.NoError(t, err)
+
+	_, err = cli.Invoke(_ctx, "GET", "/", nil)
+	require.ErrorIs(t, err, lambda.ErrFunctionError)
+	assert.Contains(t, err.Error(), "boom")
+}
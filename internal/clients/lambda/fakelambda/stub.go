@@ -0,0 +1,232 @@
+package fakelambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/aws/aws-lambda-go/events"
+	"lambda-invoker/internal/clients/lambda"
+	"maps"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// Stub is a Fake configured with expectations, so that tests read like HTTP stubs:
+//
+//	stub := fakelambda.NewStub(t)
+//	stub.OnPOST("/orders").ReturnJSON(http.StatusCreated, order).Times(1)
+//	cli := stub.Client()
+//
+// Calls matching no expectation fail the test and get 501 Not Implemented.
+// Call counts set with Times are asserted when the test finishes.
+type Stub struct {
+	t    testing.TB
+	fake *Fake
+
+	mu           sync.Mutex
+	expectations []*Expectation
+}
+
+// NewStub returns a stub failing t on unexpected calls and unmet call counts.
+func NewStub(t testing.TB) *Stub {
+	s := &Stub{t: t, fake: New()}
+	s.fake.Handle("ANY /", s.handle)
+	s.fake.Handle("ANY /{proxy+}", s.handle)
+
+	t.Cleanup(s.AssertExpectations)
+
+	return s
+}
+
+// On expects calls matching the pattern, e.g. "/orders/{id}", see lambda.Mux.
+// Expectations are matched in registration order, exhausted ones are skipped.
+func (s *Stub) On(httpMethod, pattern string) *Expectation {
+	mux := lambda.NewMux()
+	e := &Expectation{
+		method:  httpMethod,
+		pattern: pattern,
+		times:   -1,
+		mux:     mux,
+		resp:    events.APIGatewayProxyResponse{StatusCode: http.StatusOK},
+	}
+	mux.Handle(httpMethod+" "+pattern, e.handle)
+
+	s.mu.Lock()
+	s.expectations = append(s.expectations, e)
+	s.mu.Unlock()
+
+	return e
+}
+
+func (s *Stub) OnGET(pattern string) *Expectation    { return s.On(http.MethodGet, pattern) }
+func (s *Stub) OnPOST(pattern string) *Expectation   { return s.On(http.MethodPost, pattern) }
+func (s *Stub) OnPUT(pattern string) *Expectation    { return s.On(http.MethodPut, pattern) }
+func (s *Stub) OnPATCH(pattern string) *Expectation  { return s.On(http.MethodPatch, pattern) }
+func (s *Stub) OnDELETE(pattern string) *Expectation { return s.On(http.MethodDelete, pattern) }
+
+// Client returns a client of the stub, it fails the test if the client can't be created.
+func (s *Stub) Client(opts ...lambda.Option) lambda.Client {
+	s.t.Helper()
+
+	cli, err := s.fake.Client(opts...)
+	if err != nil {
+		s.t.Fatalf("fake.Client: %v", err)
+	}
+
+	return cli
+}
+
+// Requests returns all proxy requests received so far, in order.
+func (s *Stub) Requests() []events.APIGatewayProxyRequest {
+	return s.fake.Requests()
+}
+
+// AssertExpectations fails the test if an expectation with Times was not called exactly that many times.
+// It is called when the test finishes.
+func (s *Stub) AssertExpectations() {
+	s.t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.expectations {
+		if calls, times := e.Calls(), e.wantTimes(); times >= 0 && calls != times {
+			s.t.Errorf("fakelambda: %s %s called %d times, expected %d", e.method, e.pattern, calls, times)
+		}
+	}
+}
+
+func (s *Stub) handle(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	s.mu.Lock()
+	expectations := append([]*Expectation(nil), s.expectations...)
+	s.mu.Unlock()
+
+	for _, e := range expectations {
+		if resp, ok, err := e.invoke(ctx, req); ok {
+			return resp, err
+		}
+	}
+
+	s.t.Errorf("fakelambda: unexpected call %s %s", req.HTTPMethod, req.Path)
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusNotImplemented,
+		Body:       fmt.Sprintf("unexpected call %s %s", req.HTTPMethod, req.Path),
+	}, nil
+}
+
+// Expectation is a stubbed route, it responds 200 OK with an empty body unless configured otherwise.
+type Expectation struct {
+	method  string
+	pattern string
+	mux     *lambda.Mux
+
+	mu    sync.Mutex
+	times int
+	calls int
+	resp  events.APIGatewayProxyResponse
+	err   error
+}
+
+// Return sets the response status code and body.
+func (e *Expectation) Return(statusCode int, body string) *Expectation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.resp.StatusCode, e.resp.Body = statusCode, body
+
+	return e
+}
+
+// ReturnJSON sets the response status code and v marshalled to JSON as the body, it panics if v can't be marshalled.
+func (e *Expectation) ReturnJSON(statusCode int, v any) *Expectation {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("fakelambda: json.Marshal: %v", err))
+	}
+
+	return e.Return(statusCode, string(body)).WithHeader("Content-Type", "application/json")
+}
+
+// ReturnError makes the handler fail with err, it is returned to the client as a function error.
+func (e *Expectation) ReturnError(err error) *Expectation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.err = err
+
+	return e
+}
+
+// WithHeader sets a response header.
+func (e *Expectation) WithHeader(name, value string) *Expectation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.resp.Headers == nil {
+		e.resp.Headers = make(map[string]string)
+	}
+	e.resp.Headers[name] = value
+
+	return e
+}
+
+// Times expects exactly n calls, further calls are matched against following expectations.
+func (e *Expectation) Times(n int) *Expectation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.times = n
+
+	return e
+}
+
+// Once is Times(1).
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+// Calls returns the number of calls served by the expectation.
+func (e *Expectation) Calls() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.calls
+}
+
+func (e *Expectation) wantTimes() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.times
+}
+
+type servedKey struct{}
+
+// invoke serves req if it matches the expectation and the expectation is not exhausted.
+func (e *Expectation) invoke(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, bool, error) {
+	var served bool
+
+	resp, err := e.mux.Invoke(context.WithValue(ctx, servedKey{}, &served), req)
+
+	return resp, served, err
+}
+
+// handle is called by the mux for matching requests.
+func (e *Expectation) handle(ctx context.Context, _ events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.times >= 0 && e.calls >= e.times {
+		return events.APIGatewayProxyResponse{}, nil
+	}
+
+	e.calls++
+	*ctx.Value(servedKey{}).(*bool) = true
+
+	resp := e.resp
+	resp.Headers = maps.Clone(e.resp.Headers)
+
+	return resp, e.err
+}
@@ -0,0 +1,69 @@
+package fakelambda
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lambda-invoker/internal/clients/lambda"
+	"net/http"
+	"testing"
+)
+
+func TestStub(t *testing.T) {
+	stub := NewStub(t)
+	created := stub.OnPOST("/orders").ReturnJSON(http.StatusCreated, map[string]string{"id": "42"}).Once()
+	stub.OnPOST("/orders").ReturnError(errors.New("out of stock"))
+	stub.OnGET("/orders/{id}").Return(http.StatusOK, "order").WithHeader("ETag", `"v1"`)
+
+	cli := stub.Client(lambda.WithAcceptStatuses(http.StatusOK, http.StatusCreated))
+
+	var out struct{ ID string }
+	require.NoError(t, cli.Call(_ctx, "POST", "/orders", map[string]int{"qty": 1}, &out))
+	assert.Equal(t, "42", out.ID)
+
+	_, err := cli.Invoke(_ctx, "POST", "/orders", nil)
+	require.ErrorIs(t, err, lambda.ErrFunctionError, "exhausted expectations are skipped")
+
+	resp, err := cli.Do(_ctx, lambda.Request{HTTPMethod: "GET", Path: "/orders/42"})
+	require.NoError(t, err)
+	assert.Equal(t, "order", resp.Body)
+	assert.Equal(t, `"v1"`, resp.Headers["ETag"])
+
+	assert.Equal(t, 1, created.Calls())
+	assert.Len(t, stub.Requests(), 3)
+}
+
+func TestStub_Failures(t *testing.T) {
+	rec := &recordingT{TB: t}
+
+	stub := NewStub(rec)
+	stub.OnGET("/orders").Times(2)
+	cli := stub.Client()
+
+	_, err := cli.Invoke(_ctx, "GET", "/orders", nil)
+	require.NoError(t, err)
+
+	_, err = cli.Invoke(_ctx, "DELETE", "/orders", nil)
+	var statusErr *lambda.ErrUnexpectedStatus
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusNotImplemented, statusErr.Code)
+
+	stub.AssertExpectations()
+	assert.Equal(t, []string{
+		"fakelambda: unexpected call DELETE /orders",
+		"fakelambda: GET /orders called 1 times, expected 2",
+	}, rec.errors)
+}
+
+// recordingT records errors instead of failing the test.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Helper() {}
@@ -0,0 +1,50 @@
+package lambda
+
+import (
+	"context"
+)
+
+// Future is a handle to a sync invocation running in the background, see Client.InvokeFuture.
+type Future struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	body string
+	err  error
+}
+
+// InvokeFuture starts a sync invocation in the background and returns immediately,
+// so that several invocations can run concurrently and be joined with Future.Result.
+func (c *client) InvokeFuture(ctx context.Context, httpMethod, path string, body []byte, opts ...Option) *Future {
+	ctx, cancel := context.WithCancel(ctx)
+
+	f := &Future{
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+
+	go func() {
+		defer close(f.done)
+		defer cancel()
+
+		f.body, f.err = c.Invoke(ctx, httpMethod, path, body, opts...)
+	}()
+
+	return f
+}
+
+// Done returns a channel which is closed once the invocation completes.
+func (f *Future) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result waits for the invocation to complete and returns its outcome.
+func (f *Future) Result() (string, error) {
+	<-f.done
+	return f.body, f.err
+}
+
+// Cancel cancels the context of the invocation, it is a no-op once the invocation completed.
+func (f *Future) Cancel() {
+	f.cancel()
+}
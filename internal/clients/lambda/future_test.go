@@ -0,0 +1,46 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestInvokeFuture(t *testing.T) {
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusOK, proxyRequest(in).Path), nil
+	}}
+
+	cli := newClient(api, testFunctionARN)
+
+	a := cli.InvokeFuture(_ctx, "GET", "/a", nil)
+	b := cli.InvokeFuture(_ctx, "GET", "/b", nil)
+
+	<-a.Done()
+
+	body, err := a.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "/a", body)
+
+	body, err = b.Result()
+	require.NoError(t, err)
+	assert.Equal(t, "/b", body)
+}
+
+func TestInvokeFuture_Cancel(t *testing.T) {
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+
+	cli := newClient(api, testFunctionARN)
+
+	f := cli.InvokeFuture(_ctx, "GET", "/", nil)
+	f.Cancel()
+
+	_, err := f.Result()
+	assert.ErrorIs(t, err, context.Canceled)
+}
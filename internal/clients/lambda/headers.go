@@ -0,0 +1,77 @@
+package lambda
+
+import (
+	"maps"
+	"net/http"
+	"strings"
+)
+
+// mergeMultiValueHeaders returns headers with the last value of every multi-value header and the multi-value
+// headers including the single-value ones, as API Gateway delivers them. Multi-value headers take precedence.
+func mergeMultiValueHeaders(headers map[string]string, multiValue map[string][]string) (map[string]string, map[string][]string) {
+	outHeaders := maps.Clone(headers)
+	if outHeaders == nil {
+		outHeaders = make(map[string]string, len(multiValue))
+	}
+
+	outMultiValue := make(map[string][]string, len(headers)+len(multiValue))
+	for name, value := range headers {
+		outMultiValue[name] = []string{value}
+	}
+
+	for name, values := range multiValue {
+		if len(values) == 0 {
+			continue
+		}
+		outMultiValue[name] = values
+		outHeaders[name] = values[len(values)-1]
+	}
+
+	return outHeaders, outMultiValue
+}
+
+// HTTPHeader returns Headers and MultiValueHeaders merged under canonical names, MultiValueHeaders take
+// precedence for names in both, as API Gateway does.
+func (r *Response) HTTPHeader() http.Header {
+	header := make(http.Header, len(r.Headers)+len(r.MultiValueHeaders))
+	for name, value := range r.Headers {
+		header.Set(name, value)
+	}
+
+	for name, values := range r.MultiValueHeaders {
+		header.Del(name)
+		for _, value := range values {
+			header.Add(name, value)
+		}
+	}
+
+	return header
+}
+
+// Header returns the first value of the named response header matched case-insensitively, MultiValueHeaders take
+// precedence over Headers, empty if it is not set.
+func (r *Response) Header(name string) string {
+	for key, values := range r.MultiValueHeaders {
+		if len(values) > 0 && strings.EqualFold(key, name) {
+			return values[0]
+		}
+	}
+
+	for key, value := range r.Headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// ContentType returns the Content-Type response header.
+func (r *Response) ContentType() string {
+	return r.Header("Content-Type")
+}
+
+// Location returns the Location response header, e.g. of a 201 or a redirect.
+func (r *Response) Location() string {
+	return r.Header("Location")
+}
@@ -0,0 +1,57 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestMultiValueHeaders(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		req := proxyRequest(in)
+
+		assert.Equal(t, map[string]string{"Accept": "text/plain", "X-Id": "1"}, req.Headers)
+		assert.Equal(t, map[string][]string{"Accept": {"application/json", "text/plain"}, "X-Id": {"1"}}, req.MultiValueHeaders)
+
+		return proxyOutput(in, http.StatusOK, ""), nil
+	}}
+
+	c := newClient(api, testFunctionARN)
+
+	headers := map[string]string{"Accept": "*/*", "X-Id": "1"}
+	_, err := c.Do(_ctx, Request{
+		HTTPMethod:        http.MethodGet,
+		Headers:           headers,
+		MultiValueHeaders: map[string][]string{"Accept": {"application/json", "text/plain"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "*/*", headers["Accept"])
+}
+
+func TestResponse_HTTPHeader(t *testing.T) {
+	resp := &Response{
+		Headers:           map[string]string{"content-type": "text/plain", "set-cookie": "a=1"},
+		MultiValueHeaders: map[string][]string{"set-cookie": {"a=1", "b=2"}},
+	}
+
+	assert.Equal(t, http.Header{
+		"Content-Type": {"text/plain"},
+		"Set-Cookie":   {"a=1", "b=2"},
+	}, resp.HTTPHeader())
+}
+
+func TestResponse_Header(t *testing.T) {
+	resp := &Response{
+		Headers:           map[string]string{"content-type": "application/json", "X-Id": "1"},
+		MultiValueHeaders: map[string][]string{"location": {"/orders/1", "/orders/2"}, "X-Id": {"2"}},
+	}
+
+	assert.Equal(t, "application/json", resp.ContentType())
+	assert.Equal(t, "/orders/1", resp.Location())
+	assert.Equal(t, "2", resp.Header("x-id"))
+	assert.Empty(t, resp.Header("Etag"))
+}
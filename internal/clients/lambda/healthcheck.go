@@ -0,0 +1,97 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"log/slog"
+	"net/http"
+)
+
+// HealthCheckConfig configures the optional invocations of HealthCheck.
+type HealthCheckConfig struct {
+	// DryRun verifies the invoke permission and the function configuration with a DryRun invocation,
+	// which does not run the function.
+	DryRun bool
+	// Ping is invoked and must succeed, e.g. GET /health, it is skipped if HTTPMethod is empty.
+	Ping Request
+}
+
+// WithHealthCheck configures HealthCheck to also invoke the function.
+func WithHealthCheck(cfg HealthCheckConfig) Option {
+	return func(c *client) {
+		c.healthCheck = cfg
+	}
+}
+
+// HealthCheck verifies that the function exists, is Active and is not throttled by zero reserved concurrency,
+// then runs the invocations of WithHealthCheck, if any. A function not ready is reported as FunctionNotReadyError.
+func (c *client) HealthCheck(ctx context.Context) error {
+	if c.balancer != nil {
+		return c.balancer.healthCheck(ctx, c.callConfig(ctx))
+	}
+
+	qualifier := c.callConfig(ctx).qualifier
+
+	reason, err := c.notReadyReason(ctx, qualifier, false)
+	if err != nil {
+		return fmt.Errorf("health check: %w", err)
+	}
+
+	if reason != "" {
+		return &FunctionNotReadyError{Function: c.functionARN, Qualifier: qualifier, Reason: reason}
+	}
+
+	if c.healthCheck.DryRun {
+		output, err := c.invokeResolved(ctx, &lambda.InvokeInput{
+			InvocationType: types.InvocationTypeDryRun,
+			Qualifier:      pointer.ToStringOrNil(qualifier),
+		}, c.apiOptions())
+		if err != nil {
+			return fmt.Errorf("health check: %w", invokeError(c.accessDeniedError(err)))
+		}
+
+		if output.StatusCode != http.StatusNoContent {
+			return fmt.Errorf("health check: %w", &ErrUnexpectedStatus{Code: int(output.StatusCode), Body: string(output.Payload)})
+		}
+	}
+
+	if c.healthCheck.Ping.HTTPMethod != "" {
+		if _, err := c.Do(ctx, c.healthCheck.Ping); err != nil {
+			return fmt.Errorf("health check: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// HealthHandler serves HealthCheck of the clients for readiness probes: 200 if every check passes, 503 otherwise.
+// Failures are logged with the WithLogger logger of the client, slog.Default otherwise, and are not exposed
+// to the probe, as they may carry ARNs, account IDs and payloads.
+func HealthHandler(clients ...Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, cli := range clients {
+			if err := cli.HealthCheck(r.Context()); err != nil {
+				logHealthCheckError(r.Context(), cli, err)
+				writeJSONMessage(w, http.StatusServiceUnavailable, "Service Unavailable")
+				return
+			}
+		}
+
+		writeJSONMessage(w, http.StatusOK, "ok")
+	})
+}
+
+func logHealthCheckError(ctx context.Context, cli Client, err error) {
+	logger := slog.Default()
+	if c, ok := cli.(*client); ok {
+		err = c.redactError(err)
+		if c.logger != nil {
+			logger = c.logger
+		}
+	}
+
+	logger.ErrorContext(ctx, "lambda health check failed", slog.Any("error", err))
+}
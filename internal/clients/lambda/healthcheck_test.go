@@ -0,0 +1,56 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_HealthCheck(t *testing.T) {
+	api := &preflightAPI{state: types.StateActive}
+	api.invoke = func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		if in.InvocationType == types.InvocationTypeDryRun {
+			return &lambda.InvokeOutput{StatusCode: http.StatusNoContent}, nil
+		}
+		return proxyOutput(in, http.StatusServiceUnavailable, "warming up"), nil
+	}
+
+	require.NoError(t, newClient(api, testFunctionARN).HealthCheck(_ctx))
+	assert.Empty(t, api.calls(), "the function is not invoked by default")
+
+	cli := newClient(api, testFunctionARN, WithHealthCheck(HealthCheckConfig{DryRun: true}))
+	require.NoError(t, cli.HealthCheck(_ctx))
+	assert.Equal(t, types.InvocationTypeDryRun, api.calls()[0].InvocationType)
+
+	cli = newClient(api, testFunctionARN, WithHealthCheck(HealthCheckConfig{Ping: Request{HTTPMethod: "GET", Path: "/health"}}))
+	var ues *ErrUnexpectedStatus
+	require.ErrorAs(t, cli.HealthCheck(_ctx), &ues)
+
+	api.state = types.StateFailed
+	require.ErrorIs(t, newClient(api, testFunctionARN).HealthCheck(_ctx), ErrFunctionNotReady)
+}
+
+func TestHealthHandler(t *testing.T) {
+	api := &preflightAPI{state: types.StateActive}
+	var logs bytes.Buffer
+	handler := HealthHandler(newClient(api, testFunctionARN, WithLogger(slog.New(slog.NewTextHandler(&logs, nil)))))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	api.state = types.StatePending
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"message":"Service Unavailable"}`, rec.Body.String())
+	assert.NotContains(t, rec.Body.String(), testFunctionARN)
+	assert.Contains(t, logs.String(), "is not ready")
+}
@@ -0,0 +1,28 @@
+package lambda
+
+import (
+	"context"
+	"time"
+)
+
+// OnBefore calls hook with every request before it is invoked, e.g. for auditing. Like WithMetrics, it is
+// installed as an interceptor at its position among WithInterceptors options, the request must not be modified.
+func OnBefore(hook func(ctx context.Context, req Request)) Option {
+	return WithInterceptors(func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		hook(ctx, *req)
+		return next(ctx, req)
+	})
+}
+
+// OnAfter calls hook with every request, its outcome and duration, e.g. for timing. The response may be nil on
+// errors. Like WithMetrics, it is installed as an interceptor at its position among WithInterceptors options.
+func OnAfter(hook func(ctx context.Context, req Request, resp *Response, err error, elapsed time.Duration)) Option {
+	return WithInterceptors(func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		start := time.Now()
+
+		resp, err := next(ctx, req)
+		hook(ctx, *req, resp, err, time.Since(start))
+
+		return resp, err
+	})
+}
@@ -0,0 +1,45 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOnBeforeOnAfter(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusNotFound, "missing"), nil
+	}}
+
+	var order []string
+	var after struct {
+		req     Request
+		resp    *Response
+		err     error
+		elapsed time.Duration
+	}
+
+	cli := newClient(api, testFunctionARN,
+		OnBefore(func(_ context.Context, req Request) {
+			order = append(order, "before "+req.HTTPMethod+" "+req.Path)
+		}),
+		OnAfter(func(_ context.Context, req Request, resp *Response, err error, elapsed time.Duration) {
+			order = append(order, "after")
+			after.req, after.resp, after.err, after.elapsed = req, resp, err, elapsed
+		}),
+	)
+
+	_, err := cli.Invoke(_ctx, "GET", "/orders/1", nil)
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"before GET /orders/1", "after"}, order)
+	assert.Equal(t, "/orders/1", after.req.Path)
+	require.NotNil(t, after.resp)
+	assert.Equal(t, http.StatusNotFound, after.resp.StatusCode)
+	assert.Equal(t, ErrorKindBadStatus, KindOf(after.err))
+	assert.Positive(t, after.elapsed)
+}
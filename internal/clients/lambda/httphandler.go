@@ -0,0 +1,213 @@
+package lambda
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// HTTPHandlerConfig configures NewHTTPHandler.
+type HTTPHandlerConfig struct {
+	// StripPrefix is removed from request paths before invoking, e.g. "/api" maps "/api/orders" to "/orders".
+	// Paths not below it, e.g. "/apiary", are passed as is.
+	StripPrefix string
+	// MaxBodySize limits request bodies, larger ones get 413 Request Entity Too Large. 6 MB by default.
+	MaxBodySize int64
+	// Authorizer authorizes requests before invoking as an API Gateway Lambda authorizer does, if not nil.
+	// Its context populates RequestContext.Authorizer, denied requests get 403 Forbidden, failing authorizers
+	// 500 Internal Server Error, without invoking the function.
+	Authorizer Authorizer
+	// OnError is called with failed invocations and authorizations, if not nil.
+	OnError func(r *http.Request, err error)
+}
+
+// NewHTTPHandler returns a handler converting incoming requests to proxy events, invoking the function with Do
+// and writing its response back, like a local API Gateway. Every proxy response status is written as is,
+// failed invocations get 502 Bad Gateway, 429 Too Many Requests if throttled or 504 Gateway Timeout.
+func NewHTTPHandler(client Client, cfg HTTPHandlerConfig) http.Handler {
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = MaxSyncPayloadSize
+	}
+
+	return &httpHandler{client: client, cfg: cfg}
+}
+
+type httpHandler struct {
+	client Client
+	cfg    HTTPHandlerConfig
+}
+
+func (h *httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := newHTTPRequest(r, h.cfg.StripPrefix, h.cfg.MaxBodySize)
+	if errors.Is(err, errBodyTooLarge) {
+		writeJSONMessage(w, http.StatusRequestEntityTooLarge, "Request Entity Too Large")
+		return
+	}
+	if err != nil {
+		if h.cfg.OnError != nil {
+			h.cfg.OnError(r, err)
+		}
+		writeJSONMessage(w, http.StatusBadRequest, "Bad Request")
+		return
+	}
+
+	var opts []Option
+	if h.cfg.Authorizer != nil {
+		authorizerContext, err := h.cfg.Authorizer.Authorize(r.Context(), req)
+		if err != nil {
+			if h.cfg.OnError != nil {
+				h.cfg.OnError(r, err)
+			}
+			writeAuthorizerError(w, err)
+			return
+		}
+		opts = append(opts, WithAuthorizerContext(authorizerContext))
+	}
+
+	resp, err := h.client.Do(r.Context(), req, opts...)
+
+	if err != nil && !responded(resp, err) {
+		if h.cfg.OnError != nil {
+			h.cfg.OnError(r, err)
+		}
+		writeInvocationError(w, err)
+		return
+	}
+
+	if err := writeHTTPResponse(w, resp); err != nil && h.cfg.OnError != nil {
+		h.cfg.OnError(r, err)
+	}
+}
+
+// responded reports whether err is an unexpected proxy response status, which is passed through to HTTP callers.
+func responded(resp *Response, err error) bool {
+	var use *ErrUnexpectedStatus
+	return resp != nil && resp.StatusCode != 0 && errors.As(err, &use)
+}
+
+var errBodyTooLarge = errors.New("body too large")
+
+// newHTTPRequest converts r to a Request, bodies of binary content types are base64 encoded, CancelHeader and
+// ClaimCheckHeader are dropped.
+func newHTTPRequest(r *http.Request, stripPrefix string, maxBodySize int64) (Request, error) {
+	path := r.URL.Path
+	if prefix := strings.TrimSuffix(stripPrefix, "/"); prefix != "" && hasPathPrefix(path, prefix) {
+		path = "/" + strings.TrimLeft(strings.TrimPrefix(path, prefix), "/")
+	}
+
+	req := Request{
+		HTTPMethod: r.Method,
+		Path:       path,
+		Query:      r.URL.Query(),
+		Headers:    make(map[string]string, len(r.Header)+1),
+	}
+
+	for name, values := range r.Header {
+		// cancel events are published by WithCancelEvents only, HTTP callers must not forge them
+		if strings.EqualFold(name, CancelHeader) {
+			continue
+		}
+		// claim checks are set by WithClaimCheck only, forged ones would make the function read foreign objects
+		if strings.EqualFold(name, ClaimCheckHeader) {
+			continue
+		}
+		if len(values) > 1 {
+			if req.MultiValueHeaders == nil {
+				req.MultiValueHeaders = make(map[string][]string)
+			}
+			req.MultiValueHeaders[name] = values
+		}
+		if len(values) > 0 {
+			req.Headers[name] = values[len(values)-1]
+		}
+	}
+	if r.Host != "" {
+		req.Headers["Host"] = r.Host
+	}
+
+	if r.Body == nil {
+		return req, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+	if err != nil {
+		return req, fmt.Errorf("io.ReadAll: %w", err)
+	}
+	if int64(len(body)) > maxBodySize {
+		return req, fmt.Errorf("%w: exceeds %d bytes", errBodyTooLarge, maxBodySize)
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if len(body) > 0 && !isTextContentType(contentType) && (contentType != "" || !utf8.Valid(body)) {
+		req.Body = []byte(base64.StdEncoding.EncodeToString(body))
+		req.IsBase64Encoded = true
+		return req, nil
+	}
+
+	req.Body = body
+
+	return req, nil
+}
+
+// writeHTTPResponse writes the proxy response, base64 encoded bodies are decoded.
+func writeHTTPResponse(w http.ResponseWriter, resp *Response) error {
+	body, err := resp.Bytes()
+	if err != nil {
+		writeJSONMessage(w, http.StatusBadGateway, "Internal server error")
+		return fmt.Errorf("resp.Bytes: %w", err)
+	}
+
+	for name, values := range resp.HTTPHeader() {
+		w.Header()[name] = values
+	}
+
+	statusCode := resp.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("w.Write: %w", err)
+	}
+
+	return nil
+}
+
+func writeInvocationError(w http.ResponseWriter, err error) {
+	switch KindOf(err) {
+	case ErrorKindThrottled:
+		writeJSONMessage(w, http.StatusTooManyRequests, "Too Many Requests")
+	case ErrorKindContext, ErrorKindAbandoned:
+		writeJSONMessage(w, http.StatusGatewayTimeout, "Endpoint request timed out")
+	case ErrorKindPayloadTooLarge:
+		writeJSONMessage(w, http.StatusRequestEntityTooLarge, "Request Entity Too Large")
+	default:
+		writeJSONMessage(w, http.StatusBadGateway, "Internal server error")
+	}
+}
+
+func writeAuthorizerError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrUnauthorized) {
+		writeJSONMessage(w, http.StatusForbidden, "User is not authorized to access this resource")
+		return
+	}
+
+	writeJSONMessage(w, http.StatusInternalServerError, "Internal server error")
+}
+
+// writeJSONMessage writes an API Gateway style error body.
+func writeJSONMessage(w http.ResponseWriter, statusCode int, message string) {
+	body, _ := json.Marshal(struct {
+		Message string `json:"message"`
+	}{message})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,179 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHTTPHandler(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		req := proxyRequest(in)
+
+		payload, err := json.Marshal(events.APIGatewayProxyResponse{
+			StatusCode:      http.StatusCreated,
+			Headers:         map[string]string{"Content-Type": "application/octet-stream", "Location": req.Path},
+			Body:            base64.StdEncoding.EncodeToString([]byte{0xff, 0x00}),
+			IsBase64Encoded: true,
+		})
+		require.NoError(t, err)
+
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, nil
+	}}
+
+	handler := NewHTTPHandler(newClient(api, testFunctionARN), HTTPHandlerConfig{StripPrefix: "/api/"})
+
+	r := httptest.NewRequest(http.MethodPost, "http://orders.local/api/orders?tag=a&tag=b&page=2", strings.NewReader(`{"id":1}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Add("Accept", "application/json")
+	r.Header.Add("Accept", "text/plain")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusCreated, w.Code, "every proxy status is written as is")
+	assert.Equal(t, "/orders", w.Header().Get("Location"))
+	assert.Equal(t, []byte{0xff, 0x00}, w.Body.Bytes())
+
+	event := proxyRequest(api.calls()[0])
+	assert.Equal(t, http.MethodPost, event.HTTPMethod)
+	assert.Equal(t, "/orders", event.Path)
+	assert.Equal(t, `{"id":1}`, event.Body)
+	assert.False(t, event.IsBase64Encoded)
+	assert.Equal(t, "orders.local", event.Headers["Host"])
+	assert.Equal(t, "text/plain", event.Headers["Accept"])
+	assert.Equal(t, []string{"application/json", "text/plain"}, event.MultiValueHeaders["Accept"])
+	assert.Equal(t, []string{"application/json"}, event.MultiValueHeaders["Content-Type"])
+	assert.Equal(t, map[string]string{"tag": "b", "page": "2"}, event.QueryStringParameters)
+	assert.Equal(t, map[string][]string{"tag": {"a", "b"}, "page": {"2"}}, event.MultiValueQueryStringParameters)
+}
+
+func TestHTTPHandler_BinaryBody(t *testing.T) {
+	api := &fakeAPI{}
+	handler := NewHTTPHandler(newClient(api, testFunctionARN), HTTPHandlerConfig{})
+
+	r := httptest.NewRequest(http.MethodPut, "/images/1", strings.NewReader("\x89PNG"))
+	r.Header.Set("Content-Type", "image/png")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+
+	event := proxyRequest(api.calls()[0])
+	assert.True(t, event.IsBase64Encoded)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("\x89PNG")), event.Body)
+}
+
+func TestHTTPHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		body       string
+		wantStatus int
+	}{
+		{name: "transport", err: errors.New("connection reset"), wantStatus: http.StatusBadGateway},
+		{name: "throttled", err: &types.TooManyRequestsException{}, wantStatus: http.StatusTooManyRequests},
+		{name: "timeout", err: context.DeadlineExceeded, wantStatus: http.StatusGatewayTimeout},
+		{name: "too large", body: strings.Repeat("x", 11), wantStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+				return nil, tt.err
+			}}
+
+			var onError error
+			handler := NewHTTPHandler(newClient(api, testFunctionARN), HTTPHandlerConfig{
+				MaxBodySize: 10,
+				OnError:     func(_ *http.Request, err error) { onError = err },
+			})
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			if tt.err != nil {
+				assert.ErrorIs(t, onError, tt.err)
+			}
+		})
+	}
+}
+
+func TestNewHTTPRequest_StripPrefix(t *testing.T) {
+	for prefix, want := range map[string]string{"": "/api/orders", "/api": "/orders", "/api/": "/orders", "/api/orders": "/", "/ap": "/api/orders", "/": "/api/orders"} {
+		r := &http.Request{Method: http.MethodGet, URL: &url.URL{Path: "/api/orders"}, Header: http.Header{}}
+
+		req, err := newHTTPRequest(r, prefix, MaxSyncPayloadSize)
+		require.NoError(t, err)
+		assert.Equal(t, want, req.Path, prefix)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestHTTPHandler_BadBody(t *testing.T) {
+	api := &fakeAPI{}
+	handler := NewHTTPHandler(newClient(api, testFunctionARN), HTTPHandlerConfig{})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", failingReader{}))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"message":"Bad Request"}`, w.Body.String())
+	assert.Empty(t, api.calls())
+}
+
+func TestWriteJSONMessage(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeJSONMessage(w, http.StatusBadGateway, "bad \u00e9 \"gateway\"\x7f")
+
+	var body struct{ Message string }
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "bad \u00e9 \"gateway\"\x7f", body.Message)
+}
+
+func TestHTTPHandler_Authorizer(t *testing.T) {
+	api := &fakeAPI{}
+	authorizer := AuthorizerFunc(func(_ context.Context, req Request) (map[string]any, error) {
+		switch req.Headers["Authorization"] {
+		case "Bearer valid":
+			return map[string]any{"principalId": "user-1"}, nil
+		case "Bearer broken":
+			return nil, errors.New("authorizer crashed")
+		default:
+			return nil, ErrUnauthorized
+		}
+	})
+	handler := NewHTTPHandler(newClient(api, testFunctionARN), HTTPHandlerConfig{Authorizer: authorizer})
+
+	for token, want := range map[string]int{"valid": http.StatusOK, "invalid": http.StatusForbidden, "broken": http.StatusInternalServerError} {
+		r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		assert.Equal(t, want, w.Code, token)
+	}
+
+	require.Len(t, api.calls(), 1, "denied requests do not reach the function")
+	assert.Equal(t, map[string]any{"principalId": "user-1"}, proxyRequest(api.calls()[0]).RequestContext.Authorizer)
+}
@@ -0,0 +1,133 @@
+package lambda
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader is the default proxy request header carrying the idempotency key.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyStore remembers keys of submitted async requests, implement it to share keys between processes.
+type IdempotencyStore interface {
+	// Claim reports whether key was not claimed within the window and claims it.
+	Claim(key string, window time.Duration) bool
+	// Release forgets key, so that a failed submission can be retried.
+	Release(key string)
+}
+
+// Idempotency injects idempotency keys into proxy requests, the function is expected to deduplicate by them.
+// Keys are passed per call with WithIdempotencyKey or generated.
+type Idempotency struct {
+	// Header is IdempotencyKeyHeader by default.
+	Header string
+	// KeyFromContent generates keys from function, method, path and body instead of random UUIDs,
+	// so that resubmissions of the same request get the same key.
+	KeyFromContent bool
+	// Window suppresses async requests whose key was submitted within it, zero disables suppression.
+	// Suppressed requests succeed without invoking the function.
+	Window time.Duration
+	// Store is in-memory by default.
+	Store IdempotencyStore
+}
+
+// WithIdempotency injects idempotency keys, it is installed as an interceptor at its position among WithInterceptors options.
+func WithIdempotency(idem Idempotency) Option {
+	if idem.Header == "" {
+		idem.Header = IdempotencyKeyHeader
+	}
+	if idem.Store == nil {
+		idem.Store = newMemoryIdempotencyStore()
+	}
+
+	return func(c *client) {
+		c.interceptors = append(c.interceptors, c.idempotencyInterceptor(idem))
+	}
+}
+
+// WithIdempotencyKey sets the idempotency key of a call, it is meant to be passed per call.
+func WithIdempotencyKey(key string) Option {
+	return func(c *client) {
+		c.call.idempotencyKey = key
+	}
+}
+
+func (c *client) idempotencyInterceptor(idem Idempotency) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		key := c.callConfig(ctx).idempotencyKey
+		if key == "" {
+			if idem.KeyFromContent {
+				key = c.cacheKey(c.callConfig(ctx), req)
+			} else {
+				key = c.newUUID()
+			}
+		}
+
+		req.Headers = withHeader(req.Headers, idem.Header, key)
+
+		if !req.Async || idem.Window <= 0 {
+			return next(ctx, req)
+		}
+
+		if !idem.Store.Claim(key, idem.Window) {
+			return &Response{}, nil
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			idem.Store.Release(key)
+		}
+
+		return resp, err
+	}
+}
+
+// minIdempotencySweep is the number of claims below which expired ones are not swept.
+const minIdempotencySweep = 1024
+
+// memoryIdempotencyStore expires the claimed key lazily and sweeps all expired keys once the number of claims
+// doubles since the last sweep, so that Claim is amortized O(1).
+type memoryIdempotencyStore struct {
+	mu        sync.Mutex
+	claims    map[string]time.Time
+	nextSweep int
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{claims: make(map[string]time.Time), nextSweep: minIdempotencySweep}
+}
+
+func (s *memoryIdempotencyStore) Claim(key string, window time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := s.claims[key]; ok && !now.After(expiresAt) {
+		return false
+	}
+	s.claims[key] = now.Add(window)
+
+	if len(s.claims) >= s.nextSweep {
+		s.sweep(now)
+	}
+
+	return true
+}
+
+func (s *memoryIdempotencyStore) sweep(now time.Time) {
+	for k, expiresAt := range s.claims {
+		if now.After(expiresAt) {
+			delete(s.claims, k)
+		}
+	}
+
+	s.nextSweep = max(2*len(s.claims), minIdempotencySweep)
+}
+
+func (s *memoryIdempotencyStore) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.claims, key)
+}
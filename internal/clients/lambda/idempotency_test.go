@@ -0,0 +1,72 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestWithIdempotency(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithIdempotency(Idempotency{}))
+
+	_, err := cli.Invoke(_ctx, "POST", "/orders", nil)
+	require.NoError(t, err)
+	_, err = cli.Invoke(_ctx, "POST", "/orders", nil)
+	require.NoError(t, err)
+	_, err = cli.Invoke(_ctx, "POST", "/orders", nil, WithIdempotencyKey("order-42"))
+	require.NoError(t, err)
+
+	calls := api.calls()
+	first := proxyRequest(calls[0]).Headers[IdempotencyKeyHeader]
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, proxyRequest(calls[1]).Headers[IdempotencyKeyHeader])
+	assert.Equal(t, "order-42", proxyRequest(calls[2]).Headers[IdempotencyKeyHeader])
+}
+
+func TestWithIdempotency_Window(t *testing.T) {
+	var fail bool
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		if fail {
+			return nil, errors.New("connection reset")
+		}
+		return proxyOutput(in, 200, ""), nil
+	}}
+	cli := newClient(api, testFunctionARN, WithIdempotency(Idempotency{KeyFromContent: true, Window: time.Minute}))
+
+	require.NoError(t, cli.InvokeAsync(_ctx, "POST", "/orders", []byte(`{"id":1}`)))
+	require.NoError(t, cli.InvokeAsync(_ctx, "POST", "/orders", []byte(`{"id":1}`)))
+	assert.Len(t, api.calls(), 1, "duplicate is suppressed")
+
+	require.NoError(t, cli.InvokeAsync(_ctx, "POST", "/orders", []byte(`{"id":2}`)))
+	assert.Len(t, api.calls(), 2)
+
+	fail = true
+	require.Error(t, cli.InvokeAsync(_ctx, "POST", "/orders", []byte(`{"id":3}`)))
+	fail = false
+	require.NoError(t, cli.InvokeAsync(_ctx, "POST", "/orders", []byte(`{"id":3}`)))
+	assert.Len(t, api.calls(), 4, "failed submissions are not remembered")
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	s := newMemoryIdempotencyStore()
+
+	assert.True(t, s.Claim("order-1", time.Minute))
+	assert.False(t, s.Claim("order-1", time.Minute))
+
+	assert.True(t, s.Claim("order-2", -time.Second))
+	assert.True(t, s.Claim("order-2", time.Minute), "the expired claim is replaced")
+
+	for i := range minIdempotencySweep - 2 {
+		s.Claim(fmt.Sprintf("expired-%d", i), -time.Second)
+	}
+
+	assert.Len(t, s.claims, 2, "expired claims are swept once the threshold is reached")
+	assert.Equal(t, minIdempotencySweep, s.nextSweep)
+	assert.False(t, s.Claim("order-1", time.Minute))
+}
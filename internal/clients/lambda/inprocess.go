@@ -0,0 +1,104 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	lambdahandler "github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"net/http"
+	"reflect"
+)
+
+// InProcessARN is the function ARN of clients returned by NewInProcess.
+const InProcessARN = "arn:aws:lambda:eu-central-1:000000000000:function:in-process"
+
+// NewInProcess returns a client dispatching invocations to an aws-lambda-go handler on the invoking goroutine
+// instead of calling AWS, for fast tests of real handler code. The handler may have any signature accepted by
+// lambda.Start, e.g. func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error).
+// Events are built and responses unwrapped as for real invocations, handler errors and panics are function errors
+// and async invocations run to completion before InvokeAsync returns.
+func NewInProcess(handler any, opts ...Option) Client {
+	api := &inProcessAPI{handler: lambdahandler.NewHandler(handler), rand: newRandomRand()}
+
+	return newClient(api, InProcessARN, opts...)
+}
+
+// inProcessAPI is a lambdaAPI invoking the handler with lambdacontext set, like the Go runtime does.
+type inProcessAPI struct {
+	handler lambdahandler.Handler
+	rand    *Rand
+}
+
+func (a *inProcessAPI) Invoke(ctx context.Context, in *lambda.InvokeInput, _ ...func(*lambda.Options)) (*lambda.InvokeOutput, error) {
+	requestID := randomUUID(a.rand)
+
+	functionARN := InProcessARN
+	if in.Qualifier != nil {
+		functionARN += ":" + *in.Qualifier
+	}
+
+	ctx = lambdacontext.NewContext(ctx, &lambdacontext.LambdaContext{AwsRequestID: requestID, InvokedFunctionArn: functionARN})
+
+	output := &lambda.InvokeOutput{StatusCode: http.StatusOK, ExecutedVersion: pointer.To("$LATEST")}
+	awsmiddleware.SetRequestIDMetadata(&output.ResultMetadata, requestID)
+
+	payload, invokeErr := a.invoke(ctx, in.Payload)
+	if invokeErr != nil {
+		errPayload, err := json.Marshal(invokeErr)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal: %w", err)
+		}
+		payload = errPayload
+		output.FunctionError = pointer.To("Unhandled")
+	}
+
+	if in.InvocationType == types.InvocationTypeEvent {
+		output.StatusCode = http.StatusAccepted
+		output.FunctionError = nil
+		return output, nil
+	}
+
+	output.Payload = payload
+
+	return output, nil
+}
+
+// invoke runs the handler, errors and panics are returned in the error payload format of the Go runtime.
+func (a *inProcessAPI) invoke(ctx context.Context, payload []byte) (out []byte, invokeErr *messages.InvokeResponse_Error) {
+	defer func() {
+		if r := recover(); r != nil {
+			out, invokeErr = nil, &messages.InvokeResponse_Error{Message: fmt.Sprint(r), Type: errorTypeName(r)}
+		}
+	}()
+
+	out, err := a.handler.Invoke(ctx, payload)
+	if err != nil {
+		if ive, ok := err.(messages.InvokeResponse_Error); ok {
+			return nil, &ive
+		}
+		return nil, &messages.InvokeResponse_Error{Message: err.Error(), Type: errorTypeName(err)}
+	}
+
+	return out, nil
+}
+
+// GetFunction resolves every function to InProcessARN.
+func (a *inProcessAPI) GetFunction(context.Context, *lambda.GetFunctionInput, ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	return &lambda.GetFunctionOutput{Configuration: &types.FunctionConfiguration{FunctionArn: pointer.To(InProcessARN)}}, nil
+}
+
+// errorTypeName names the type of v without the pointer, as the Go runtime reports errorType.
+func errorTypeName(v any) string {
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		return t.Elem().Name()
+	}
+
+	return t.Name()
+}
@@ -0,0 +1,66 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type orderNotFoundError struct{ id string }
+
+func (e *orderNotFoundError) Error() string {
+	return "order not found: " + e.id
+}
+
+func TestNewInProcess(t *testing.T) {
+	var invokedARN string
+	handler := func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		lc, ok := lambdacontext.FromContext(ctx)
+		require.True(t, ok)
+		invokedARN = lc.InvokedFunctionArn
+
+		switch req.Path {
+		case "/orders/1":
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: `{"id":"1","method":"` + req.HTTPMethod + `"}`}, nil
+		case "/panic":
+			panic("boom")
+		}
+		return events.APIGatewayProxyResponse{}, &orderNotFoundError{id: req.Path}
+	}
+
+	cli := NewInProcess(handler, WithQualifier("live"))
+
+	var out struct{ ID, Method string }
+	require.NoError(t, cli.Call(_ctx, "GET", "/orders/1", nil, &out))
+	assert.Equal(t, "1", out.ID)
+	assert.Equal(t, "GET", out.Method)
+	assert.Equal(t, InProcessARN+":live", invokedARN)
+
+	resp, err := cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/orders/2"})
+	var ie *InvocationError
+	require.ErrorAs(t, err, &ie)
+	assert.Equal(t, "orderNotFoundError", ie.Type)
+	assert.Equal(t, "order not found: /orders/2", ie.Message)
+	assert.NotEmpty(t, resp.RequestID)
+	assert.Equal(t, "$LATEST", resp.ExecutedVersion)
+
+	_, err = cli.Invoke(_ctx, "GET", "/panic", nil)
+	require.ErrorAs(t, err, &ie)
+	assert.Equal(t, "boom", ie.Message)
+	assert.Equal(t, ErrorKindFunction, KindOf(err))
+
+	require.NoError(t, cli.InvokeAsync(_ctx, "POST", "/orders/2", nil), "async errors are not returned")
+
+	arn, err := cli.FunctionARN(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, InProcessARN, arn)
+}
+
+func TestNewInProcess_InvalidHandler(t *testing.T) {
+	_, err := NewInProcess("not a handler").Invoke(_ctx, "GET", "/", nil)
+	require.ErrorIs(t, err, ErrFunctionError)
+}
@@ -0,0 +1,30 @@
+package lambda
+
+import (
+	"context"
+)
+
+// Invoker performs an invocation, it is the next step of an Interceptor chain.
+type Invoker func(ctx context.Context, req *Request) (*Response, error)
+
+// Interceptor wraps every invocation, it can inspect or modify the request before calling next
+// and the response or error after it, e.g. for logging, metrics, auth or retries.
+type Interceptor func(ctx context.Context, req *Request, next Invoker) (*Response, error)
+
+// WithInterceptors appends interceptors to the chain, the first one is the outermost.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+func chainInterceptors(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+		invoker = func(ctx context.Context, req *Request) (*Response, error) {
+			return interceptor(ctx, req, next)
+		}
+	}
+
+	return invoker
+}
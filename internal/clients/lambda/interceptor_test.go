@@ -0,0 +1,52 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestWithInterceptors(t *testing.T) {
+	var order []string
+
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+			order = append(order, name+":before")
+			resp, err := next(ctx, req)
+			order = append(order, name+":after")
+			return resp, err
+		}
+	}
+
+	auth := func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		req.Headers = map[string]string{"Authorization": "Bearer token"}
+		return next(ctx, req)
+	}
+
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithInterceptors(record("outer"), record("inner"), auth))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"outer:before", "inner:before", "inner:after", "outer:after"}, order)
+	assert.Equal(t, "Bearer token", proxyRequest(api.calls()[0]).Headers["Authorization"])
+}
+
+func TestWithInterceptors_ShortCircuit(t *testing.T) {
+	errDenied := errors.New("denied")
+
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithInterceptors(func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		if req.Async {
+			return nil, errDenied
+		}
+		return next(ctx, req)
+	}))
+
+	err := cli.InvokeAsync(_ctx, "POST", "/", nil)
+	assert.ErrorIs(t, err, errDenied)
+	assert.Empty(t, api.calls())
+}
@@ -0,0 +1,100 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// ObjectStreamer is optionally implemented by an ObjectStore to read offloaded bodies without buffering them,
+// see InvokeTo.
+type ObjectStreamer interface {
+	Open(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+}
+
+type streamBodyKey struct{}
+
+// InvokeTo synchronously invokes the function and writes the decoded response body to w. The Invoke API response
+// is buffered as a whole, InvokeWithResponseStream is not used. Only bodies offloaded by WithClaimCheck are copied
+// from the store without buffering if it implements ObjectStreamer, unless checksum verification or a JMESPath
+// expression needs the whole body. Responses are neither cached nor shared by WithSingleflight.
+func (c *client) InvokeTo(ctx context.Context, httpMethod, path string, body []byte, w io.Writer, opts ...Option) error {
+	ctx, err := c.withCallOptions(ctx, append(slices.Clip(opts), WithCacheTTL(0)))
+	if err != nil {
+		return fmt.Errorf("invoke[sync]: %w", err)
+	}
+
+	resp, err := c.do(context.WithValue(ctx, streamBodyKey{}, true), &Request{
+		HTTPMethod: httpMethod,
+		Path:       path,
+		Body:       body,
+	})
+	if err != nil {
+		return fmt.Errorf("invoke[sync]: %w", err)
+	}
+
+	if err := c.writeBody(ctx, resp, w); err != nil {
+		return fmt.Errorf("writeBody: %w", err)
+	}
+
+	return nil
+}
+
+// isStreamed reports that ctx is of an InvokeTo call.
+func isStreamed(ctx context.Context) bool {
+	stream, _ := ctx.Value(streamBodyKey{}).(bool)
+	return stream
+}
+
+// streamsBody reports that the claim-check fetch is left to InvokeTo.
+func (c *client) streamsBody(ctx context.Context) bool {
+	if !isStreamed(ctx) {
+		return false
+	}
+
+	if _, ok := c.claimCheck.Store.(ObjectStreamer); !ok {
+		return false
+	}
+
+	return !c.verifyChecksum && c.callConfig(ctx).jmesPath == ""
+}
+
+func (c *client) writeBody(ctx context.Context, resp *Response, w io.Writer) error {
+	location, ok := headerValue(resp.Headers, ClaimCheckHeader)
+	if !ok || c.claimCheck == nil {
+		body, err := resp.Bytes()
+		if err != nil {
+			return fmt.Errorf("resp.Bytes: %w", err)
+		}
+
+		if _, err := w.Write(body); err != nil {
+			return fmt.Errorf("w.Write: %w", err)
+		}
+
+		return nil
+	}
+
+	bucket, key, err := c.claimCheck.parseLocation(location)
+	if err != nil {
+		return err
+	}
+
+	rc, err := c.claimCheck.Store.(ObjectStreamer).Open(ctx, bucket, key)
+	if err != nil {
+		return fmt.Errorf("store.Open: %w", err)
+	}
+	defer rc.Close()
+
+	var r io.Reader = rc
+	if resp.IsBase64Encoded {
+		r = base64.NewDecoder(base64.StdEncoding, rc)
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		return fmt.Errorf("io.Copy: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// streamingStore counts Get and Open calls of a memoryStore.
+type streamingStore struct {
+	memoryStore
+	gets, opens int
+}
+
+func (s *streamingStore) Get(ctx context.Context, bucket, key string) ([]byte, error) {
+	s.gets++
+	return s.memoryStore.Get(ctx, bucket, key)
+}
+
+func (s *streamingStore) Open(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	s.opens++
+	body, err := s.memoryStore.Get(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+func TestInvokeTo(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		payload, err := json.Marshal(events.APIGatewayProxyResponse{
+			StatusCode:      http.StatusOK,
+			Body:            base64.StdEncoding.EncodeToString([]byte{0x00, 0xff}),
+			IsBase64Encoded: true,
+		})
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, err
+	}}
+	cli := newClient(api, testFunctionARN)
+
+	var buf bytes.Buffer
+	require.NoError(t, cli.InvokeTo(_ctx, "GET", "/file", nil, &buf))
+	assert.Equal(t, []byte{0x00, 0xff}, buf.Bytes())
+}
+
+func TestInvokeTo_ClaimCheck(t *testing.T) {
+	store := &streamingStore{}
+	cc := ClaimCheck{Store: store, Bucket: "payloads", Threshold: 1024}
+	large := strings.Repeat("x", 2048)
+
+	handler := ClaimCheckHandler(cc)(func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: large}, nil
+	})
+
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		resp, err := handler(ctx, proxyRequest(in))
+		require.NoError(t, err)
+
+		payload, err := json.Marshal(resp)
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, err
+	}}
+	cli := newClient(api, testFunctionARN, WithClaimCheck(cc), WithCache(Cache{TTL: time.Minute}))
+
+	var buf bytes.Buffer
+	require.NoError(t, cli.InvokeTo(_ctx, "GET", "/report", nil, &buf))
+	assert.Equal(t, large, buf.String())
+	assert.Equal(t, 0, store.gets)
+	assert.Equal(t, 1, store.opens)
+
+	body, err := cli.Invoke(_ctx, "GET", "/report", nil)
+	require.NoError(t, err)
+	assert.Equal(t, large, body)
+	assert.Equal(t, 1, store.gets)
+}
@@ -0,0 +1,56 @@
+package lambda
+
+import (
+	"context"
+	"iter"
+)
+
+// InvokeSeq synchronously invokes requests with the configured concurrency and yields their results
+// in completion order, Result.Index is the position of the Request in the sequence.
+// Breaking out of the loop cancels outstanding invocations and stops pulling requests.
+// Use slices.Values to invoke a batch.
+func (c *client) InvokeSeq(ctx context.Context, requests iter.Seq[Request]) iter.Seq[Result] {
+	return func(yield func(Result) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		in := make(chan Request)
+		go func() {
+			defer close(in)
+			for req := range requests {
+				select {
+				case <-ctx.Done():
+					return
+				case in <- req:
+				}
+			}
+		}()
+
+		for r := range c.InvokeStreaming(ctx, in) {
+			if !yield(r) {
+				return
+			}
+		}
+	}
+}
+
+// paginate yields the items of every page until hasMorePages reports none, or the error of nextPage and stops.
+// Breaking out of the loop stops fetching pages.
+func paginate[P, T any](hasMorePages func() bool, nextPage func() (P, error), items func(P) []T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for hasMorePages() {
+			page, err := nextPage()
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items(page) {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
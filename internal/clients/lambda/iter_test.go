@@ -0,0 +1,92 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"iter"
+	"slices"
+	"sync/atomic"
+	"testing"
+)
+
+func TestInvokeSeq(t *testing.T) {
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithConcurrency(3))
+
+	requests := []Request{{HTTPMethod: "GET", Path: "/a"}, {HTTPMethod: "GET", Path: "/b"}, {HTTPMethod: "GET", Path: "/c"}}
+
+	var indexes []int
+	for r := range cli.InvokeSeq(_ctx, slices.Values(requests)) {
+		assert.NoError(t, r.Err)
+		assert.Equal(t, "ok", r.Body)
+		indexes = append(indexes, r.Index)
+	}
+
+	slices.Sort(indexes)
+	assert.Equal(t, []int{0, 1, 2}, indexes)
+}
+
+func TestInvokeSeq_Break(t *testing.T) {
+	var invoked atomic.Int32
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		invoked.Add(1)
+		return proxyOutput(in, 200, "ok"), nil
+	}}
+	cli := newClient(api, testFunctionARN, WithConcurrency(1))
+
+	var pulled atomic.Int32
+	requests := func(yield func(Request) bool) {
+		for {
+			pulled.Add(1)
+			if !yield(Request{HTTPMethod: "GET", Path: "/"}) {
+				return
+			}
+		}
+	}
+
+	for range cli.InvokeSeq(_ctx, requests) {
+		break
+	}
+
+	// the infinite sequence is abandoned, only requests in flight at the break were pulled
+	assert.Less(t, pulled.Load(), int32(10))
+	assert.Less(t, invoked.Load(), int32(10))
+}
+
+func TestPaginate(t *testing.T) {
+	pages := [][]int{{1, 2}, {}, {3}}
+
+	var fetched int
+	hasMorePages := func() bool { return fetched < len(pages) }
+	nextPage := func() ([]int, error) {
+		fetched++
+		return pages[fetched-1], nil
+	}
+	items := func(page []int) []int { return page }
+
+	assert.Equal(t, []int{1, 2, 3}, slices.Collect(keys(paginate(hasMorePages, nextPage, items))))
+
+	fetched = 0
+	for item := range keys(paginate(hasMorePages, nextPage, items)) {
+		if item == 2 {
+			break
+		}
+	}
+	assert.Equal(t, 1, fetched, "breaking out of the loop stops fetching pages")
+
+	failing := paginate(func() bool { return true }, func() ([]int, error) { return nil, assert.AnError }, items)
+	for _, err := range failing {
+		assert.ErrorIs(t, err, assert.AnError)
+	}
+}
+
+// keys drops the errors of seq.
+func keys[K, V any](seq iter.Seq2[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package lambda
+
+import (
+	"fmt"
+	"github.com/jmespath/go-jmespath"
+)
+
+// WithJMESPath applies the JMESPath expression, e.g. "items[?status=='paid'].id", to the JSON response body
+// of sync invocations and replaces the body with the JSON encoded result, it is meant to be passed per call.
+// An invalid expression or a non-JSON body fail the call.
+func WithJMESPath(expression string) Option {
+	return func(c *client) {
+		c.call.jmesPath = expression
+	}
+}
+
+// extract replaces the response body with the result of the expression.
+func (c *client) extract(expression string, resp *Response) error {
+	query, err := jmespath.Compile(expression)
+	if err != nil {
+		return fmt.Errorf("jmespath.Compile: %w", err)
+	}
+
+	body, err := resp.Bytes()
+	if err != nil {
+		return fmt.Errorf("resp.Bytes: %w", err)
+	}
+
+	var data any
+	if err := c.json.Unmarshal(body, &data); err != nil {
+		return withKind(ErrorKindMarshal, fmt.Errorf("json.Unmarshal: %w", err))
+	}
+
+	result, err := query.Search(data)
+	if err != nil {
+		return fmt.Errorf("query.Search: %w", err)
+	}
+
+	extracted, err := c.json.Marshal(result)
+	if err != nil {
+		return withKind(ErrorKindMarshal, fmt.Errorf("json.Marshal: %w", err))
+	}
+
+	resp.Body = string(extracted)
+	resp.IsBase64Encoded = false
+	resp.Data = nil
+
+	return nil
+}
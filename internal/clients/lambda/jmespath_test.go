@@ -0,0 +1,44 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestWithJMESPath(t *testing.T) {
+	body := `{"items":[{"id":1,"status":"paid"},{"id":2,"status":"new"},{"id":3,"status":"paid"}],"total":3}`
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusOK, body), nil
+	}}
+	cli := newClient(api, testFunctionARN)
+
+	got, err := cli.Invoke(_ctx, "GET", "/orders", nil, WithJMESPath("items[?status=='paid'].id"))
+	require.NoError(t, err)
+	assert.Equal(t, `[1,3]`, got)
+
+	got, err = cli.Invoke(_ctx, "GET", "/orders", nil, WithJMESPath("total"))
+	require.NoError(t, err)
+	assert.Equal(t, `3`, got)
+
+	got, err = cli.Invoke(_ctx, "GET", "/orders", nil)
+	require.NoError(t, err)
+	assert.Equal(t, body, got, "per call only")
+
+	var ids []int
+	require.NoError(t, cli.Call(_ctx, "GET", "/orders", nil, &ids, WithJMESPath("items[].id")))
+	assert.Equal(t, []int{1, 2, 3}, ids)
+
+	_, err = cli.Invoke(_ctx, "GET", "/orders", nil, WithJMESPath("items[?"))
+	require.Error(t, err)
+}
+
+func TestWithJMESPath_NotJSON(t *testing.T) {
+	cli := newClient(&fakeAPI{}, testFunctionARN)
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil, WithJMESPath("id"))
+	assert.Equal(t, ErrorKindMarshal, KindOf(err))
+}
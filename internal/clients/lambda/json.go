@@ -0,0 +1,30 @@
+package lambda
+
+import (
+	"encoding/json"
+)
+
+// JSONEngine marshals proxy envelopes, it dominates client-side CPU for large payloads.
+type JSONEngine interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// StdJSON is the encoding/json engine, it is the default unless built with the gojson tag.
+type StdJSON struct{}
+
+func (StdJSON) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (StdJSON) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// WithJSONEngine sets the engine for proxy envelopes, StdJSON by default, GoJSON with the gojson build tag.
+// Route codecs used by Call are not affected.
+func WithJSONEngine(engine JSONEngine) Option {
+	return func(c *client) {
+		c.json = engine
+	}
+}
@@ -0,0 +1,21 @@
+//go:build gojson
+
+package lambda
+
+import (
+	gojson "github.com/goccy/go-json"
+)
+
+// defaultJSON is GoJSON in builds with the gojson tag.
+var defaultJSON JSONEngine = GoJSON{}
+
+// GoJSON is the github.com/goccy/go-json engine, it is only built with the gojson tag and is the default then.
+type GoJSON struct{}
+
+func (GoJSON) Marshal(v any) ([]byte, error) {
+	return gojson.Marshal(v)
+}
+
+func (GoJSON) Unmarshal(data []byte, v any) error {
+	return gojson.Unmarshal(data, v)
+}
@@ -0,0 +1,18 @@
+//go:build gojson
+
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestGoJSON(t *testing.T) {
+	cli := newClient(&fakeAPI{}, testFunctionARN)
+	assert.Equal(t, GoJSON{}, cli.json)
+
+	body, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", body)
+}
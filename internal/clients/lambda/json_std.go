@@ -0,0 +1,6 @@
+//go:build !gojson
+
+package lambda
+
+// defaultJSON is StdJSON unless built with the gojson tag, see GoJSON.
+var defaultJSON JSONEngine = StdJSON{}
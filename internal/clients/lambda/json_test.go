@@ -0,0 +1,34 @@
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type countingJSON struct {
+	StdJSON
+	marshals, unmarshals int
+}
+
+func (c *countingJSON) Marshal(v any) ([]byte, error) {
+	c.marshals++
+	return c.StdJSON.Marshal(v)
+}
+
+func (c *countingJSON) Unmarshal(data []byte, v any) error {
+	c.unmarshals++
+	return c.StdJSON.Unmarshal(data, v)
+}
+
+func TestWithJSONEngine(t *testing.T) {
+	engine := &countingJSON{}
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithJSONEngine(engine))
+
+	body, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "ok", body)
+	assert.Equal(t, 1, engine.marshals)
+	assert.Equal(t, 1, engine.unmarshals)
+}
@@ -0,0 +1,153 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrorKind classifies invocation failures, use KindOf instead of matching error strings.
+type ErrorKind int
+
+const (
+	ErrorKindNone ErrorKind = iota
+	// ErrorKindUnknown is an error which does not fall into other kinds.
+	ErrorKindUnknown
+	// ErrorKindMarshal is a failure to marshal the request or unmarshal the response envelope.
+	ErrorKindMarshal
+	// ErrorKindTransport is a failure to call the Invoke API.
+	ErrorKindTransport
+	// ErrorKindThrottled is a rejection of the Invoke API call due to the concurrency or request rate limits.
+	ErrorKindThrottled
+	// ErrorKindFunction is an error returned by the function handler or runtime.
+	ErrorKindFunction
+	// ErrorKindBadStatus is an unexpected Invoke API or proxy response status code.
+	ErrorKindBadStatus
+	// ErrorKindPanic is a panic recovered on the invoke path, see PanicError.
+	ErrorKindPanic
+	// ErrorKindUnauthorized is a request denied by the authorizer.
+	ErrorKindUnauthorized
+	// ErrorKindChecksum is a response body checksum mismatch.
+	ErrorKindChecksum
+	// ErrorKindAbandoned is a sync invocation abandoned by soft-cancel.
+	ErrorKindAbandoned
+	// ErrorKindContext is a canceled or expired context.
+	ErrorKindContext
+	// ErrorKindPayloadTooLarge is a payload exceeding the invoke limit, see PayloadTooLargeError.
+	ErrorKindPayloadTooLarge
+)
+
+var errorKindNames = [...]string{
+	ErrorKindNone:            "none",
+	ErrorKindUnknown:         "unknown",
+	ErrorKindMarshal:         "marshal",
+	ErrorKindTransport:       "transport",
+	ErrorKindThrottled:       "throttled",
+	ErrorKindFunction:        "function",
+	ErrorKindBadStatus:       "bad_status",
+	ErrorKindPanic:           "panic",
+	ErrorKindUnauthorized:    "unauthorized",
+	ErrorKindChecksum:        "checksum",
+	ErrorKindAbandoned:       "abandoned",
+	ErrorKindContext:         "context",
+	ErrorKindPayloadTooLarge: "payload_too_large",
+}
+
+func (k ErrorKind) String() string {
+	if k < 0 || int(k) >= len(errorKindNames) {
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+
+	return errorKindNames[k]
+}
+
+func (k ErrorKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *ErrorKind) UnmarshalText(text []byte) error {
+	for kind, name := range errorKindNames {
+		if name == string(text) {
+			*k = ErrorKind(kind)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown error kind: %s", text)
+}
+
+// KindOf classifies err, it returns ErrorKindNone for nil.
+func KindOf(err error) ErrorKind {
+	var pe *PanicError
+	var ke *kindError
+	var use *ErrUnexpectedStatus
+
+	switch {
+	case err == nil:
+		return ErrorKindNone
+	case errors.As(err, &pe):
+		return ErrorKindPanic
+	case errors.Is(err, ErrAbandoned):
+		return ErrorKindAbandoned
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ErrorKindContext
+	case errors.Is(err, ErrPayloadTooLarge):
+		return ErrorKindPayloadTooLarge
+	case errors.Is(err, ErrThrottled):
+		return ErrorKindThrottled
+	case errors.Is(err, ErrFunctionError):
+		return ErrorKindFunction
+	case errors.As(err, &use):
+		return ErrorKindBadStatus
+	case errors.As(err, &ke):
+		return ke.kind
+	case errors.Is(err, ErrUnauthorized):
+		return ErrorKindUnauthorized
+	case errors.Is(err, ErrChecksumMismatch):
+		return ErrorKindChecksum
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+// kindError tags err with its kind without changing the message.
+type kindError struct {
+	kind ErrorKind
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
+
+func withKind(kind ErrorKind, err error) error {
+	return &kindError{kind: kind, err: err}
+}
+
+// PanicError is returned instead of a panic raised on the invoke path, e.g. by an interceptor.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// recoverInvoker converts panics of next into PanicError.
+func recoverInvoker(next Invoker) Invoker {
+	return func(ctx context.Context, req *Request) (resp *Response, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				resp, err = nil, &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,107 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		invoke func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error)
+		want   ErrorKind
+	}{
+		{
+			name: "ok",
+			want: ErrorKindNone,
+		},
+		{
+			name: "transport",
+			invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+				return nil, errors.New("connection reset")
+			},
+			want: ErrorKindTransport,
+		},
+		{
+			name: "throttled",
+			invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+				return nil, fmt.Errorf("operation error: %w", &types.TooManyRequestsException{})
+			},
+			want: ErrorKindThrottled,
+		},
+		{
+			name: "function",
+			invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+				return &lambda.InvokeOutput{StatusCode: http.StatusOK, FunctionError: pointer.To("Unhandled")}, nil
+			},
+			want: ErrorKindFunction,
+		},
+		{
+			name: "bad status",
+			invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+				return proxyOutput(in, http.StatusInternalServerError, "oops"), nil
+			},
+			want: ErrorKindBadStatus,
+		},
+		{
+			name: "marshal",
+			invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+				return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: []byte("not json")}, nil
+			},
+			want: ErrorKindMarshal,
+		},
+		{
+			name: "context",
+			invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+				return nil, fmt.Errorf("operation error: %w", context.DeadlineExceeded)
+			},
+			want: ErrorKindContext,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := newClient(&fakeAPI{invoke: tt.invoke}, testFunctionARN)
+
+			_, err := cli.Invoke(_ctx, "GET", "/", nil)
+
+			assert.Equal(t, tt.want, KindOf(err))
+		})
+	}
+}
+
+func TestRecoverInvoker(t *testing.T) {
+	panicking := func(context.Context, *Request, Invoker) (*Response, error) {
+		panic("boom")
+	}
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithInterceptors(panicking))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.Error(t, err)
+
+	var pe *PanicError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, "boom", pe.Value)
+	assert.NotEmpty(t, pe.Stack)
+	assert.Equal(t, ErrorKindPanic, KindOf(err))
+}
+
+func TestErrorKind_Text(t *testing.T) {
+	text, err := ErrorKindThrottled.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "throttled", string(text))
+
+	var k ErrorKind
+	require.NoError(t, k.UnmarshalText(text))
+	assert.Equal(t, ErrorKindThrottled, k)
+
+	require.Error(t, k.UnmarshalText([]byte("nope")))
+}
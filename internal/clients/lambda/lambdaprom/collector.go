@@ -0,0 +1,109 @@
+// Package lambdaprom exports invocation metrics of lambda clients to Prometheus.
+package lambdaprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"lambda-invoker/internal/clients/lambda"
+	"strconv"
+)
+
+var payloadBuckets = prometheus.ExponentialBuckets(256, 4, 9) // 256B .. 16MB
+
+// Collector is a lambda.MetricsRecorder and a prometheus.Collector,
+// pass it to lambda.WithMetrics and register it with a prometheus.Registerer.
+type Collector struct {
+	invocations   *prometheus.CounterVec
+	errors        *prometheus.CounterVec
+	throttles     *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	requestBytes  *prometheus.HistogramVec
+	responseBytes *prometheus.HistogramVec
+}
+
+var _ lambda.MetricsRecorder = (*Collector)(nil)
+var _ lambda.ThrottleRecorder = (*Collector)(nil)
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector returns a collector with metrics prefixed by the namespace, e.g. "myservice".
+func NewCollector(namespace string) *Collector {
+	labels := []string{"function_arn", "async"}
+
+	return &Collector{
+		invocations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "lambda",
+			Name:      "invocations_total",
+			Help:      "Number of Lambda invocations.",
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "lambda",
+			Name:      "invocation_errors_total",
+			Help:      "Number of failed Lambda invocations by error class.",
+		}, append(labels, "class")),
+		throttles: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "lambda",
+			Name:      "throttled_attempts_total",
+			Help:      "Number of throttled Lambda API attempts retried by the SDK.",
+		}, []string{"function_arn"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "lambda",
+			Name:      "invocation_duration_seconds",
+			Help:      "Latency of Lambda invocations as seen by the caller.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		requestBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "lambda",
+			Name:      "request_body_bytes",
+			Help:      "Size of Lambda invocation request bodies.",
+			Buckets:   payloadBuckets,
+		}, labels),
+		responseBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "lambda",
+			Name:      "response_body_bytes",
+			Help:      "Size of Lambda invocation response bodies.",
+			Buckets:   payloadBuckets,
+		}, labels),
+	}
+}
+
+func (c *Collector) RecordInvocation(m lambda.InvocationMetrics) {
+	functionARN, async := m.FunctionARN, strconv.FormatBool(m.Async)
+
+	c.invocations.WithLabelValues(functionARN, async).Inc()
+	c.duration.WithLabelValues(functionARN, async).Observe(m.Duration.Seconds())
+	c.requestBytes.WithLabelValues(functionARN, async).Observe(float64(m.RequestBytes))
+
+	if m.ErrorClass != "" {
+		c.errors.WithLabelValues(functionARN, async, m.ErrorClass).Inc()
+		return
+	}
+
+	c.responseBytes.WithLabelValues(functionARN, async).Observe(float64(m.ResponseBytes))
+}
+
+func (c *Collector) RecordThrottle(m lambda.ThrottleMetrics) {
+	c.throttles.WithLabelValues(m.FunctionARN).Inc()
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.invocations.Describe(ch)
+	c.errors.Describe(ch)
+	c.throttles.Describe(ch)
+	c.duration.Describe(ch)
+	c.requestBytes.Describe(ch)
+	c.responseBytes.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.invocations.Collect(ch)
+	c.errors.Collect(ch)
+	c.throttles.Collect(ch)
+	c.duration.Collect(ch)
+	c.requestBytes.Collect(ch)
+	c.responseBytes.Collect(ch)
+}
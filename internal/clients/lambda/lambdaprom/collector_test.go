@@ -0,0 +1,47 @@
+package lambdaprom
+
+import (
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"lambda-invoker/internal/clients/lambda"
+	"strings"
+	"testing"
+	"time"
+)
+
+const functionARN = "arn:aws:lambda:eu-central-1:000000000000:function:my-function"
+
+func TestCollector(t *testing.T) {
+	c := NewCollector("test")
+
+	c.RecordInvocation(lambda.InvocationMetrics{FunctionARN: functionARN, Duration: time.Millisecond, RequestBytes: 10, ResponseBytes: 20})
+	c.RecordInvocation(lambda.InvocationMetrics{FunctionARN: functionARN, Duration: time.Millisecond, ErrorClass: "context"})
+
+	expected := `
+# HELP test_lambda_invocation_errors_total Number of failed Lambda invocations by error class.
+# TYPE test_lambda_invocation_errors_total counter
+test_lambda_invocation_errors_total{async="false",class="context",function_arn="` + functionARN + `"} 1
+# HELP test_lambda_invocations_total Number of Lambda invocations.
+# TYPE test_lambda_invocations_total counter
+test_lambda_invocations_total{async="false",function_arn="` + functionARN + `"} 2
+`
+
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected), "test_lambda_invocations_total", "test_lambda_invocation_errors_total")
+	assert.NoError(t, err)
+}
+
+func TestCollector_RecordThrottle(t *testing.T) {
+	c := NewCollector("test")
+
+	c.RecordThrottle(lambda.ThrottleMetrics{FunctionARN: functionARN, Attempt: 1})
+	c.RecordThrottle(lambda.ThrottleMetrics{FunctionARN: functionARN, Attempt: 2})
+
+	expected := `
+# HELP test_lambda_throttled_attempts_total Number of throttled Lambda API attempts retried by the SDK.
+# TYPE test_lambda_throttled_attempts_total counter
+test_lambda_throttled_attempts_total{function_arn="` + functionARN + `"} 2
+`
+
+	err := testutil.CollectAndCompare(c, strings.NewReader(expected), "test_lambda_throttled_attempts_total")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,212 @@
+// Package lambdatest runs disposable Lambda functions in LocalStack for integration tests.
+//
+//	cli, err := lambdatest.NewClient(t, lambdatest.Function{})
+//	require.NoError(t, err)
+package lambdatest
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	awslambda "github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/localstack"
+	"lambda-invoker/internal/clients/lambda"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+const (
+	// Region of the LocalStack clients.
+	Region = "eu-central-1"
+	// Image is the LocalStack image started by StartLocalstack.
+	Image = "localstack/localstack:latest"
+)
+
+// HelloHandler is a Python proxy handler responding "Hello from Lambda!", it is the default function code.
+const HelloHandler = `def handler(event, context):
+    return {
+        'statusCode': 200,
+        'body': 'Hello from Lambda!'
+    }`
+
+// Function describes a function to create, zero fields are defaulted.
+type Function struct {
+	// Name is "my-function" by default.
+	Name string
+	// Runtime is Python 3.8 by default.
+	Runtime types.Runtime
+	// Handler is "index.handler" by default.
+	Handler string
+	// Files are packaged into the deployment zip, by default index.py holding HelloHandler.
+	Files map[string][]byte
+}
+
+func (f Function) withDefaults() Function {
+	if f.Name == "" {
+		f.Name = "my-function"
+	}
+	if f.Runtime == "" {
+		f.Runtime = types.RuntimePython38
+	}
+	if f.Handler == "" {
+		f.Handler = "index.handler"
+	}
+	if len(f.Files) == 0 {
+		f.Files = map[string][]byte{"index.py": []byte(HelloHandler)}
+	}
+
+	return f
+}
+
+// NewClient starts LocalStack, creates the function and returns a client of it.
+// The container is removed when the test finishes.
+func NewClient(t testing.TB, fn Function, opts ...lambda.Option) (lambda.Client, error) {
+	ctx := context.Background()
+
+	endpoint, err := StartLocalstack(t)
+	if err != nil {
+		return nil, fmt.Errorf("StartLocalstack: %w", err)
+	}
+
+	awsCli, err := NewAWSClient(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("NewAWSClient: %w", err)
+	}
+
+	functionARN, err := CreateFunction(ctx, awsCli, fn)
+	if err != nil {
+		return nil, fmt.Errorf("CreateFunction: %w", err)
+	}
+
+	cli, err := lambda.New(awsCli, functionARN, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("lambda.New: %w", err)
+	}
+
+	return cli, nil
+}
+
+// StartLocalstack starts a LocalStack container removed when the test finishes and returns its endpoint.
+// https://golang.testcontainers.org/modules/localstack/
+func StartLocalstack(t testing.TB) (string, error) {
+	ctx := context.Background()
+
+	container, err := localstack.Run(ctx, Image)
+	if err != nil {
+		return "", fmt.Errorf("localstack.Run: %w", err)
+	}
+	testcontainers.CleanupContainer(t, container)
+
+	endpoint, err := container.PortEndpoint(ctx, "4566/tcp", "http")
+	if err != nil {
+		return "", fmt.Errorf("container.PortEndpoint: %w", err)
+	}
+
+	return endpoint, nil
+}
+
+// NewAWSClient returns a Lambda API client of the endpoint with static test credentials.
+func NewAWSClient(ctx context.Context, endpoint string) (*awslambda.Client, error) {
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(Region),
+		// Github Actions build fails without StaticCredentialsProvider
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("test", "test", "")))
+	if err != nil {
+		return nil, fmt.Errorf("config.LoadDefaultConfig: %w", err)
+	}
+
+	cli := awslambda.NewFromConfig(cfg, func(o *awslambda.Options) {
+		o.BaseEndpoint = pointer.To(endpoint)
+	})
+
+	return cli, nil
+}
+
+// CreateFunction creates the function, waits until it is active and returns its ARN.
+func CreateFunction(ctx context.Context, cli *awslambda.Client, fn Function) (string, error) {
+	fn = fn.withDefaults()
+
+	code, err := Zip(fn.Files)
+	if err != nil {
+		return "", fmt.Errorf("Zip: %w", err)
+	}
+
+	resp, err := cli.CreateFunction(ctx, &awslambda.CreateFunctionInput{
+		FunctionName: pointer.ToString(fn.Name),
+		Runtime:      fn.Runtime,
+		// LocalStack does not enforce IAM policies, so one can use any ARN format role.
+		Role:    pointer.ToString("arn:aws:iam::000000000000:role/lambda-role"),
+		Handler: pointer.ToString(fn.Handler),
+		Code: &types.FunctionCode{
+			ZipFile: code,
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("cli.CreateFunction: %w", err)
+	}
+
+	if err := WaitForFunction(ctx, cli, fn.Name); err != nil {
+		return "", fmt.Errorf("WaitForFunction: %w", err)
+	}
+
+	return pointer.GetString(resp.FunctionArn), nil
+}
+
+// WaitForFunction polls the function state every second until it is active, failed or ctx is done.
+func WaitForFunction(ctx context.Context, cli *awslambda.Client, functionName string) error {
+	for {
+		resp, err := cli.GetFunction(ctx, &awslambda.GetFunctionInput{
+			FunctionName: pointer.ToString(functionName),
+		})
+		if err != nil {
+			return fmt.Errorf("cli.GetFunction: %w", err)
+		}
+
+		if resp.Configuration != nil {
+			switch resp.Configuration.State {
+			case types.StateActive:
+				return nil
+			case types.StateFailed:
+				return fmt.Errorf("cli.GetFunction: %s", pointer.GetString(resp.Configuration.StateReason))
+			case types.StatePending:
+				slog.Info("Function is Pending state")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("ctx.Done: %w", context.Cause(ctx))
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// Zip packages the files, keyed by name, into a deployment zip.
+func Zip(files map[string][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zipWriter := zip.NewWriter(&buf)
+
+	for name, content := range files {
+		fileWriter, err := zipWriter.Create(name)
+		if err != nil {
+			return nil, fmt.Errorf("zw.Create: %w", err)
+		}
+
+		if _, err := fileWriter.Write(content); err != nil {
+			return nil, fmt.Errorf("fw.Write: %w", err)
+		}
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("zw.Close: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
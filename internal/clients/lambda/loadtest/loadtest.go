@@ -0,0 +1,230 @@
+// Package loadtest runs load tests of a function through lambda.Client.InvokeStreaming
+// and reports latency percentiles and error rates.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lambda-invoker/internal/clients/lambda"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config configures a load test, it runs until Requests are sent or Duration elapses, whichever comes first.
+type Config struct {
+	Request lambda.Request
+	// NewRequest, if set, returns the i-th request instead of Request, e.g. rendered by a lambda.Template.
+	// An error stops the test.
+	NewRequest func(i int) (lambda.Request, error)
+	// Concurrency is the number of in-flight invocations, it must not exceed lambda.WithConcurrency of the client,
+	// otherwise latencies include queueing. 1 by default.
+	Concurrency int
+	// Requests is the total number of invocations, unlimited if zero.
+	Requests int
+	// Duration limits the test, unlimited if zero. Either Requests or Duration must be set.
+	Duration time.Duration
+	// RampUp grows the number of in-flight invocations linearly from 1 to Concurrency over its duration.
+	RampUp time.Duration
+}
+
+// Report summarises a load test.
+type Report struct {
+	Requests int
+	Errors   int
+	// ErrorKinds counts errors by kind.
+	ErrorKinds map[lambda.ErrorKind]int
+	Duration   time.Duration
+	Latency    Latency
+}
+
+// Latency percentiles of all invocations, failed ones included.
+type Latency struct {
+	Min, Mean, P50, P90, P95, P99, Max time.Duration
+}
+
+// ErrorRate is the share of failed invocations in [0, 1].
+func (r Report) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// Throughput is the number of invocations per second.
+func (r Report) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+
+	return float64(r.Requests) / r.Duration.Seconds()
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "requests:    %d in %s (%.1f/s)\n", r.Requests, r.Duration.Round(time.Millisecond), r.Throughput())
+	fmt.Fprintf(&b, "errors:      %d (%.2f%%)\n", r.Errors, 100*r.ErrorRate())
+
+	kinds := make([]lambda.ErrorKind, 0, len(r.ErrorKinds))
+	for k := range r.ErrorKinds {
+		kinds = append(kinds, k)
+	}
+	slices.Sort(kinds)
+	for _, k := range kinds {
+		fmt.Fprintf(&b, "  %-10s %d\n", k.String()+":", r.ErrorKinds[k])
+	}
+
+	l := r.Latency
+	fmt.Fprintf(&b, "latency:     min %s, mean %s, max %s\n", l.Min, l.Mean, l.Max)
+	fmt.Fprintf(&b, "percentiles: p50 %s, p90 %s, p95 %s, p99 %s\n", l.P50, l.P90, l.P95, l.P99)
+
+	return b.String()
+}
+
+// Run runs the load test, invocations still in flight when ctx is done or Duration elapses are not reported.
+func Run(ctx context.Context, cli lambda.Client, cfg Config) (*Report, error) {
+	if cfg.Requests <= 0 && cfg.Duration <= 0 {
+		return nil, errors.New("either Requests or Duration must be set")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if cfg.Duration > 0 {
+		runCtx, cancel = context.WithTimeout(runCtx, cfg.Duration)
+		defer cancel()
+	}
+
+	var (
+		mu     sync.Mutex
+		starts []time.Time
+		reqErr error
+	)
+
+	in := make(chan lambda.Request)
+	slots := make(chan struct{}, cfg.Concurrency)
+	start := time.Now()
+
+	go func() {
+		defer close(in)
+
+		for i := 0; cfg.Requests <= 0 || i < cfg.Requests; i++ {
+			req := cfg.Request
+			if cfg.NewRequest != nil {
+				var err error
+				if req, err = cfg.NewRequest(i); err != nil {
+					mu.Lock()
+					reqErr = fmt.Errorf("NewRequest[%d]: %w", i, err)
+					mu.Unlock()
+					cancel()
+					return
+				}
+			}
+
+			if err := acquire(runCtx, slots, cfg, start); err != nil {
+				return
+			}
+
+			mu.Lock()
+			starts = append(starts, time.Now())
+			mu.Unlock()
+
+			select {
+			case <-runCtx.Done():
+				return
+			case in <- req:
+			}
+		}
+	}()
+
+	report := &Report{ErrorKinds: make(map[lambda.ErrorKind]int)}
+	var latencies []time.Duration
+
+	for r := range cli.InvokeStreaming(runCtx, in) {
+		<-slots
+
+		if r.Err != nil && runCtx.Err() != nil {
+			continue
+		}
+
+		mu.Lock()
+		latencies = append(latencies, time.Since(starts[r.Index]))
+		mu.Unlock()
+
+		report.Requests++
+		if r.Err != nil {
+			report.Errors++
+			report.ErrorKinds[lambda.KindOf(r.Err)]++
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if reqErr != nil {
+		return nil, reqErr
+	}
+
+	report.Duration = time.Since(start)
+	report.Latency = percentiles(latencies)
+
+	return report, nil
+}
+
+// acquire takes an in-flight slot, during ramp-up it waits until the number of in-flight invocations
+// is below the ramp-up limit.
+func acquire(ctx context.Context, slots chan struct{}, cfg Config, start time.Time) error {
+	for {
+		elapsed := time.Since(start)
+		if cfg.RampUp <= 0 || elapsed >= cfg.RampUp ||
+			len(slots) < 1+int(float64(cfg.Concurrency-1)*elapsed.Seconds()/cfg.RampUp.Seconds()) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case slots <- struct{}{}:
+		return nil
+	}
+}
+
+func percentiles(latencies []time.Duration) Latency {
+	if len(latencies) == 0 {
+		return Latency{}
+	}
+
+	slices.Sort(latencies)
+
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+
+	at := func(p float64) time.Duration {
+		return latencies[int(p*float64(len(latencies)-1))]
+	}
+
+	return Latency{
+		Min:  latencies[0],
+		Mean: sum / time.Duration(len(latencies)),
+		P50:  at(0.50),
+		P90:  at(0.90),
+		P95:  at(0.95),
+		P99:  at(0.99),
+		Max:  latencies[len(latencies)-1],
+	}
+}
@@ -0,0 +1,119 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"lambda-invoker/internal/clients/lambda"
+	"lambda-invoker/internal/clients/lambda/fakelambda"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var _ctx = context.Background()
+
+func TestRun(t *testing.T) {
+	var calls, inFlight, maxInFlight atomic.Int32
+
+	fake := fakelambda.New()
+	fake.Handle("GET /", func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		if calls.Add(1)%5 == 0 {
+			return events.APIGatewayProxyResponse{}, errors.New("boom")
+		}
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	cli, err := fake.Client()
+	require.NoError(t, err)
+
+	report, err := Run(_ctx, cli, Config{
+		Request:     lambda.Request{HTTPMethod: "GET", Path: "/"},
+		Concurrency: 4,
+		Requests:    50,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 50, report.Requests)
+	assert.Equal(t, 10, report.Errors)
+	assert.Equal(t, map[lambda.ErrorKind]int{lambda.ErrorKindFunction: 10}, report.ErrorKinds)
+	assert.InDelta(t, 0.2, report.ErrorRate(), 1e-9)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(4))
+	assert.GreaterOrEqual(t, report.Latency.P50, time.Millisecond)
+	assert.LessOrEqual(t, report.Latency.P50, report.Latency.P99)
+	assert.Contains(t, report.String(), "function:")
+}
+
+func TestRun_Duration(t *testing.T) {
+	fake := fakelambda.New()
+	fake.Handle("GET /", func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		time.Sleep(time.Millisecond)
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	cli, err := fake.Client()
+	require.NoError(t, err)
+
+	report, err := Run(_ctx, cli, Config{
+		Request:     lambda.Request{HTTPMethod: "GET", Path: "/"},
+		Concurrency: 2,
+		Duration:    100 * time.Millisecond,
+		RampUp:      50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	assert.Positive(t, report.Requests)
+	assert.Zero(t, report.Errors)
+	assert.InDelta(t, 100*time.Millisecond, report.Duration, float64(50*time.Millisecond))
+
+	_, err = Run(_ctx, cli, Config{})
+	require.Error(t, err)
+}
+
+func TestRun_NewRequest(t *testing.T) {
+	fake := fakelambda.New()
+	fake.Handle("POST /orders", func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	cli, err := fake.Client()
+	require.NoError(t, err)
+
+	tmpl, err := lambda.ParseTemplate(`{"seq":{{.Index}}}`)
+	require.NoError(t, err)
+
+	report, err := Run(_ctx, cli, Config{
+		NewRequest: func(i int) (lambda.Request, error) {
+			body, err := tmpl.Render(lambda.TemplateData{Index: i})
+			return lambda.Request{HTTPMethod: "POST", Path: "/orders", Body: body}, err
+		},
+		Requests: 3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.Requests)
+
+	var bodies []string
+	for _, req := range fake.Requests() {
+		bodies = append(bodies, req.Body)
+	}
+	assert.Equal(t, []string{`{"seq":0}`, `{"seq":1}`, `{"seq":2}`}, bodies)
+
+	_, err = Run(_ctx, cli, Config{
+		NewRequest: func(int) (lambda.Request, error) { return lambda.Request{}, errors.New("bad template") },
+		Requests:   3,
+	})
+	require.ErrorContains(t, err, "bad template")
+}
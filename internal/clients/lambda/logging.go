@@ -0,0 +1,80 @@
+package lambda
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WithLogger logs invocations with the logger: request and response dumps at debug level
+// and failed invocations at error level, all tagged with function ARN and request ID.
+// Retries of WithRetryPolicy are logged at warn level.
+// Verbosity is controlled by the level of the logger's handler. Credential headers such as Authorization and
+// Cookie are not logged, logged values are masked as configured by WithRedaction.
+// Logging is installed as an interceptor at its position among WithInterceptors options.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *client) {
+		c.logger = logger
+		c.interceptors = append(c.interceptors, c.loggingInterceptor)
+	}
+}
+
+func (c *client) loggingInterceptor(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+	if c.logger.Enabled(ctx, slog.LevelDebug) {
+		c.logger.DebugContext(ctx, "lambda request",
+			slog.String("method", req.HTTPMethod),
+			slog.String("path", c.redact(req.Path)),
+			slog.Bool("async", req.Async),
+			slog.Any("headers", c.logHeaders(req.Headers)),
+			slog.String("body", c.redact(string(req.Body))),
+		)
+	}
+
+	start := time.Now()
+	resp, err := next(ctx, req)
+
+	attrs := []any{
+		slog.String("method", req.HTTPMethod),
+		slog.String("path", c.redact(req.Path)),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.String("request_id", resp.RequestID))
+	}
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("correlation_id", id))
+	}
+
+	if err != nil {
+		c.logger.ErrorContext(ctx, "lambda invocation failed", append(attrs, slog.Any("error", c.redactError(err)))...)
+		return resp, err
+	}
+
+	if c.logger.Enabled(ctx, slog.LevelDebug) {
+		c.logger.DebugContext(ctx, "lambda response", append(attrs,
+			slog.Int("status", resp.StatusCode),
+			slog.Any("headers", c.logHeaders(resp.Headers)),
+			slog.String("body", c.redact(resp.Body)),
+		)...)
+	}
+
+	return resp, nil
+}
+
+// sensitiveHeaders carry credentials, they are never logged.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Amz-Security-Token"}
+
+// logHeaders returns headers without sensitive ones and with redacted values.
+func (c *client) logHeaders(headers map[string]string) map[string]string {
+	out := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if slices.ContainsFunc(sensitiveHeaders, func(s string) bool { return strings.EqualFold(s, name) }) {
+			continue
+		}
+		out[name] = c.redact(value)
+	}
+
+	return out
+}
@@ -0,0 +1,67 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithLogger(logger))
+
+	_, err := cli.Invoke(_ctx, "GET", "/orders", []byte(`{"id":1}`))
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "level=DEBUG msg=\"lambda request\"")
+	assert.Contains(t, out, "level=DEBUG msg=\"lambda response\"")
+	assert.Contains(t, out, "function_arn="+testFunctionARN)
+	assert.Contains(t, out, `body="{\"id\":1}"`)
+}
+
+func TestWithLogger_Error(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, errors.New("boom")
+	}}
+
+	cli := newClient(api, testFunctionARN, WithLogger(logger))
+
+	_, err := cli.Invoke(_ctx, "GET", "/orders", nil)
+	require.Error(t, err)
+
+	out := buf.String()
+	assert.NotContains(t, out, "level=DEBUG")
+	assert.Contains(t, out, "level=ERROR msg=\"lambda invocation failed\"")
+	assert.Contains(t, out, "boom")
+}
+
+func TestWithLogger_Sensitive(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithLogger(logger), WithRedaction())
+
+	_, err := cli.Do(_ctx, Request{
+		HTTPMethod: "POST",
+		Path:       "/accounts/123456789012",
+		Headers:    map[string]string{"authorization": "Bearer secret", "Cookie": "session=secret", "X-Account": "123456789012"},
+		Body:       []byte(`{"account":"123456789012"}`),
+	})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.NotContains(t, out, "secret")
+	assert.NotContains(t, out, "123456789012")
+	assert.Contains(t, out, "X-Account:"+RedactAccountIDs("123456789012"))
+}
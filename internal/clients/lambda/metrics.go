@@ -0,0 +1,71 @@
+package lambda
+
+import (
+	"context"
+	"time"
+)
+
+// InvocationMetrics describes a completed invocation, it is passed to MetricsRecorder.
+type InvocationMetrics struct {
+	FunctionARN   string
+	HTTPMethod    string
+	Path          string
+	Async         bool
+	StatusCode    int
+	RequestBytes  int
+	ResponseBytes int
+	Duration      time.Duration
+	// ErrorClass is empty for successful invocations, see errorClass.
+	ErrorClass string
+}
+
+// MetricsRecorder receives metrics of every invocation, see the lambdaprom package for a Prometheus one.
+type MetricsRecorder interface {
+	RecordInvocation(m InvocationMetrics)
+}
+
+// WithMetrics records metrics of every invocation with the recorder,
+// it is installed as an interceptor at its position among WithInterceptors options.
+func WithMetrics(recorder MetricsRecorder) Option {
+	return func(c *client) {
+		if tr, ok := recorder.(ThrottleRecorder); ok {
+			c.throttleRecorders = append(c.throttleRecorders, tr)
+		}
+		c.interceptors = append(c.interceptors, c.metricsInterceptor(recorder))
+	}
+}
+
+func (c *client) metricsInterceptor(recorder MetricsRecorder) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		start := time.Now()
+
+		resp, err := next(ctx, req)
+
+		m := InvocationMetrics{
+			FunctionARN:  c.redact(c.functionARN),
+			HTTPMethod:   req.HTTPMethod,
+			Path:         req.Path,
+			Async:        req.Async,
+			RequestBytes: len(req.Body),
+			Duration:     time.Since(start),
+			ErrorClass:   errorClass(err),
+		}
+		if resp != nil {
+			m.StatusCode = resp.StatusCode
+			m.ResponseBytes = len(resp.Body)
+		}
+
+		recorder.RecordInvocation(m)
+
+		return resp, err
+	}
+}
+
+// errorClass returns a low cardinality class of err suitable for metric labels.
+func errorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return KindOf(err).String()
+}
@@ -0,0 +1,44 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type metricsRecorderFunc func(m InvocationMetrics)
+
+func (f metricsRecorderFunc) RecordInvocation(m InvocationMetrics) {
+	f(m)
+}
+
+func TestWithMetrics(t *testing.T) {
+	var recorded []InvocationMetrics
+	recorder := metricsRecorderFunc(func(m InvocationMetrics) {
+		recorded = append(recorded, m)
+	})
+
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN, WithMetrics(recorder))
+
+	_, err := cli.Invoke(_ctx, "POST", "/orders", []byte(`{}`))
+	require.NoError(t, err)
+
+	api.invoke = func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, context.DeadlineExceeded
+	}
+	_, err = cli.Invoke(_ctx, "POST", "/orders", nil)
+	require.Error(t, err)
+
+	require.Len(t, recorded, 2)
+
+	assert.Equal(t, testFunctionARN, recorded[0].FunctionARN)
+	assert.Equal(t, 200, recorded[0].StatusCode)
+	assert.Equal(t, 2, recorded[0].RequestBytes)
+	assert.Equal(t, 2, recorded[0].ResponseBytes)
+	assert.Empty(t, recorded[0].ErrorClass)
+
+	assert.Equal(t, "context", recorded[1].ErrorClass)
+}
@@ -0,0 +1,58 @@
+package lambda
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// MiddlewareConfig configures Middleware.
+type MiddlewareConfig struct {
+	// Prefix selects the forwarded route subtree, e.g. "/legacy" forwards "/legacy" and "/legacy/...".
+	Prefix string
+	// StripPrefix removes Prefix from paths of forwarded requests, e.g. "/legacy/orders" is invoked as "/orders".
+	StripPrefix bool
+	// Methods limit forwarded requests, all methods by default.
+	Methods []string
+	// Handler configures forwarding, see NewHTTPHandler. Its StripPrefix is set from the fields above.
+	Handler HTTPHandlerConfig
+}
+
+// Middleware forwards requests of a route subtree to the function and passes others to the next handler,
+// e.g. to migrate routes between a service and functions one by one. It is a plain net/http middleware:
+//
+//	r.Use(lambda.Middleware(client, cfg))                     // chi
+//	e.Use(echo.WrapMiddleware(lambda.Middleware(client, cfg))) // echo
+//
+// For gin mount the handler instead: r.Any("/legacy/*path", gin.WrapH(lambda.NewHTTPHandler(client, cfg))).
+func Middleware(client Client, cfg MiddlewareConfig) func(http.Handler) http.Handler {
+	prefix := strings.TrimSuffix(cfg.Prefix, "/")
+
+	handlerCfg := cfg.Handler
+	handlerCfg.StripPrefix = ""
+	if cfg.StripPrefix {
+		handlerCfg.StripPrefix = prefix
+	}
+
+	forward := NewHTTPHandler(client, handlerCfg)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !hasPathPrefix(r.URL.Path, prefix) || len(cfg.Methods) > 0 && !slices.Contains(cfg.Methods, r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			forward.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasPathPrefix reports whether path is prefix or below it, "/legacy" is not a prefix of "/legacyfoo".
+func hasPathPrefix(path, prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
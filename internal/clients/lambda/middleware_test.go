@@ -0,0 +1,47 @@
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	tests := []struct {
+		name       string
+		cfg        MiddlewareConfig
+		method     string
+		path       string
+		wantStatus int
+		wantPath   string
+	}{
+		{name: "subtree", cfg: MiddlewareConfig{Prefix: "/legacy"}, method: "GET", path: "/legacy/orders", wantStatus: http.StatusOK, wantPath: "/legacy/orders"},
+		{name: "root", cfg: MiddlewareConfig{Prefix: "/legacy/"}, method: "GET", path: "/legacy", wantStatus: http.StatusOK, wantPath: "/legacy"},
+		{name: "strip", cfg: MiddlewareConfig{Prefix: "/legacy", StripPrefix: true}, method: "GET", path: "/legacy/orders", wantStatus: http.StatusOK, wantPath: "/orders"},
+		{name: "strip root", cfg: MiddlewareConfig{Prefix: "/legacy", StripPrefix: true}, method: "GET", path: "/legacy", wantStatus: http.StatusOK, wantPath: "/"},
+		{name: "other route", cfg: MiddlewareConfig{Prefix: "/legacy"}, method: "GET", path: "/legacyfoo", wantStatus: http.StatusTeapot},
+		{name: "method", cfg: MiddlewareConfig{Prefix: "/legacy", Methods: []string{"GET"}}, method: "POST", path: "/legacy/orders", wantStatus: http.StatusTeapot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{}
+			handler := Middleware(newClient(api, testFunctionARN), tt.cfg)(next)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantPath == "" {
+				assert.Empty(t, api.calls())
+				return
+			}
+			assert.Equal(t, tt.wantPath, proxyRequest(api.calls()[0]).Path)
+		})
+	}
+}
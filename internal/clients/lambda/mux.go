@@ -0,0 +1,57 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"maps"
+	"net/http"
+)
+
+type muxRoute struct {
+	route   route
+	handler Handler
+}
+
+// Mux is a function side Handler dispatching proxy requests to handlers by "METHOD /path" patterns,
+// see WithRouteCodec for the syntax. Values of path parameters are added to the request PathParameters.
+type Mux struct {
+	routes []muxRoute
+}
+
+func NewMux() *Mux {
+	return &Mux{}
+}
+
+// Handle registers the handler serving requests matching the pattern, routes are matched in registration order.
+// It panics if the pattern is invalid.
+func (m *Mux) Handle(pattern string, handler Handler) {
+	m.routes = append(m.routes, muxRoute{route: mustParseRoute(pattern), handler: handler})
+}
+
+// Invoke calls the handler of the first matching route, it responds 404 Not Found if no route matches.
+// Pass it to lambda.Start of aws-lambda-go.
+func (m *Mux) Invoke(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	for _, mr := range m.routes {
+		params, ok := mr.route.match(req.HTTPMethod, req.Path)
+		if !ok {
+			continue
+		}
+
+		if len(params) > 0 {
+			pathParameters := maps.Clone(req.PathParameters)
+			if pathParameters == nil {
+				pathParameters = make(map[string]string, len(params))
+			}
+			maps.Copy(pathParameters, params)
+			req.PathParameters = pathParameters
+		}
+
+		return mr.handler(ctx, req)
+	}
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusNotFound,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       `{"message":"Not Found"}`,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestMux(t *testing.T) {
+	echo := func(name string) Handler {
+		return func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusOK, Body: name + ":" + req.PathParameters["id"]}, nil
+		}
+	}
+
+	mux := NewMux()
+	mux.Handle("GET /orders/{id}", echo("get"))
+	mux.Handle("ANY /orders/{id}", echo("any"))
+
+	tests := []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{"GET", "/orders/42", http.StatusOK, "get:42"},
+		{"DELETE", "/orders/42", http.StatusOK, "any:42"},
+		{"GET", "/payments", http.StatusNotFound, `{"message":"Not Found"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			resp, err := mux.Invoke(_ctx, events.APIGatewayProxyRequest{HTTPMethod: tt.method, Path: tt.path})
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.body, resp.Body)
+		})
+	}
+}
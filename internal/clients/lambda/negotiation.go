@@ -0,0 +1,55 @@
+package lambda
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// ErrContentTypeMismatch is matched by ContentTypeMismatchError.
+var ErrContentTypeMismatch = errors.New("content type mismatch")
+
+// ContentTypeMismatchError is returned by Client.Call if the response Content-Type is not accepted by the codec,
+// instead of decoding the body with the wrong codec. Responses without Content-Type are decoded.
+type ContentTypeMismatchError struct {
+	Expected string
+	Actual   string
+}
+
+func (e *ContentTypeMismatchError) Error() string {
+	return fmt.Sprintf("content type mismatch: expected %s, got %s", e.Expected, e.Actual)
+}
+
+func (e *ContentTypeMismatchError) Unwrap() error {
+	return ErrContentTypeMismatch
+}
+
+// ContentTypeAcceptor is optionally implemented by a Codec which decodes more media types than its ContentType,
+// e.g. both application/protobuf and application/x-protobuf.
+type ContentTypeAcceptor interface {
+	Accepts(mediaType string) bool
+}
+
+// negotiate checks the response content type against the codec.
+func negotiate(codec Codec, headers map[string]string) error {
+	contentType, ok := headerValue(headers, "Content-Type")
+	if !ok || contentType == "" {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("mime.ParseMediaType[%s]: %w", contentType, err)
+	}
+
+	if acceptor, ok := codec.(ContentTypeAcceptor); ok {
+		if acceptor.Accepts(mediaType) {
+			return nil
+		}
+	} else if expected, _, err := mime.ParseMediaType(codec.ContentType()); err == nil && strings.EqualFold(expected, mediaType) {
+		return nil
+	}
+
+	return &ContentTypeMismatchError{Expected: codec.ContentType(), Actual: contentType}
+}
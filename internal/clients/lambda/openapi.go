@@ -0,0 +1,404 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"maps"
+	"mime"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrUnknownOperation is returned by OpenAPIClient for operation IDs which are not in the spec.
+	ErrUnknownOperation = errors.New("unknown operation")
+	// ErrContractViolation is returned by OpenAPIClient for requests or responses not conforming to the spec.
+	ErrContractViolation = errors.New("contract violation")
+)
+
+// OpenAPIClient invokes the operations of an OpenAPI 3 spec by operation ID: parameters are serialized into the
+// path, query and headers, and request and response bodies are validated against the JSON schemas of the spec.
+// Only local $ref of schemas and parameters and the default parameter styles are supported.
+type OpenAPIClient struct {
+	client     Client
+	components openAPIComponents
+	operations map[string]openAPIOperation
+}
+
+type openAPISpec struct {
+	Paths      map[string]openAPIPathItem `yaml:"paths"`
+	Components openAPIComponents          `yaml:"components"`
+}
+
+type openAPIComponents struct {
+	Schemas    map[string]*openAPISchema   `yaml:"schemas"`
+	Parameters map[string]openAPIParameter `yaml:"parameters"`
+}
+
+type openAPIPathItem struct {
+	Parameters []openAPIParameter `yaml:"parameters"`
+	Get        *openAPIOperation  `yaml:"get"`
+	Put        *openAPIOperation  `yaml:"put"`
+	Post       *openAPIOperation  `yaml:"post"`
+	Delete     *openAPIOperation  `yaml:"delete"`
+	Patch      *openAPIOperation  `yaml:"patch"`
+	Head       *openAPIOperation  `yaml:"head"`
+	Options    *openAPIOperation  `yaml:"options"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `yaml:"operationId"`
+	Parameters  []openAPIParameter         `yaml:"parameters"`
+	RequestBody *openAPIRequestBody        `yaml:"requestBody"`
+	Responses   map[string]openAPIResponse `yaml:"responses"`
+
+	method string
+	path   string
+}
+
+type openAPIParameter struct {
+	Ref      string         `yaml:"$ref"`
+	Name     string         `yaml:"name"`
+	In       string         `yaml:"in"`
+	Required bool           `yaml:"required"`
+	Explode  *bool          `yaml:"explode"`
+	Schema   *openAPISchema `yaml:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `yaml:"required"`
+	Content  map[string]openAPIMediaType `yaml:"content"`
+}
+
+type openAPIResponse struct {
+	Content map[string]openAPIMediaType `yaml:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `yaml:"schema"`
+}
+
+type openAPISchema struct {
+	Ref        string                    `yaml:"$ref"`
+	Type       string                    `yaml:"type"`
+	Required   []string                  `yaml:"required"`
+	Properties map[string]*openAPISchema `yaml:"properties"`
+	Items      *openAPISchema            `yaml:"items"`
+	Enum       []any                     `yaml:"enum"`
+	Nullable   bool                      `yaml:"nullable"`
+}
+
+// NewOpenAPIClient returns a client of the operations of the YAML or JSON OpenAPI 3 spec file.
+func NewOpenAPIClient(client Client, specPath string) (*OpenAPIClient, error) {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	var spec openAPISpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("yaml.Unmarshal[%s]: %w", specPath, err)
+	}
+
+	c := &OpenAPIClient{client: client, components: spec.Components, operations: make(map[string]openAPIOperation)}
+
+	for path, item := range spec.Paths {
+		for method, op := range item.operations() {
+			if op.OperationID == "" {
+				return nil, fmt.Errorf("%s %s: operationId is empty", method, path)
+			}
+
+			op.method, op.path = method, path
+			op.Parameters = append(slices.Clone(item.Parameters), op.Parameters...)
+			for i, p := range op.Parameters {
+				if op.Parameters[i], err = c.resolveParameter(p); err != nil {
+					return nil, fmt.Errorf("%s: %w", op.OperationID, err)
+				}
+			}
+
+			c.operations[op.OperationID] = *op
+		}
+	}
+
+	return c, nil
+}
+
+func (item openAPIPathItem) operations() map[string]*openAPIOperation {
+	ops := make(map[string]*openAPIOperation)
+	for method, op := range map[string]*openAPIOperation{
+		"GET": item.Get, "PUT": item.Put, "POST": item.Post, "DELETE": item.Delete,
+		"PATCH": item.Patch, "HEAD": item.Head, "OPTIONS": item.Options,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+
+	return ops
+}
+
+func (c *OpenAPIClient) resolveParameter(p openAPIParameter) (openAPIParameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+
+	resolved, ok := c.components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+	if !ok {
+		return p, fmt.Errorf("unresolved $ref: %s", p.Ref)
+	}
+
+	return resolved, nil
+}
+
+// Operations returns the sorted operation IDs of the spec.
+func (c *OpenAPIClient) Operations() []string {
+	return slices.Sorted(maps.Keys(c.operations))
+}
+
+// Call invokes the operation with params by name and in as the JSON request body, unless nil, and decodes the JSON
+// response body into out, unless nil. Slice parameters are serialized as repeated query parameters, or comma
+// separated with explode: false and in paths and headers. Declared error statuses are returned as ErrUnexpectedStatus,
+// undeclared statuses and invalid bodies as ErrContractViolation.
+func (c *OpenAPIClient) Call(ctx context.Context, operationID string, params map[string]any, in, out any, opts ...Option) error {
+	op, ok := c.operations[operationID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownOperation, operationID)
+	}
+
+	req, err := c.newRequest(op, params, in)
+	if err != nil {
+		return fmt.Errorf("%s: %w", operationID, err)
+	}
+
+	resp, err := c.client.Do(ctx, req, opts...)
+	if err != nil && !responded(resp, err) {
+		return fmt.Errorf("%s: %w", operationID, err)
+	}
+
+	body, err := c.validateResponse(op, resp)
+	if err != nil {
+		return fmt.Errorf("%s: %w", operationID, err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%s: %w", operationID, &ErrUnexpectedStatus{Code: resp.StatusCode, Body: string(body)})
+	}
+
+	if out != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("%s: json.Unmarshal: %w", operationID, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *OpenAPIClient) newRequest(op openAPIOperation, params map[string]any, in any) (Request, error) {
+	req := Request{HTTPMethod: op.method, Path: op.path, Headers: map[string]string{"Accept": "application/json"}}
+
+	for _, p := range op.Parameters {
+		v, ok := params[p.Name]
+		if !ok || v == nil {
+			if p.Required || p.In == "path" {
+				return req, fmt.Errorf("%w: parameter %s is required", ErrContractViolation, p.Name)
+			}
+			continue
+		}
+
+		values := parameterValues(v)
+
+		switch p.In {
+		case "path":
+			req.Path = strings.ReplaceAll(req.Path, "{"+p.Name+"}", url.PathEscape(strings.Join(values, ",")))
+		case "query":
+			if req.Query == nil {
+				req.Query = make(url.Values)
+			}
+			if p.Explode == nil || *p.Explode {
+				req.Query[p.Name] = values
+			} else {
+				req.Query.Set(p.Name, strings.Join(values, ","))
+			}
+		case "header":
+			req.Headers[p.Name] = strings.Join(values, ",")
+		}
+	}
+
+	if in == nil {
+		if op.RequestBody != nil && op.RequestBody.Required {
+			return req, fmt.Errorf("%w: request body is required", ErrContractViolation)
+		}
+		return req, nil
+	}
+
+	body, err := json.Marshal(in)
+	if err != nil {
+		return req, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	if op.RequestBody != nil {
+		if mt, ok := op.RequestBody.Content["application/json"]; ok {
+			if err := c.validate(mt.Schema, body, "request body"); err != nil {
+				return req, err
+			}
+		}
+	}
+
+	req.Headers["Content-Type"] = "application/json"
+	req.Body = body
+
+	return req, nil
+}
+
+// parameterValues formats scalars and slices of scalars.
+func parameterValues(v any) []string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return []string{fmt.Sprint(v)}
+	}
+
+	values := make([]string, rv.Len())
+	for i := range values {
+		values[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+
+	return values
+}
+
+// validateResponse checks that the status is declared and the JSON body matches its schema, it returns the body.
+func (c *OpenAPIClient) validateResponse(op openAPIOperation, resp *Response) ([]byte, error) {
+	body, err := resp.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("resp.Bytes: %w", err)
+	}
+
+	declared, ok := op.Responses[strconv.Itoa(resp.StatusCode)]
+	if !ok {
+		declared, ok = op.Responses[strconv.Itoa(resp.StatusCode/100)+"XX"]
+	}
+	if !ok {
+		declared, ok = op.Responses["default"]
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: response status %d is not declared", ErrContractViolation, resp.StatusCode)
+	}
+
+	if len(declared.Content) == 0 || len(body) == 0 {
+		return body, nil
+	}
+
+	contentType, _ := headerValue(resp.Headers, "Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "" {
+		mediaType = "application/json"
+	}
+
+	mt, ok := declared.Content[mediaType]
+	if !ok {
+		return nil, fmt.Errorf("%w: response content type %s is not declared", ErrContractViolation, contentType)
+	}
+
+	if mediaType == "application/json" {
+		if err := c.validate(mt.Schema, body, "response body"); err != nil {
+			return nil, err
+		}
+	}
+
+	return body, nil
+}
+
+func (c *OpenAPIClient) validate(schema *openAPISchema, body []byte, name string) error {
+	if schema == nil {
+		return nil
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Errorf("%w: %s is not JSON: %w", ErrContractViolation, name, err)
+	}
+
+	if err := c.validateValue(schema, v, "$"); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrContractViolation, name, err)
+	}
+
+	return nil
+}
+
+// validateValue validates a decoded JSON value against the type, required, properties, items and enum keywords.
+func (c *OpenAPIClient) validateValue(schema *openAPISchema, v any, at string) error {
+	for depth := 0; schema.Ref != ""; depth++ {
+		resolved, ok := c.components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if !ok || depth > 32 {
+			return fmt.Errorf("%s: unresolved $ref: %s", at, schema.Ref)
+		}
+		schema = resolved
+	}
+
+	if v == nil {
+		if schema.Nullable || schema.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not %s", at, schema.Type)
+	}
+
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(v) }) {
+		return fmt.Errorf("%s: %v is not one of %v", at, v, schema.Enum)
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: %T is not an object", at, v)
+		}
+		for _, name := range schema.Required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: property %s is required", at, name)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			if pv, ok := obj[name]; ok {
+				if err := c.validateValue(schema.Properties[name], pv, at+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: %T is not an array", at, v)
+		}
+		if schema.Items != nil {
+			for i, item := range arr {
+				if err := c.validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%s: %T is not a string", at, v)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s: %T is not a number", at, v)
+		}
+	case "integer":
+		if f, ok := v.(float64); !ok || f != float64(int64(f)) {
+			return fmt.Errorf("%s: %v is not an integer", at, v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: %T is not a boolean", at, v)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func newOpenAPITestClient(t *testing.T, responses map[string]events.APIGatewayProxyResponse) (*OpenAPIClient, *fakeAPI) {
+	t.Helper()
+
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		req := proxyRequest(in)
+		payload, err := json.Marshal(responses[req.HTTPMethod+" "+req.Path])
+		if err != nil {
+			return nil, err
+		}
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, nil
+	}}
+
+	c, err := NewOpenAPIClient(newClient(api, testFunctionARN), "testdata/openapi.yaml")
+	require.NoError(t, err)
+
+	return c, api
+}
+
+func TestOpenAPIClient(t *testing.T) {
+	c, api := newOpenAPITestClient(t, map[string]events.APIGatewayProxyResponse{
+		"GET /orders":       {StatusCode: http.StatusOK, Body: `[{"id":"1","status":"new"}]`},
+		"POST /orders":      {StatusCode: http.StatusCreated, Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"id":"2","status":"new","quantity":3}`},
+		"GET /orders/a%2Fb": {StatusCode: http.StatusOK, Body: `{"id":"a/b","status":"shipped"}`},
+	})
+	assert.Equal(t, []string{"createOrder", "getOrder", "listOrders"}, c.Operations())
+
+	var orders []map[string]any
+	require.NoError(t, c.Call(_ctx, "listOrders", map[string]any{"status": []string{"new", "shipped"}, "fields": []string{"id", "status"}}, nil, &orders))
+	assert.Equal(t, []map[string]any{{"id": "1", "status": "new"}}, orders)
+
+	event := proxyRequest(api.calls()[0])
+	assert.Equal(t, "GET", event.HTTPMethod)
+	assert.Equal(t, map[string][]string{"status": {"new", "shipped"}, "fields": {"id,status"}}, event.MultiValueQueryStringParameters)
+
+	var created struct {
+		ID       string
+		Quantity int
+	}
+	require.NoError(t, c.Call(_ctx, "createOrder", map[string]any{"X-Tenant": "acme"}, map[string]any{"item": "book", "quantity": 3}, &created))
+	assert.Equal(t, "2", created.ID)
+	assert.Equal(t, 3, created.Quantity)
+
+	event = proxyRequest(api.calls()[1])
+	assert.Equal(t, "acme", event.Headers["X-Tenant"])
+	assert.Equal(t, "application/json", event.Headers["Content-Type"])
+	assert.JSONEq(t, `{"item":"book","quantity":3}`, event.Body)
+
+	require.NoError(t, c.Call(_ctx, "getOrder", map[string]any{"id": "a/b"}, nil, nil))
+	assert.Equal(t, "/orders/a%2Fb", proxyRequest(api.calls()[2]).Path)
+}
+
+func TestOpenAPIClient_Errors(t *testing.T) {
+	c, api := newOpenAPITestClient(t, map[string]events.APIGatewayProxyResponse{
+		"POST /orders":       {StatusCode: http.StatusConflict, Body: `{"message":"duplicate"}`},
+		"GET /orders/1":      {StatusCode: http.StatusOK, Body: `{"id":"1"}`},
+		"GET /orders/2":      {StatusCode: http.StatusOK, Body: `{"id":"2","status":"lost"}`},
+		"GET /orders/3":      {StatusCode: http.StatusOK, Body: `{"id":3,"status":"new"}`},
+		"GET /orders/4":      {StatusCode: http.StatusOK, Headers: map[string]string{"Content-Type": "text/plain"}, Body: `order 4`},
+		"GET /orders/absent": {StatusCode: http.StatusNotFound, Body: `{"message":"not found"}`},
+	})
+
+	err := c.Call(_ctx, "createOrder", map[string]any{"X-Tenant": "acme"}, map[string]any{"item": "book"}, nil)
+	var use *ErrUnexpectedStatus
+	require.ErrorAs(t, err, &use, "declared error status")
+	assert.Equal(t, http.StatusConflict, use.Code)
+
+	invalid := []struct {
+		name   string
+		op     string
+		params map[string]any
+		in     any
+	}{
+		{name: "missing path parameter", op: "getOrder"},
+		{name: "missing required header", op: "createOrder", in: map[string]any{"item": "book"}},
+		{name: "missing request body", op: "createOrder", params: map[string]any{"X-Tenant": "acme"}},
+		{name: "invalid request body", op: "createOrder", params: map[string]any{"X-Tenant": "acme"}, in: map[string]any{"item": "book", "quantity": 1.5}},
+		{name: "missing property", op: "getOrder", params: map[string]any{"id": 1}},
+		{name: "enum", op: "getOrder", params: map[string]any{"id": 2}},
+		{name: "type", op: "getOrder", params: map[string]any{"id": 3}},
+		{name: "content type", op: "getOrder", params: map[string]any{"id": 4}},
+		{name: "undeclared status", op: "getOrder", params: map[string]any{"id": "absent"}},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, c.Call(_ctx, tt.op, tt.params, tt.in, nil), ErrContractViolation)
+		})
+	}
+
+	assert.Len(t, api.calls(), 6, "invalid requests are not sent")
+	require.ErrorIs(t, c.Call(_ctx, "deleteOrder", nil, nil, nil), ErrUnknownOperation)
+}
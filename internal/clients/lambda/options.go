@@ -0,0 +1,6 @@
+package lambda
+
+// Option configures the client returned by New.
+// Call scoped options, i.e. WithHeader, WithQualifier, WithLogType, WithTimeout, WithCacheTTL, WithIdempotencyKey
+// and WithJMESPath, can also be passed per call to override the client defaults, other options are ignored per call.
+type Option func(*client)
@@ -0,0 +1,36 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestOptions(t *testing.T) {
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+
+		out := proxyOutput(in, http.StatusOK, "ok")
+		out.LogResult = pointer.To(base64.StdEncoding.EncodeToString([]byte("START RequestId: 1\nEND RequestId: 1\n")))
+		return out, nil
+	}}
+	cli := newClient(api, testFunctionARN, WithQualifier("live"), WithLogType(types.LogTypeTail), WithTimeout(time.Minute))
+
+	resp, err := cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "START RequestId: 1\nEND RequestId: 1\n", resp.LogResult)
+
+	in := api.calls()[0]
+	assert.Equal(t, "live", *in.Qualifier)
+	assert.Equal(t, types.LogTypeTail, in.LogType)
+}
@@ -0,0 +1,98 @@
+package lambda
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// terraformOutput is a single entry of `terraform output -json`.
+type terraformOutput struct {
+	Value any `json:"value"`
+}
+
+// cloudFormationOutput is a single stack output of `aws cloudformation describe-stacks`.
+type cloudFormationOutput struct {
+	OutputKey   string `json:"OutputKey"`
+	OutputValue string `json:"OutputValue"`
+}
+
+type cloudFormationStacks struct {
+	Stacks []struct {
+		Outputs []cloudFormationOutput `json:"Outputs"`
+	} `json:"Stacks"`
+}
+
+// LoadOutputs reads a `terraform output -json` or `aws cloudformation describe-stacks` file
+// and returns outputs holding Lambda function ARNs keyed by output name, other outputs are skipped.
+// A bare JSON array of CloudFormation outputs is accepted as well.
+func LoadOutputs(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	outputs, err := parseOutputs(data)
+	if err != nil {
+		return nil, fmt.Errorf("parseOutputs[%s]: %w", path, err)
+	}
+
+	return outputs, nil
+}
+
+// RegisterOutputs registers every function of LoadOutputs under its output name.
+func (r *Registry) RegisterOutputs(path string, opts ...Option) error {
+	outputs, err := LoadOutputs(path)
+	if err != nil {
+		return fmt.Errorf("LoadOutputs: %w", err)
+	}
+
+	for name, functionARN := range outputs {
+		if err := r.Register(name, functionARN, opts...); err != nil {
+			return fmt.Errorf("r.Register: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func parseOutputs(data []byte) (map[string]string, error) {
+	var stacks cloudFormationStacks
+	if err := json.Unmarshal(data, &stacks); err == nil && len(stacks.Stacks) > 0 {
+		var outputs []cloudFormationOutput
+		for _, s := range stacks.Stacks {
+			outputs = append(outputs, s.Outputs...)
+		}
+		return cloudFormationFunctions(outputs), nil
+	}
+
+	var outputs []cloudFormationOutput
+	if err := json.Unmarshal(data, &outputs); err == nil {
+		return cloudFormationFunctions(outputs), nil
+	}
+
+	var tf map[string]terraformOutput
+	if err := json.Unmarshal(data, &tf); err != nil {
+		return nil, fmt.Errorf("neither Terraform nor CloudFormation outputs: %w", err)
+	}
+
+	functions := make(map[string]string)
+	for name, o := range tf {
+		if s, ok := o.Value.(string); ok && isFunctionARN(s) {
+			functions[name] = s
+		}
+	}
+
+	return functions, nil
+}
+
+func cloudFormationFunctions(outputs []cloudFormationOutput) map[string]string {
+	functions := make(map[string]string)
+	for _, o := range outputs {
+		if isFunctionARN(o.OutputValue) {
+			functions[o.OutputKey] = o.OutputValue
+		}
+	}
+
+	return functions
+}
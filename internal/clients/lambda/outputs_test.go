@@ -0,0 +1,50 @@
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLoadOutputs(t *testing.T) {
+	tests := []struct {
+		file string
+		want map[string]string
+	}{
+		{
+			file: "testdata/terraform-outputs.json",
+			want: map[string]string{
+				"orders_function_arn":   "arn:aws:lambda:eu-central-1:000000000000:function:orders",
+				"payments_function_arn": "arn:aws:lambda:eu-central-1:000000000000:function:payments",
+			},
+		},
+		{
+			file: "testdata/cloudformation-outputs.json",
+			want: map[string]string{
+				"OrdersFunctionArn": "arn:aws:lambda:eu-central-1:000000000000:function:orders",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			outputs, err := LoadOutputs(tt.file)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, outputs)
+		})
+	}
+}
+
+func TestRegistry_RegisterOutputs(t *testing.T) {
+	registry := newRegistry(&fakeAPI{})
+
+	require.NoError(t, registry.RegisterOutputs("testdata/terraform-outputs.json"))
+	assert.Equal(t, []string{"orders_function_arn", "payments_function_arn"}, registry.Names())
+
+	out, err := registry.Invoke(_ctx, "orders_function_arn", "GET", "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", out)
+
+	_, err = registry.Invoke(_ctx, "unknown", "GET", "/", nil)
+	assert.ErrorIs(t, err, ErrUnknownFunction)
+}
@@ -0,0 +1,39 @@
+package lambda
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// WithPprofLabels runs every invocation with "lambda_function" and "lambda_route" pprof labels,
+// so that CPU and goroutine profiles of the caller attribute time spent in invocations to functions and routes.
+// The route is the pattern of the matching WithRouteCodec route, or the method and path otherwise.
+func WithPprofLabels() Option {
+	return func(c *client) {
+		c.interceptors = append(c.interceptors, c.pprofInterceptor)
+	}
+}
+
+func (c *client) pprofInterceptor(ctx context.Context, req *Request, next Invoker) (resp *Response, err error) {
+	labels := pprof.Labels(
+		"lambda_function", c.redact(c.functionARN),
+		"lambda_route", c.routeName(req.HTTPMethod, req.Path),
+	)
+
+	pprof.Do(ctx, labels, func(ctx context.Context) {
+		resp, err = next(ctx, req)
+	})
+
+	return resp, err
+}
+
+// routeName returns the pattern of the first registered route matching the request.
+func (c *client) routeName(httpMethod, path string) string {
+	for _, rc := range c.routeCodecs {
+		if _, ok := rc.route.match(httpMethod, path); ok {
+			return rc.route.pattern
+		}
+	}
+
+	return httpMethod + " " + path
+}
@@ -0,0 +1,33 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"runtime/pprof"
+	"testing"
+)
+
+func TestWithPprofLabels(t *testing.T) {
+	labels := make(map[string]string)
+
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		pprof.ForLabels(ctx, func(key, value string) bool {
+			labels[key] = value
+			return true
+		})
+		return proxyOutput(in, http.StatusOK, ""), nil
+	}}
+
+	cli := newClient(api, testFunctionARN, WithRouteCodec("GET /orders/{id}", JSONCodec), WithPprofLabels())
+
+	_, err := cli.Invoke(_ctx, "GET", "/orders/42", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"lambda_function": testFunctionARN,
+		"lambda_route":    "GET /orders/{id}",
+	}, labels)
+}
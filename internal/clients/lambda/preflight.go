@@ -0,0 +1,162 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"sync"
+	"time"
+)
+
+// ErrFunctionNotReady is matched by FunctionNotReadyError.
+var ErrFunctionNotReady = errors.New("function is not ready")
+
+// FunctionNotReadyError is returned before invoking a function failing the pre-flight check of WithPreflight.
+type FunctionNotReadyError struct {
+	Function  string
+	Qualifier string
+	Reason    string
+}
+
+func (e *FunctionNotReadyError) Error() string {
+	function := e.Function
+	if e.Qualifier != "" {
+		function += ":" + e.Qualifier
+	}
+
+	return fmt.Sprintf("function %s is not ready: %s", function, e.Reason)
+}
+
+func (e *FunctionNotReadyError) Unwrap() error {
+	return ErrFunctionNotReady
+}
+
+// PreflightConfig configures WithPreflight.
+type PreflightConfig struct {
+	// Interval is how long a passed check is trusted, 30 seconds by default. Failed checks are not cached.
+	Interval time.Duration
+	// ProvisionedConcurrency requires the qualified version or alias to have ready provisioned concurrency
+	// with instances available, it is only checked with a *lambda.Client.
+	ProvisionedConcurrency bool
+}
+
+// provisionedConcurrencyAPI is implemented by *lambda.Client.
+type provisionedConcurrencyAPI interface {
+	GetProvisionedConcurrencyConfig(ctx context.Context, params *lambda.GetProvisionedConcurrencyConfigInput, optFns ...func(*lambda.Options)) (*lambda.GetProvisionedConcurrencyConfigOutput, error)
+}
+
+// preflight caches passed checks per qualifier.
+type preflight struct {
+	cfg PreflightConfig
+
+	mu     sync.Mutex
+	passed map[string]time.Time
+}
+
+// WithPreflight checks that the function is Active and not throttled by zero reserved concurrency before
+// invoking it, returning FunctionNotReadyError otherwise.
+func WithPreflight(cfg PreflightConfig) Option {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+
+	return func(c *client) {
+		c.preflight = &preflight{cfg: cfg, passed: make(map[string]time.Time)}
+	}
+}
+
+// preflighted runs the pre-flight check before next unless a check of the qualifier passed recently.
+func (c *client) preflighted(next Invoker) Invoker {
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		if err := c.preflightCheck(ctx); err != nil {
+			return nil, err
+		}
+
+		return next(ctx, req)
+	}
+}
+
+func (c *client) preflightCheck(ctx context.Context) error {
+	p := c.preflight
+	qualifier := c.callConfig(ctx).qualifier
+
+	p.mu.Lock()
+	passedAt, ok := p.passed[qualifier]
+	p.mu.Unlock()
+
+	if ok && time.Since(passedAt) < p.cfg.Interval {
+		return nil
+	}
+
+	reason, err := c.notReadyReason(ctx, qualifier, p.cfg.ProvisionedConcurrency)
+	if err != nil {
+		return fmt.Errorf("preflight: %w", err)
+	}
+
+	if reason != "" {
+		return &FunctionNotReadyError{Function: c.functionARN, Qualifier: qualifier, Reason: reason}
+	}
+
+	p.mu.Lock()
+	p.passed[qualifier] = time.Now()
+	p.mu.Unlock()
+
+	return nil
+}
+
+// notReadyReason returns why the function is not ready, empty if it is.
+func (c *client) notReadyReason(ctx context.Context, qualifier string, provisioned bool) (string, error) {
+	output, err := c.cli.GetFunction(ctx, &lambda.GetFunctionInput{
+		FunctionName: pointer.To(c.functionARN),
+		Qualifier:    pointer.ToStringOrNil(qualifier),
+	}, c.apiOptions()...)
+	if err != nil {
+		return "", fmt.Errorf("cli.GetFunction: %w", c.accessDeniedError(err))
+	}
+
+	if cfg := output.Configuration; cfg != nil && cfg.State != "" && cfg.State != types.StateActive {
+		return fmt.Sprintf("state %s: %s", cfg.State, pointer.Get(cfg.StateReason)), nil
+	}
+
+	if output.Concurrency != nil && output.Concurrency.ReservedConcurrentExecutions != nil && *output.Concurrency.ReservedConcurrentExecutions == 0 {
+		return "reserved concurrency is 0, every invocation is throttled", nil
+	}
+
+	if !provisioned {
+		return "", nil
+	}
+
+	api, ok := c.cli.(provisionedConcurrencyAPI)
+	if !ok {
+		return "", nil
+	}
+
+	if qualifier == "" {
+		return "provisioned concurrency requires a qualifier", nil
+	}
+
+	pc, err := api.GetProvisionedConcurrencyConfig(ctx, &lambda.GetProvisionedConcurrencyConfigInput{
+		FunctionName: pointer.To(c.functionARN),
+		Qualifier:    pointer.To(qualifier),
+	}, c.apiOptions()...)
+	if err != nil {
+		var nf *types.ProvisionedConcurrencyConfigNotFoundException
+		if errors.As(err, &nf) {
+			return "provisioned concurrency is not configured", nil
+		}
+		return "", fmt.Errorf("cli.GetProvisionedConcurrencyConfig: %w", c.accessDeniedError(err))
+	}
+
+	if pc.Status != types.ProvisionedConcurrencyStatusEnumReady {
+		return fmt.Sprintf("provisioned concurrency %s: %s", pc.Status, pointer.Get(pc.StatusReason)), nil
+	}
+
+	if pointer.Get(pc.AvailableProvisionedConcurrentExecutions) == 0 {
+		return "no provisioned concurrency available", nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,82 @@
+package lambda
+
+import (
+	"context"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+)
+
+// preflightAPI is a fakeAPI reporting the configured function state and provisioned concurrency.
+type preflightAPI struct {
+	fakeAPI
+	state       types.State
+	reserved    *int32
+	provisioned *lambda.GetProvisionedConcurrencyConfigOutput
+	checks      atomic.Int32
+}
+
+func (a *preflightAPI) GetFunction(_ context.Context, _ *lambda.GetFunctionInput, _ ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	a.checks.Add(1)
+
+	return &lambda.GetFunctionOutput{
+		Configuration: &types.FunctionConfiguration{State: a.state, StateReason: pointer.To("creating")},
+		Concurrency:   &types.Concurrency{ReservedConcurrentExecutions: a.reserved},
+	}, nil
+}
+
+func (a *preflightAPI) GetProvisionedConcurrencyConfig(context.Context, *lambda.GetProvisionedConcurrencyConfigInput, ...func(*lambda.Options)) (*lambda.GetProvisionedConcurrencyConfigOutput, error) {
+	if a.provisioned == nil {
+		return nil, &types.ProvisionedConcurrencyConfigNotFoundException{}
+	}
+
+	return a.provisioned, nil
+}
+
+func TestWithPreflight(t *testing.T) {
+	api := &preflightAPI{state: types.StatePending}
+	cli := newClient(api, testFunctionARN, WithPreflight(PreflightConfig{}))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.ErrorIs(t, err, ErrFunctionNotReady)
+
+	var fnr *FunctionNotReadyError
+	require.ErrorAs(t, err, &fnr)
+	assert.Equal(t, "state Pending: creating", fnr.Reason)
+	assert.Empty(t, api.calls(), "the function is not invoked")
+
+	api.state = types.StateActive
+	api.reserved = pointer.To(int32(0))
+	_, err = cli.Invoke(_ctx, "GET", "/", nil)
+	require.ErrorContains(t, err, "reserved concurrency is 0")
+
+	api.reserved = nil
+	for range 3 {
+		_, err = cli.Invoke(_ctx, "GET", "/", nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), api.checks.Load(), "passed checks are cached")
+}
+
+func TestWithPreflight_ProvisionedConcurrency(t *testing.T) {
+	api := &preflightAPI{state: types.StateActive}
+	cli := newClient(api, testFunctionARN, WithPreflight(PreflightConfig{ProvisionedConcurrency: true}))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.ErrorContains(t, err, "requires a qualifier")
+
+	_, err = cli.Invoke(_ctx, "GET", "/", nil, WithQualifier("live"))
+	require.ErrorContains(t, err, "provisioned concurrency is not configured")
+
+	api.provisioned = &lambda.GetProvisionedConcurrencyConfigOutput{Status: types.ProvisionedConcurrencyStatusEnumReady, AvailableProvisionedConcurrentExecutions: pointer.To(int32(0))}
+	_, err = cli.Invoke(_ctx, "GET", "/", nil, WithQualifier("live"))
+	require.ErrorContains(t, err, "no provisioned concurrency available")
+
+	api.provisioned.AvailableProvisionedConcurrentExecutions = pointer.To(int32(2))
+	_, err = cli.Invoke(_ctx, "GET", "/", nil, WithQualifier("live"))
+	require.NoError(t, err)
+}
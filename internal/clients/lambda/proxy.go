@@ -0,0 +1,138 @@
+package lambda
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyConfig routes Lambda API calls through an egress proxy, see HTTPTransport.Proxy.
+// Unlike http.ProxyFromEnvironment it does not read HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+type ProxyConfig struct {
+	// HTTPProxy is the proxy URL of http endpoints, e.g. a local emulator.
+	HTTPProxy string
+	// HTTPSProxy is the proxy URL of https endpoints, e.g. "http://proxy.corp:3128".
+	HTTPSProxy string
+	// NoProxy is a comma-separated list of hosts called directly, in NO_PROXY format:
+	// "*", IP addresses, CIDRs such as "10.0.0.0/8", domains matching subdomains too such as "amazonaws.com",
+	// ".amazonaws.com" matching subdomains only, each optionally with a port. Loopback hosts are never proxied.
+	NoProxy string
+}
+
+// proxyFunc returns a http.Transport.Proxy function, invalid proxy URLs are returned as errors of each call.
+func (p ProxyConfig) proxyFunc() func(*http.Request) (*url.URL, error) {
+	noProxy := parseNoProxy(p.NoProxy)
+
+	return func(req *http.Request) (*url.URL, error) {
+		proxy := p.HTTPSProxy
+		if req.URL.Scheme == "http" {
+			proxy = p.HTTPProxy
+		}
+
+		if proxy == "" || !noProxy.useProxy(req.URL) {
+			return nil, nil
+		}
+
+		proxyURL, err := url.Parse(proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL: %s", proxy)
+		}
+
+		return proxyURL, nil
+	}
+}
+
+type noProxyEntry struct {
+	ipNet      *net.IPNet
+	ip         net.IP
+	domain     string
+	subdomains bool
+	port       string
+}
+
+type noProxyList struct {
+	all     bool
+	entries []noProxyEntry
+}
+
+func parseNoProxy(s string) noProxyList {
+	var list noProxyList
+
+	for _, field := range strings.Split(s, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		switch {
+		case field == "":
+			continue
+		case field == "*":
+			list.all = true
+			continue
+		}
+
+		if _, ipNet, err := net.ParseCIDR(field); err == nil {
+			list.entries = append(list.entries, noProxyEntry{ipNet: ipNet})
+			continue
+		}
+
+		var entry noProxyEntry
+		if host, port, err := net.SplitHostPort(field); err == nil {
+			field, entry.port = host, port
+		}
+
+		if ip := net.ParseIP(field); ip != nil {
+			entry.ip = ip
+		} else {
+			field = strings.TrimPrefix(field, "*")
+			entry.subdomains = strings.HasPrefix(field, ".")
+			entry.domain = strings.TrimPrefix(field, ".")
+		}
+
+		list.entries = append(list.entries, entry)
+	}
+
+	return list
+}
+
+func (l noProxyList) useProxy(u *url.URL) bool {
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+
+	ip := net.ParseIP(host)
+	if host == "localhost" || ip != nil && ip.IsLoopback() {
+		return false
+	}
+
+	if l.all {
+		return false
+	}
+
+	for _, e := range l.entries {
+		if e.port != "" && e.port != port {
+			continue
+		}
+
+		switch {
+		case e.ipNet != nil:
+			if ip != nil && e.ipNet.Contains(ip) {
+				return false
+			}
+		case e.ip != nil:
+			if ip != nil && e.ip.Equal(ip) {
+				return false
+			}
+		case e.subdomains:
+			if strings.HasSuffix(host, "."+e.domain) {
+				return false
+			}
+		default:
+			if host == e.domain || strings.HasSuffix(host, "."+e.domain) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
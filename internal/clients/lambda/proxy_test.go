@@ -0,0 +1,78 @@
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestProxyConfig(t *testing.T) {
+	proxy := ProxyConfig{
+		HTTPProxy:  "http://plain.corp:8080",
+		HTTPSProxy: "http://proxy.corp:3128",
+		NoProxy:    "10.0.0.0/8, 192.168.1.1, internal.corp, .vpce.amazonaws.com, *.svc.local, example.org:8443",
+	}.proxyFunc()
+
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://lambda.eu-central-1.amazonaws.com/", want: "http://proxy.corp:3128"},
+		{url: "http://lambda.local:4566/", want: "http://plain.corp:8080"},
+		{url: "https://10.1.2.3/"},
+		{url: "https://192.168.1.1/"},
+		{url: "https://192.168.1.2/", want: "http://proxy.corp:3128"},
+		{url: "https://internal.corp/"},
+		{url: "https://lambda.internal.corp/"},
+		{url: "https://vpce-1.lambda.vpce.amazonaws.com/"},
+		{url: "https://vpce.amazonaws.com/", want: "http://proxy.corp:3128"},
+		{url: "https://svc.local/", want: "http://proxy.corp:3128"},
+		{url: "https://lambda.svc.local/"},
+		{url: "https://example.org:8443/"},
+		{url: "https://example.org/", want: "http://proxy.corp:3128"},
+		{url: "http://localhost:4566/"},
+		{url: "http://127.0.0.1:4566/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			require.NoError(t, err)
+
+			got, err := proxy(&http.Request{URL: u})
+			require.NoError(t, err)
+
+			if tt.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestProxyConfig_All(t *testing.T) {
+	u, err := url.Parse("https://lambda.eu-central-1.amazonaws.com/")
+	require.NoError(t, err)
+
+	got, err := ProxyConfig{HTTPSProxy: "http://proxy.corp:3128", NoProxy: "*"}.proxyFunc()(&http.Request{URL: u})
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	_, err = ProxyConfig{HTTPSProxy: "proxy.corp"}.proxyFunc()(&http.Request{URL: u})
+	require.Error(t, err)
+}
+
+func TestWithHTTPTransport_Proxy(t *testing.T) {
+	var tr http.Transport
+	HTTPTransport{Proxy: &ProxyConfig{HTTPSProxy: "http://proxy.corp:3128"}}.apply(&tr)
+	require.NotNil(t, tr.Proxy)
+
+	u, err := url.Parse("https://lambda.eu-central-1.amazonaws.com/")
+	require.NoError(t, err)
+	got, err := tr.Proxy(&http.Request{URL: u})
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy.corp:3128", got.String())
+}
@@ -0,0 +1,90 @@
+package lambda
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Rand is the source of randomness used for retry jitter, sampling and key-hash routing.
+// It is safe for concurrent use, pass one created with a fixed seed via WithRand
+// to make tests and replay runs reproducible.
+type Rand struct {
+	mu   sync.Mutex
+	rnd  *rand.Rand
+	seed uint64
+}
+
+// NewRand returns a Rand producing the same sequence for the same seed.
+func NewRand(seed uint64) *Rand {
+	return &Rand{
+		rnd:  rand.New(rand.NewPCG(seed, seed)),
+		seed: seed,
+	}
+}
+
+func newRandomRand() *Rand {
+	return NewRand(rand.Uint64())
+}
+
+// WithRand sets the source of randomness, a randomly seeded one is used by default.
+func WithRand(r *Rand) Option {
+	return func(c *client) {
+		c.rand = r
+	}
+}
+
+// Float64 returns a number in [0.0, 1.0).
+func (r *Rand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rnd.Float64()
+}
+
+// IntN returns a number in [0, n), it panics if n <= 0.
+func (r *Rand) IntN(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rnd.IntN(n)
+}
+
+// Uint64 returns a random number.
+func (r *Rand) Uint64() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.rnd.Uint64()
+}
+
+// Sample reports true with the given probability.
+func (r *Rand) Sample(probability float64) bool {
+	return r.Float64() < probability
+}
+
+// Jitter returns a duration in [0, d).
+func (r *Rand) Jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return time.Duration(r.rnd.Int64N(int64(d)))
+}
+
+// Hash returns a hash of key which is stable for the same seed.
+func (r *Rand) Hash(key string) uint64 {
+	var seed [8]byte
+	binary.LittleEndian.PutUint64(seed[:], r.seed)
+
+	h := fnv.New64a()
+	_, _ = h.Write(seed[:])
+	_, _ = h.Write([]byte(key))
+
+	return h.Sum64()
+}
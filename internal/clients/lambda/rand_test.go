@@ -0,0 +1,32 @@
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRand_Deterministic(t *testing.T) {
+	a, b := NewRand(42), NewRand(42)
+
+	for range 10 {
+		assert.Equal(t, a.Float64(), b.Float64())
+		assert.Equal(t, a.IntN(100), b.IntN(100))
+		assert.Equal(t, a.Jitter(time.Second), b.Jitter(time.Second))
+		assert.Equal(t, a.Sample(0.5), b.Sample(0.5))
+	}
+
+	assert.Equal(t, a.Hash("orders"), b.Hash("orders"))
+	assert.NotEqual(t, a.Hash("orders"), NewRand(43).Hash("orders"))
+}
+
+func TestRand_Jitter(t *testing.T) {
+	r := NewRand(1)
+
+	assert.Zero(t, r.Jitter(0))
+	for range 100 {
+		j := r.Jitter(time.Millisecond)
+		assert.GreaterOrEqual(t, j, time.Duration(0))
+		assert.Less(t, j, time.Millisecond)
+	}
+}
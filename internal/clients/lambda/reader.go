@@ -0,0 +1,42 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// InvokeReader is Invoke with the body read from r, e.g. a file or an HTTP request body. Reading stops with
+// PayloadTooLargeError as soon as the body exceeds the sync payload limit, unless WithRequestCompression or
+// WithClaimCheck may bring it under the limit.
+func (c *client) InvokeReader(ctx context.Context, httpMethod, path string, r io.Reader, opts ...Option) (string, error) {
+	body, err := c.readBody(r)
+	if err != nil {
+		return "", fmt.Errorf("readBody: %w", err)
+	}
+
+	return c.Invoke(ctx, httpMethod, path, body, opts...)
+}
+
+// readBody reads r up to the sync payload limit, PayloadTooLargeError.Size is then the number of bytes read,
+// one over the limit.
+func (c *client) readBody(r io.Reader) ([]byte, error) {
+	if c.compressThreshold > 0 || c.claimCheck != nil {
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("io.ReadAll: %w", err)
+		}
+		return body, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r, MaxSyncPayloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+
+	if len(body) > MaxSyncPayloadSize {
+		return nil, &PayloadTooLargeError{Size: len(body), Limit: MaxSyncPayloadSize}
+	}
+
+	return body, nil
+}
@@ -0,0 +1,60 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// endlessReader fails the test if it is read past n bytes.
+type endlessReader struct {
+	t    *testing.T
+	n    int
+	read int
+}
+
+func (r *endlessReader) Read(p []byte) (int, error) {
+	if r.read > r.n {
+		r.t.Fatal("read past the limit")
+	}
+	for i := range p {
+		p[i] = 'x'
+	}
+	r.read += len(p)
+
+	return len(p), nil
+}
+
+func TestInvokeReader(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusOK, strings.ToUpper(proxyRequest(in).Body)), nil
+	}}
+	cli := newClient(api, testFunctionARN)
+
+	got, err := cli.InvokeReader(_ctx, "POST", "/", strings.NewReader("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO", got)
+
+	_, err = cli.InvokeReader(_ctx, "POST", "/", &endlessReader{t: t, n: MaxSyncPayloadSize})
+	var tooLarge *PayloadTooLargeError
+	require.ErrorAs(t, err, &tooLarge)
+	assert.Equal(t, MaxSyncPayloadSize+1, tooLarge.Size)
+	assert.Len(t, api.calls(), 1)
+
+	_, err = cli.InvokeReader(_ctx, "POST", "/", io.MultiReader(strings.NewReader("x"), errReader{}))
+	assert.ErrorIs(t, err, errRead)
+}
+
+var errRead = errors.New("read failed")
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errRead
+}
@@ -0,0 +1,217 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"iter"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// ErrNoFixture is returned by replay clients for requests which were not recorded.
+var ErrNoFixture = errors.New("no recorded fixture")
+
+// Fixture is a recorded invocation, Response is nil if it failed with Error before the function responded.
+type Fixture struct {
+	HTTPMethod string            `json:"httpMethod"`
+	Path       string            `json:"path"`
+	Query      url.Values        `json:"query,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	Async      bool              `json:"async,omitempty"`
+
+	Response  *Response `json:"response,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	ErrorKind ErrorKind `json:"errorKind,omitempty"`
+}
+
+func (f Fixture) key() string {
+	return fixtureKey(f.HTTPMethod, f.Path, f.Query, f.Body, f.Async)
+}
+
+// fixtureKey matches requests by method, path, query, body and invocation type,
+// headers are ignored as they carry per-call values such as trace and correlation IDs.
+func fixtureKey(httpMethod, path string, query url.Values, body string, async bool) string {
+	return httpMethod + " " + path + "?" + query.Encode() + " " + strconv.FormatBool(async) + " " + body
+}
+
+// Recorder captures invocations as fixtures, install it with WithInterceptors(recorder.Interceptor)
+// and call Save when done, e.g. in t.Cleanup.
+type Recorder struct {
+	path string
+
+	mu       sync.Mutex
+	fixtures []Fixture
+}
+
+// NewRecorder returns a recorder saving fixtures to the JSON file at path.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{path: path}
+}
+
+// Interceptor records the request and the response or error of every invocation.
+func (r *Recorder) Interceptor(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+	fixture := Fixture{
+		HTTPMethod: req.HTTPMethod,
+		Path:       req.Path,
+		Query:      req.Query,
+		Headers:    req.Headers,
+		Body:       string(req.Body),
+		Async:      req.Async,
+	}
+
+	resp, err := next(ctx, req)
+	if err != nil {
+		fixture.Error = err.Error()
+		fixture.ErrorKind = KindOf(err)
+	}
+	// proxy responses of failed invocations, e.g. of unexpected status codes, are replayed for the client to fail again
+	if resp != nil && (err == nil || resp.StatusCode != 0) {
+		fixture.Response = cloneResponse(resp)
+	}
+
+	r.mu.Lock()
+	r.fixtures = append(r.fixtures, fixture)
+	r.mu.Unlock()
+
+	return resp, err
+}
+
+// Fixtures returns the invocations recorded so far, in order.
+func (r *Recorder) Fixtures() []Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Fixture(nil), r.fixtures...)
+}
+
+// All yields the invocations recorded so far in order, recorded while iterating are not yielded.
+func (r *Recorder) All() iter.Seq[Fixture] {
+	return slices.Values(r.Fixtures())
+}
+
+// Save writes the recorded fixtures to the file, overwriting it.
+func (r *Recorder) Save() error {
+	data, err := json.MarshalIndent(r.Fixtures(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("json.MarshalIndent: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, data, 0o644); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+
+	return nil
+}
+
+// NewReplayClient returns a client serving the fixtures saved by Recorder without calling AWS.
+// Fixtures are served in place of the Lambda API, so that interceptors and response handling configured by opts
+// run as they did when recording. Only EnvelopeProxy is supported, options changing the sent proxy event body
+// such as WithRequestCompression or WithClaimCheck must not be passed.
+// Requests recorded several times are served in recorded order, the last one repeatedly.
+// Errors are replayed with their message and ErrorKind, unrecorded requests fail with ErrNoFixture.
+func NewReplayClient(path string, opts ...Option) (Client, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	var fixtures []Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal[%s]: %w", path, err)
+	}
+
+	api := &replayAPI{fixtures: make(map[string][]Fixture)}
+	for _, f := range fixtures {
+		api.fixtures[f.key()] = append(api.fixtures[f.key()], f)
+	}
+
+	return newClient(api, replayFunctionARN, opts...), nil
+}
+
+const replayFunctionARN = "arn:aws:lambda:eu-central-1:000000000000:function:replay"
+
+// replayAPI serves Invoke from fixtures, other calls are not supported.
+type replayAPI struct {
+	lambdaAPI
+
+	mu       sync.Mutex
+	fixtures map[string][]Fixture
+}
+
+func (a *replayAPI) Invoke(_ context.Context, in *lambda.InvokeInput, _ ...func(*lambda.Options)) (*lambda.InvokeOutput, error) {
+	var event events.APIGatewayProxyRequest
+	if err := json.Unmarshal(in.Payload, &event); err != nil {
+		return nil, fmt.Errorf("%w: json.Unmarshal: %w", ErrNoFixture, err)
+	}
+
+	query := url.Values(event.MultiValueQueryStringParameters)
+	if query == nil && len(event.QueryStringParameters) > 0 {
+		query = make(url.Values, len(event.QueryStringParameters))
+		for name, value := range event.QueryStringParameters {
+			query.Set(name, value)
+		}
+	}
+
+	async := in.InvocationType == types.InvocationTypeEvent
+	key := fixtureKey(event.HTTPMethod, event.Path, query, event.Body, async)
+
+	a.mu.Lock()
+	fixtures := a.fixtures[key]
+	if len(fixtures) == 0 {
+		a.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s %s", ErrNoFixture, event.HTTPMethod, event.Path)
+	}
+
+	f := fixtures[0]
+	if len(fixtures) > 1 {
+		a.fixtures[key] = fixtures[1:]
+	}
+	a.mu.Unlock()
+
+	if f.Response == nil && f.Error != "" {
+		return nil, withKind(f.ErrorKind, errors.New(f.Error))
+	}
+
+	if async {
+		return &lambda.InvokeOutput{StatusCode: http.StatusAccepted}, nil
+	}
+
+	resp := f.Response
+	if resp == nil {
+		resp = &Response{StatusCode: http.StatusOK}
+	}
+
+	payload, err := json.Marshal(events.APIGatewayProxyResponse{
+		StatusCode:        resp.StatusCode,
+		Headers:           resp.Headers,
+		MultiValueHeaders: resp.MultiValueHeaders,
+		Body:              resp.Body,
+		IsBase64Encoded:   resp.IsBase64Encoded,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	output := &lambda.InvokeOutput{
+		StatusCode:      http.StatusOK,
+		Payload:         payload,
+		ExecutedVersion: pointer.ToStringOrNil(resp.ExecutedVersion),
+	}
+	if resp.LogResult != "" {
+		output.LogResult = pointer.To(base64.StdEncoding.EncodeToString([]byte(resp.LogResult)))
+	}
+
+	return output, nil
+}
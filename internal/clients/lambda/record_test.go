@@ -0,0 +1,65 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	var n int
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		n++
+		if proxyRequest(in).Path == "/missing" {
+			return proxyOutput(in, http.StatusNotFound, "not found"), nil
+		}
+		return proxyOutput(in, http.StatusOK, "order "+string(rune('0'+n))), nil
+	}}
+
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	recorder := NewRecorder(path)
+	cli := newClient(api, testFunctionARN, WithInterceptors(recorder.Interceptor))
+
+	for range 2 {
+		_, err := cli.Invoke(_ctx, "GET", "/orders/1", nil)
+		require.NoError(t, err)
+	}
+	_, err := cli.Invoke(_ctx, "GET", "/missing", nil)
+	require.Error(t, err)
+	require.NoError(t, cli.InvokeAsync(_ctx, "POST", "/events", []byte(`{"id":1}`)))
+
+	require.Len(t, recorder.Fixtures(), 4)
+	assert.Equal(t, recorder.Fixtures(), slices.Collect(recorder.All()))
+	require.NoError(t, recorder.Save())
+
+	var intercepted int
+	count := func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		intercepted++
+		return next(ctx, req)
+	}
+
+	replay, err := NewReplayClient(path, WithInterceptors(count))
+	require.NoError(t, err)
+
+	for _, want := range []string{"order 1", "order 2", "order 2"} {
+		body, err := replay.Invoke(_ctx, "GET", "/orders/1", nil)
+		require.NoError(t, err)
+		assert.Equal(t, want, body)
+	}
+
+	_, err = replay.Invoke(_ctx, "GET", "/missing", nil)
+	assert.Equal(t, ErrorKindBadStatus, KindOf(err))
+
+	require.NoError(t, replay.InvokeAsync(_ctx, "POST", "/events", []byte(`{"id":1}`)))
+
+	_, err = replay.Invoke(_ctx, "GET", "/orders/2", nil)
+	require.ErrorIs(t, err, ErrNoFixture)
+
+	assert.Equal(t, 4, n, "replay does not call AWS")
+	assert.Equal(t, 6, intercepted, "interceptors run on replay")
+}
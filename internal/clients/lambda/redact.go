@@ -0,0 +1,58 @@
+package lambda
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+)
+
+// accountIDPattern matches 12-digit AWS account IDs, bare or within ARNs.
+var accountIDPattern = regexp.MustCompile(`\b\d{12}\b`)
+
+// RedactAccountIDs replaces AWS account IDs in s with "acct-" and a short hash of the ID,
+// so that redacted strings stay correlatable without exposing the account.
+func RedactAccountIDs(s string) string {
+	return accountIDPattern.ReplaceAllStringFunc(s, func(id string) string {
+		sum := sha256.Sum256([]byte(id))
+		return "acct-" + hex.EncodeToString(sum[:4])
+	})
+}
+
+// WithRedaction masks account IDs, including those within ARNs, in returned errors, WithLogger logs,
+// metrics and pprof labels, trace attributes and hook events. errors.Is and errors.As still see the original errors.
+func WithRedaction() Option {
+	return func(c *client) {
+		c.redaction = true
+	}
+}
+
+// redact applies RedactAccountIDs if redaction is configured.
+func (c *client) redact(s string) string {
+	if !c.redaction {
+		return s
+	}
+
+	return RedactAccountIDs(s)
+}
+
+// redactedError masks account IDs in the message of the wrapped error.
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return RedactAccountIDs(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactError wraps err if redaction is configured.
+func (c *client) redactError(err error) error {
+	if !c.redaction || err == nil {
+		return err
+	}
+
+	return &redactedError{err: err}
+}
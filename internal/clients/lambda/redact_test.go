@@ -0,0 +1,52 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"testing"
+)
+
+func TestRedactAccountIDs(t *testing.T) {
+	redacted := RedactAccountIDs("arn:aws:lambda:us-east-1:000000000000:function:fn, account 000000000000, id 1234567890123")
+
+	assert.NotContains(t, redacted, "000000000000")
+	assert.Contains(t, redacted, "arn:aws:lambda:us-east-1:acct-")
+	assert.Contains(t, redacted, ":function:fn")
+	assert.Contains(t, redacted, "1234567890123", "only 12-digit numbers are account IDs")
+	assert.Equal(t, redacted, RedactAccountIDs("arn:aws:lambda:us-east-1:000000000000:function:fn, account 000000000000, id 1234567890123"))
+}
+
+func TestWithRedaction(t *testing.T) {
+	errBoom := errors.New("boom for " + testFunctionARN)
+	api := &fakeAPI{invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, errBoom
+	}}
+
+	var buf bytes.Buffer
+	var events []Event
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	cli := newClient(api, testFunctionARN,
+		WithLogger(logger),
+		WithEventSink(func(_ context.Context, e Event) { events = append(events, e) }),
+		WithRedaction(),
+	)
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.ErrorIs(t, err, errBoom)
+
+	assert.NotContains(t, err.Error(), "000000000000")
+	assert.NotContains(t, buf.String(), "000000000000")
+	require.Len(t, events, 2)
+	for _, e := range events {
+		assert.NotContains(t, e.Header().FunctionARN, "000000000000")
+	}
+	assert.NotContains(t, events[1].(InvocationFinished).Error, "000000000000")
+
+	// the invoked function is not affected
+	assert.Equal(t, testFunctionARN, *api.calls()[0].FunctionName)
+}
@@ -0,0 +1,143 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// ErrUnknownFunction is returned by Registry for names which were not registered.
+var ErrUnknownFunction = errors.New("unknown function")
+
+// Registry holds clients of many named functions backed by a shared *lambda.Client.
+type Registry struct {
+	cli  lambdaAPI
+	opts []Option
+
+	mu      sync.RWMutex
+	clients map[string]Client
+}
+
+// NewRegistry returns an empty registry, opts are applied to every registered function.
+func NewRegistry(cli *lambda.Client, opts ...Option) (*Registry, error) {
+	if cli == nil {
+		return nil, fmt.Errorf("lambda.NewFromConfig returned nil")
+	}
+
+	return newRegistry(cli, opts...), nil
+}
+
+func newRegistry(cli lambdaAPI, opts ...Option) *Registry {
+	return &Registry{
+		cli:     cli,
+		opts:    opts,
+		clients: make(map[string]Client),
+	}
+}
+
+// Register adds or replaces the named function, opts are applied after the registry wide ones.
+func (r *Registry) Register(name, functionARN string, opts ...Option) error {
+	c := newClient(r.cli, functionARN, append(slices.Clone(r.opts), opts...)...)
+
+	if err := validateFunction(functionARN, c.strictARN); err != nil {
+		return fmt.Errorf("validateFunction: %w", err)
+	}
+
+	if err := c.validateAccount(); err != nil {
+		return fmt.Errorf("c.validateAccount: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clients[name] = c
+
+	return nil
+}
+
+// Client returns the client of the named function.
+func (r *Registry) Client(name string) (Client, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFunction, name)
+	}
+
+	return c, nil
+}
+
+// Names returns the sorted names of registered functions.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(r.clients))
+}
+
+// Unregister removes the named function, it is a no-op for unknown names.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.clients, name)
+}
+
+func (r *Registry) Invoke(ctx context.Context, name, httpMethod, path string, body []byte, opts ...Option) (string, error) {
+	c, err := r.Client(name)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := c.Invoke(ctx, httpMethod, path, body, opts...)
+	if err != nil {
+		return "", fmt.Errorf("registry[%s]: %w", name, err)
+	}
+
+	return out, nil
+}
+
+func (r *Registry) InvokeAsync(ctx context.Context, name, httpMethod, path string, body []byte, opts ...Option) error {
+	c, err := r.Client(name)
+	if err != nil {
+		return err
+	}
+
+	if err := c.InvokeAsync(ctx, httpMethod, path, body, opts...); err != nil {
+		return fmt.Errorf("registry[%s]: %w", name, err)
+	}
+
+	return nil
+}
+
+func (r *Registry) Do(ctx context.Context, name string, req Request, opts ...Option) (*Response, error) {
+	c, err := r.Client(name)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(ctx, req, opts...)
+	if err != nil {
+		return resp, fmt.Errorf("registry[%s]: %w", name, err)
+	}
+
+	return resp, nil
+}
+
+func (r *Registry) Call(ctx context.Context, name, httpMethod, path string, in, out any, opts ...Option) error {
+	c, err := r.Client(name)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Call(ctx, httpMethod, path, in, out, opts...); err != nil {
+		return fmt.Errorf("registry[%s]: %w", name, err)
+	}
+
+	return nil
+}
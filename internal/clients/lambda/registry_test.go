@@ -0,0 +1,40 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	const ordersARN = "arn:aws:lambda:eu-central-1:000000000000:function:orders"
+	const paymentsARN = "arn:aws:lambda:eu-central-1:000000000000:function:payments"
+
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusOK, `{"function":"`+*in.FunctionName+`"}`), nil
+	}}
+	registry := newRegistry(api, WithAcceptStatuses(http.StatusOK))
+
+	require.NoError(t, registry.Register("orders", ordersARN))
+	require.NoError(t, registry.Register("payments", paymentsARN))
+	require.Error(t, registry.Register("bad", "not a function"))
+
+	var out struct {
+		Function string `json:"function"`
+	}
+	require.NoError(t, registry.Call(_ctx, "payments", "POST", "/charges", map[string]int{"amount": 1}, &out))
+	assert.Equal(t, paymentsARN, out.Function)
+
+	resp, err := registry.Do(_ctx, "orders", Request{HTTPMethod: "GET", Path: "/orders/1"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	registry.Unregister("orders")
+	assert.Equal(t, []string{"payments"}, registry.Names())
+
+	_, err = registry.Do(_ctx, "orders", Request{HTTPMethod: "GET", Path: "/orders/1"})
+	assert.ErrorIs(t, err, ErrUnknownFunction)
+}
@@ -0,0 +1,64 @@
+package lambda
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var reportFieldPattern = regexp.MustCompile(`([A-Za-z ]+): ([0-9.]+) (ms|MB)`)
+
+// ExecutionStats are the execution statistics of the REPORT line of the log tail, see WithLogType.
+type ExecutionStats struct {
+	Duration       time.Duration
+	BilledDuration time.Duration
+	// MemorySize is the configured memory in MB.
+	MemorySize int
+	// MaxMemoryUsed is the peak memory usage in MB.
+	MaxMemoryUsed int
+	// InitDuration is only reported for invocations which initialised a new instance.
+	InitDuration time.Duration
+}
+
+// parseReport parses the REPORT line of the log tail, it returns nil if there is no REPORT line.
+// The tail is truncated to the last 4KB of the log, the REPORT line is normally the last one.
+func parseReport(logResult string) *ExecutionStats {
+	for _, line := range strings.Split(logResult, "\n") {
+		if !strings.HasPrefix(line, "REPORT ") {
+			continue
+		}
+
+		stats := &ExecutionStats{}
+		for _, m := range reportFieldPattern.FindAllStringSubmatch(line, -1) {
+			name, value := strings.TrimSpace(m[1]), m[2]
+
+			switch name {
+			case "Duration":
+				stats.Duration = parseMillis(value)
+			case "Billed Duration":
+				stats.BilledDuration = parseMillis(value)
+			case "Memory Size":
+				stats.MemorySize, _ = strconv.Atoi(value)
+			case "Max Memory Used":
+				stats.MaxMemoryUsed, _ = strconv.Atoi(value)
+			case "Init Duration":
+				stats.InitDuration = parseMillis(value)
+			}
+		}
+
+		return stats
+	}
+
+	return nil
+}
+
+// parseMillis parses a decimal number of milliseconds, it returns zero if invalid.
+func parseMillis(s string) time.Duration {
+	ms, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(ms * float64(time.Millisecond))
+}
@@ -0,0 +1,62 @@
+package lambda
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const (
+	warmReport = "REPORT RequestId: 1\tDuration: 12.34 ms\tBilled Duration: 13 ms\tMemory Size: 128 MB\tMax Memory Used: 64 MB\t\n"
+	coldReport = "REPORT RequestId: 1\tDuration: 12.34 ms\tBilled Duration: 163 ms\tMemory Size: 128 MB\tMax Memory Used: 64 MB\tInit Duration: 150.5 ms\t\n"
+)
+
+func TestParseReport(t *testing.T) {
+	stats := parseReport("START RequestId: 1\nEND RequestId: 1\n" + coldReport)
+	require.NotNil(t, stats)
+	assert.Equal(t, ExecutionStats{
+		Duration:       12340 * time.Microsecond,
+		BilledDuration: 163 * time.Millisecond,
+		MemorySize:     128,
+		MaxMemoryUsed:  64,
+		InitDuration:   150500 * time.Microsecond,
+	}, *stats)
+
+	stats = parseReport(warmReport)
+	require.NotNil(t, stats)
+	assert.Equal(t, 13*time.Millisecond, stats.BilledDuration)
+	assert.Zero(t, stats.InitDuration)
+
+	assert.Nil(t, parseReport("START RequestId: 1\nEND RequestId: 1\n"))
+	assert.Nil(t, parseReport(""))
+}
+
+func TestExecutionStats(t *testing.T) {
+	report := coldReport
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		out := proxyOutput(in, http.StatusOK, "ok")
+		out.LogResult = pointer.To(base64.StdEncoding.EncodeToString([]byte(report)))
+		return out, nil
+	}}
+	cli := newClient(api, testFunctionARN, WithLogType(types.LogTypeTail))
+
+	resp, err := cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/"})
+	require.NoError(t, err)
+	assert.True(t, resp.ColdStart)
+	assert.Equal(t, 150500*time.Microsecond, resp.InitDuration)
+	require.NotNil(t, resp.Stats)
+	assert.Equal(t, 128, resp.Stats.MemorySize)
+
+	report = warmReport
+	resp, err = cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/"})
+	require.NoError(t, err)
+	assert.False(t, resp.ColdStart)
+	assert.Zero(t, resp.InitDuration)
+}
@@ -0,0 +1,78 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"maps"
+)
+
+// EventBuilder enriches every proxy event before it is marshaled, e.g. with tenant IDs, auth context or
+// synthetic request IDs. Request compression and claim check apply after it.
+type EventBuilder func(ctx context.Context, event *events.APIGatewayProxyRequest)
+
+// WithEventBuilders appends builders run in order after the event is built from the request and the options.
+func WithEventBuilders(builders ...EventBuilder) Option {
+	return func(c *client) {
+		c.eventBuilders = append(c.eventBuilders, builders...)
+	}
+}
+
+// WithAuthorizerContext adds entries to RequestContext.Authorizer of the proxy event, they take precedence over the
+// context returned by WithAuthorizer.
+func WithAuthorizerContext(authorizer map[string]any) Option {
+	return func(c *client) {
+		out := maps.Clone(c.call.authorizerContext)
+		if out == nil {
+			out = make(map[string]any, len(authorizer))
+		}
+		maps.Copy(out, authorizer)
+		c.call.authorizerContext = out
+	}
+}
+
+// WithClaims sets the claims of the authorizer context, as a Cognito user pool authorizer does,
+// e.g. map[string]any{"sub": "123", "email": "a@example.com"}.
+func WithClaims(claims map[string]any) Option {
+	return WithAuthorizerContext(map[string]any{"claims": claims})
+}
+
+// WithIdentity sets RequestContext.Identity of the proxy event, e.g. Cognito identity fields and the source IP.
+func WithIdentity(identity events.APIGatewayRequestIdentity) Option {
+	return func(c *client) {
+		c.call.identity = identity
+	}
+}
+
+// WithSourceIP sets RequestContext.Identity.SourceIP of the proxy event.
+func WithSourceIP(ip string) Option {
+	return func(c *client) {
+		c.call.identity.SourceIP = ip
+	}
+}
+
+// WithStageVariables adds stage variables to the proxy event, e.g. map[string]string{"env": "prod"}.
+func WithStageVariables(variables map[string]string) Option {
+	return func(c *client) {
+		out := maps.Clone(c.call.stageVariables)
+		if out == nil {
+			out = make(map[string]string, len(variables))
+		}
+		maps.Copy(out, variables)
+		c.call.stageVariables = out
+	}
+}
+
+// withAuthorizerContext returns authorizer merged with the configured context, the maps are not modified.
+func (cfg callConfig) withAuthorizerContext(authorizer map[string]any) map[string]any {
+	if len(cfg.authorizerContext) == 0 {
+		return authorizer
+	}
+
+	out := maps.Clone(authorizer)
+	if out == nil {
+		out = make(map[string]any, len(cfg.authorizerContext))
+	}
+	maps.Copy(out, cfg.authorizerContext)
+
+	return out
+}
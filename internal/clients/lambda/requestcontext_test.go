@@ -0,0 +1,89 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestRequestContext(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		req := proxyRequest(in)
+
+		assert.Equal(t, map[string]any{
+			"principalId": "user-1",
+			"tenant":      "acme",
+			"claims":      map[string]any{"sub": "123"},
+		}, req.RequestContext.Authorizer)
+		assert.Equal(t, "eu-west-1:abc", req.RequestContext.Identity.CognitoIdentityID)
+		assert.Equal(t, "203.0.113.7", req.RequestContext.Identity.SourceIP)
+		assert.Equal(t, map[string]string{"env": "prod", "table": "orders"}, req.StageVariables)
+
+		return proxyOutput(in, http.StatusOK, ""), nil
+	}}
+
+	authorizer := AuthorizerFunc(func(context.Context, Request) (map[string]any, error) {
+		return map[string]any{"principalId": "user-1", "tenant": "default"}, nil
+	})
+
+	c := newClient(api, testFunctionARN, WithAuthorizer(authorizer), WithAuthorizerContext(map[string]any{"tenant": "acme"}),
+		WithStageVariables(map[string]string{"env": "prod"}))
+
+	_, err := c.Do(_ctx, Request{HTTPMethod: http.MethodGet},
+		WithClaims(map[string]any{"sub": "123"}),
+		WithIdentity(events.APIGatewayRequestIdentity{CognitoIdentityID: "eu-west-1:abc"}),
+		WithSourceIP("203.0.113.7"),
+		WithStageVariables(map[string]string{"table": "orders"}))
+	require.NoError(t, err)
+}
+
+func TestWithEventBuilders(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		req := proxyRequest(in)
+
+		assert.Equal(t, "acme", req.Headers["X-Tenant-Id"])
+		assert.Equal(t, "synthetic-1", req.RequestContext.RequestID)
+
+		return proxyOutput(in, http.StatusOK, ""), nil
+	}}
+
+	c := newClient(api, testFunctionARN, WithEventBuilders(
+		func(_ context.Context, event *events.APIGatewayProxyRequest) {
+			event.Headers["X-Tenant-Id"] = "acme"
+		},
+		func(_ context.Context, event *events.APIGatewayProxyRequest) {
+			event.RequestContext.RequestID = "synthetic-1"
+		},
+	))
+
+	headers := map[string]string{"Accept": "application/json"}
+	_, err := c.Do(_ctx, Request{HTTPMethod: http.MethodGet, Headers: headers})
+	require.NoError(t, err)
+
+	assert.NotContains(t, headers, "X-Tenant-Id")
+
+	_, err = c.Do(_ctx, Request{HTTPMethod: http.MethodGet})
+	require.NoError(t, err)
+}
+
+func TestWithEventBuilders_RetainedBody(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusOK, ""), nil
+	}}
+
+	var retained string
+	c := newClient(api, testFunctionARN, WithEventBuilders(func(_ context.Context, event *events.APIGatewayProxyRequest) {
+		retained = event.Body
+	}))
+
+	body := []byte("order-1")
+	_, err := c.Do(_ctx, Request{HTTPMethod: http.MethodPost, Body: body})
+	require.NoError(t, err)
+
+	copy(body, "order-2")
+	assert.Equal(t, "order-1", retained, "the event body does not alias Request.Body")
+}
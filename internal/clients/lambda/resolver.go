@@ -0,0 +1,109 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"sync"
+	"time"
+)
+
+// FunctionResolver resolves the ARN of the function to invoke, e.g. *TagResolver. Resolve is called on every
+// invocation and should cache, Invalidate drops the cached ARN.
+type FunctionResolver interface {
+	Resolve(ctx context.Context) (string, error)
+	Invalidate()
+}
+
+// WithResolver invokes the ARN resolved by r instead of the function given to New, lazily on first use.
+// When the Invoke API rejects the ARN as not found, e.g. the function was re-created under another
+// name, r is invalidated and the invocation is retried once with the re-resolved ARN.
+func WithResolver(r FunctionResolver) Option {
+	return func(c *client) {
+		c.resolver = r
+	}
+}
+
+// WithARNRefresh resolves the function name or alias given to New to the full ARN with GetFunction lazily on
+// first use and again every interval, if not 0, and on errors as WithResolver does.
+func WithARNRefresh(interval time.Duration) Option {
+	return func(c *client) {
+		c.resolver = &nameResolver{client: c, interval: interval}
+	}
+}
+
+// nameResolver is the FunctionResolver of WithARNRefresh.
+type nameResolver struct {
+	client   *client
+	interval time.Duration
+
+	mu         sync.Mutex
+	arn        string
+	resolvedAt time.Time
+}
+
+func (r *nameResolver) Resolve(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.arn != "" && (r.interval <= 0 || time.Since(r.resolvedAt) < r.interval) {
+		return r.arn, nil
+	}
+
+	arn, err := r.client.getFunctionARN(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	r.arn, r.resolvedAt = arn, time.Now()
+
+	return arn, nil
+}
+
+func (r *nameResolver) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.arn = ""
+}
+
+// invokeResolved calls the Invoke API with the function name set to the configured or the resolved function.
+func (c *client) invokeResolved(ctx context.Context, in *lambda.InvokeInput, optFns []func(*lambda.Options)) (*lambda.InvokeOutput, error) {
+	if c.resolver == nil {
+		in.FunctionName = pointer.To(c.functionARN)
+		return c.cli.Invoke(ctx, in, optFns...)
+	}
+
+	arn, err := c.resolver.Resolve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolver.Resolve: %w", err)
+	}
+
+	in.FunctionName = pointer.To(arn)
+	output, err := c.cli.Invoke(ctx, in, optFns...)
+	if err == nil || !isStaleFunction(err) {
+		return output, err
+	}
+
+	c.resolver.Invalidate()
+
+	fresh, resolveErr := c.resolver.Resolve(ctx)
+	if resolveErr != nil || fresh == arn {
+		return output, err
+	}
+
+	retry := *in
+	retry.FunctionName = pointer.To(fresh)
+
+	return c.cli.Invoke(ctx, &retry, optFns...)
+}
+
+// isStaleFunction reports whether err rejects the function name, as after the function was deleted.
+// InvalidParameterValueException is not stale, as it is mostly about the payload or other parameters.
+func isStaleFunction(err error) bool {
+	var rnf *types.ResourceNotFoundException
+	return errors.As(err, &rnf)
+}
@@ -0,0 +1,103 @@
+package lambda
+
+import (
+	"context"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recreatedAPI is a fakeAPI serving the function under the current ARN only, as after a re-creation.
+type recreatedAPI struct {
+	fakeAPI
+	current atomic.Pointer[string]
+	gets    atomic.Int32
+}
+
+func (a *recreatedAPI) GetFunction(context.Context, *lambda.GetFunctionInput, ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	a.gets.Add(1)
+
+	return &lambda.GetFunctionOutput{Configuration: &types.FunctionConfiguration{FunctionArn: a.current.Load()}}, nil
+}
+
+func newRecreatedAPI(arn string) *recreatedAPI {
+	a := &recreatedAPI{}
+	a.current.Store(pointer.To(arn))
+	a.invoke = func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		if *in.FunctionName != *a.current.Load() {
+			return nil, &types.ResourceNotFoundException{Message: pointer.To("Function not found: " + *in.FunctionName)}
+		}
+		return proxyOutput(in, 200, "ok"), nil
+	}
+
+	return a
+}
+
+func TestWithARNRefresh(t *testing.T) {
+	api := newRecreatedAPI(testFunctionARN)
+	cli := newClient(api, "my-function", WithARNRefresh(0))
+	assert.Zero(t, api.gets.Load(), "resolved lazily")
+
+	for range 3 {
+		_, err := cli.Invoke(_ctx, "GET", "/", nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), api.gets.Load())
+	assert.Equal(t, testFunctionARN, *api.calls()[0].FunctionName)
+
+	recreated := testFunctionARN + "-v2"
+	api.current.Store(pointer.To(recreated))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err, "retried with the re-resolved ARN")
+	assert.Equal(t, recreated, *api.calls()[4].FunctionName)
+
+	arn, err := cli.FunctionARN(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, recreated, arn)
+}
+
+func TestWithARNRefresh_Interval(t *testing.T) {
+	api := newRecreatedAPI(testFunctionARN)
+	cli := newClient(api, "my-function", WithARNRefresh(time.Millisecond))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+
+	time.Sleep(2 * time.Millisecond)
+
+	_, err = cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), api.gets.Load())
+}
+
+func TestWithResolver_NotFound(t *testing.T) {
+	api := newRecreatedAPI("arn:aws:lambda:eu-central-1:000000000000:function:gone")
+	discovery := &fakeDiscovery{functions: map[string]map[string]string{testFunctionARN: {"service": "orders"}}, order: []string{testFunctionARN}}
+	cli := newClient(api, testFunctionARN, WithResolver(newTagResolver(discovery, map[string]string{"service": "orders"}, 0)))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	var rnf *types.ResourceNotFoundException
+	require.ErrorAs(t, err, &rnf, "the same ARN is not retried")
+	assert.Len(t, api.calls(), 1)
+	assert.Equal(t, 2, discovery.lists, "re-resolved once")
+}
+
+func TestWithResolver_InvalidParameter(t *testing.T) {
+	api := newRecreatedAPI(testFunctionARN)
+	api.invoke = func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, &types.InvalidParameterValueException{Message: pointer.To("Invalid payload")}
+	}
+	cli := newClient(api, "my-function", WithARNRefresh(0))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	var ipv *types.InvalidParameterValueException
+	require.ErrorAs(t, err, &ipv)
+	assert.Equal(t, int32(1), api.gets.Load(), "not re-resolved")
+	assert.Len(t, api.calls(), 1)
+}
@@ -0,0 +1,107 @@
+package lambda
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 100 * time.Millisecond
+	defaultRetryMaxDelay  = 5 * time.Second
+)
+
+// RetryPolicy retries failed invocations with exponential backoff and jitter,
+// on top of the retries of the AWS SDK which only cover the API call itself.
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt, values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, 100ms by default, it doubles for every next retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay, 5s by default.
+	MaxDelay time.Duration
+	// Retryable decides whether err is retried, IsRetryable by default.
+	Retryable func(err error) bool
+}
+
+// WithRetryPolicy sets the retry policy, retries are scheduled inside interceptors, so that they observe one invocation.
+// Every retry is logged at warn level with WithLogger and emitted as RetryScheduled with WithEventSink.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *client) {
+		if p.BaseDelay <= 0 {
+			p.BaseDelay = defaultRetryBaseDelay
+		}
+		if p.MaxDelay <= 0 {
+			p.MaxDelay = defaultRetryMaxDelay
+		}
+		if p.Retryable == nil {
+			p.Retryable = IsRetryable
+		}
+		c.retryPolicy = &p
+	}
+}
+
+// IsRetryable reports throttling and transport errors, function errors and bad statuses are not retried
+// as the function may have had side effects.
+func IsRetryable(err error) bool {
+	switch KindOf(err) {
+	case ErrorKindThrottled, ErrorKindTransport:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *client) retrying(next Invoker) Invoker {
+	p := c.retryPolicy
+
+	return func(ctx context.Context, req *Request) (*Response, error) {
+		for attempt := 1; ; attempt++ {
+			resp, err := next(ctx, req)
+			if err == nil || attempt >= p.MaxAttempts || !p.Retryable(err) {
+				return resp, err
+			}
+
+			delay := c.backoff(attempt)
+
+			c.emit(ctx, RetryScheduled{
+				EventHeader: c.eventHeader(EventRetryScheduled),
+				HTTPMethod:  req.HTTPMethod,
+				Path:        req.Path,
+				Attempt:     attempt,
+				Delay:       delay,
+				Error:       c.redact(err.Error()),
+			})
+
+			if c.logger != nil {
+				c.logger.WarnContext(ctx, "lambda invocation retry",
+					slog.String("method", req.HTTPMethod),
+					slog.String("path", req.Path),
+					slog.Int("attempt", attempt),
+					slog.Duration("delay", delay),
+					slog.Any("error", c.redactError(err)),
+				)
+			}
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return resp, err
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+// backoff returns the delay after the given attempt: half of the exponential delay plus jitter of the other half.
+func (c *client) backoff(attempt int) time.Duration {
+	p := c.retryPolicy
+
+	d := p.MaxDelay
+	if shift := attempt - 1; shift < 32 && p.BaseDelay<<shift < p.MaxDelay {
+		d = p.BaseDelay << shift
+	}
+
+	return d/2 + c.rand.Jitter(d/2)
+}
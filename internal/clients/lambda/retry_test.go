@@ -0,0 +1,93 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithRetryPolicy(t *testing.T) {
+	var attempts atomic.Int32
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		if attempts.Add(1) < 3 {
+			return nil, fmt.Errorf("operation error: %w", &types.TooManyRequestsException{})
+		}
+		return proxyOutput(in, http.StatusOK, "ok"), nil
+	}}
+
+	var buf bytes.Buffer
+	var retries []RetryScheduled
+	var invocations int
+	cli := newClient(api, testFunctionARN,
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+		WithEventSink(func(_ context.Context, e Event) {
+			switch e := e.(type) {
+			case RetryScheduled:
+				retries = append(retries, e)
+			case InvocationFinished:
+				invocations++
+			}
+		}),
+	)
+
+	body, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", body)
+
+	require.Len(t, retries, 2)
+	assert.Equal(t, 1, retries[0].Attempt)
+	assert.Equal(t, 2, retries[1].Attempt)
+	assert.Equal(t, 1, invocations, "interceptors observe one invocation")
+	assert.Contains(t, buf.String(), `"level":"WARN","msg":"lambda invocation retry"`)
+}
+
+func TestWithRetryPolicy_NotRetryable(t *testing.T) {
+	var attempts atomic.Int32
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		attempts.Add(1)
+		return proxyOutput(in, http.StatusInternalServerError, "oops"), nil
+	}}
+	cli := newClient(api, testFunctionARN, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+
+	_, err := cli.Invoke(_ctx, "GET", "/", nil)
+	require.Error(t, err)
+	assert.Equal(t, int32(1), attempts.Load())
+}
+
+func TestWithRetryPolicy_Context(t *testing.T) {
+	errTransport := errors.New("connection reset")
+	api := &fakeAPI{invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, errTransport
+	}}
+	cli := newClient(api, testFunctionARN, WithRetryPolicy(RetryPolicy{MaxAttempts: 10, BaseDelay: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(_ctx, 10*time.Millisecond)
+	defer cancel()
+
+	_, err := cli.Invoke(ctx, "GET", "/", nil)
+	assert.ErrorIs(t, err, errTransport)
+	assert.Len(t, api.calls(), 1)
+}
+
+func TestClient_Backoff(t *testing.T) {
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithRand(NewRand(1)),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}))
+
+	for attempt, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		want *= time.Millisecond
+		d := cli.backoff(attempt + 1)
+		assert.GreaterOrEqual(t, d, want/2)
+		assert.Less(t, d, want)
+	}
+}
@@ -0,0 +1,92 @@
+package lambda
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// ErrUnknownHost is returned by RoundTripper for hosts without a function and no fallback transport.
+var ErrUnknownHost = errors.New("unknown host")
+
+// RoundTripper is an http.RoundTripper invoking functions for requests to their virtual hosts, so that http.Client
+// users such as generated OpenAPI clients target a function with a base URL like "http://orders.lambda".
+// Proxy responses of any status are returned as *http.Response, failed invocations as errors.
+type RoundTripper struct {
+	hosts    map[string]Client
+	fallback http.RoundTripper
+}
+
+// NewRoundTripper returns a round tripper sending requests to other hosts with fallback, e.g. http.DefaultTransport.
+// Requests to other hosts fail if fallback is nil.
+func NewRoundTripper(fallback http.RoundTripper) *RoundTripper {
+	return &RoundTripper{hosts: make(map[string]Client), fallback: fallback}
+}
+
+// Handle registers the function serving requests to the host, with or without a port.
+// It is not safe for concurrent use with RoundTrip.
+func (t *RoundTripper) Handle(host string, target Client) {
+	t.hosts[host] = target
+}
+
+func (t *RoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	target, ok := t.hosts[r.URL.Host]
+	if !ok {
+		target, ok = t.hosts[r.URL.Hostname()]
+	}
+	if !ok {
+		if t.fallback != nil {
+			return t.fallback.RoundTrip(r)
+		}
+		closeBody(r)
+		return nil, fmt.Errorf("%w: %s", ErrUnknownHost, r.URL.Host)
+	}
+
+	req, err := newHTTPRequest(r, "", MaxSyncPayloadSize)
+	closeBody(r)
+	if err != nil {
+		return nil, fmt.Errorf("newHTTPRequest: %w", err)
+	}
+
+	resp, err := target.Do(r.Context(), req)
+
+	if err != nil && !responded(resp, err) {
+		return nil, err
+	}
+
+	return newHTTPResponse(r, resp)
+}
+
+// newHTTPResponse converts the proxy response, base64 encoded bodies are decoded.
+func newHTTPResponse(r *http.Request, resp *Response) (*http.Response, error) {
+	body, err := resp.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("resp.Bytes: %w", err)
+	}
+
+	statusCode := resp.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	return &http.Response{
+		Status:        strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        resp.HTTPHeader(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}, nil
+}
+
+func closeBody(r *http.Request) {
+	if r.Body != nil {
+		_ = r.Body.Close()
+	}
+}
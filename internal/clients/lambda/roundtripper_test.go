@@ -0,0 +1,76 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRoundTripper(t *testing.T) {
+	orders := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusNotFound, `{"message":"order not found"}`), nil
+	}}
+	payments := &fakeAPI{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "fallback")
+	}))
+	t.Cleanup(server.Close)
+
+	rt := NewRoundTripper(http.DefaultTransport)
+	rt.Handle("orders.lambda", newClient(orders, testFunctionARN))
+	rt.Handle("payments.lambda:8080", newClient(payments, testFunctionARN))
+	httpClient := &http.Client{Transport: rt}
+
+	resp, err := httpClient.Get("http://orders.lambda/orders/1?expand=items")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, `{"message":"order not found"}`, readBody(t, resp))
+
+	event := proxyRequest(orders.calls()[0])
+	assert.Equal(t, "/orders/1", event.Path)
+	assert.Equal(t, "items", event.QueryStringParameters["expand"])
+
+	resp, err = httpClient.Post("http://payments.lambda:8080/payments", "application/json", strings.NewReader(`{"amount":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "ok", readBody(t, resp))
+	assert.Equal(t, `{"amount":1}`, proxyRequest(payments.calls()[0]).Body)
+
+	resp, err = httpClient.Get(server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "fallback", readBody(t, resp))
+}
+
+func TestRoundTripper_Errors(t *testing.T) {
+	api := &fakeAPI{invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, errors.New("connection reset")
+	}}
+
+	rt := NewRoundTripper(nil)
+	rt.Handle("orders.lambda", newClient(api, testFunctionARN))
+	httpClient := &http.Client{Transport: rt}
+
+	_, err := httpClient.Get("http://orders.lambda/orders/1")
+	require.ErrorContains(t, err, "connection reset")
+
+	_, err = httpClient.Get("http://unknown.lambda/")
+	require.ErrorIs(t, err, ErrUnknownHost)
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return string(body)
+}
@@ -0,0 +1,113 @@
+package lambda
+
+import (
+	"fmt"
+	"strings"
+)
+
+// route is a parsed "METHOD /path" pattern, following API Gateway resource syntax:
+// a path segment can be a {param} and the last one a greedy {param+}.
+// The method can be omitted or set to ANY to match every method.
+type route struct {
+	pattern  string
+	method   string
+	segments []string
+}
+
+// mustParseRoute panics on invalid patterns, the same way http.ServeMux does on registration.
+func mustParseRoute(pattern string) route {
+	r, err := parseRoute(pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+func parseRoute(pattern string) (route, error) {
+	method, path, found := strings.Cut(strings.TrimSpace(pattern), " ")
+	if !found {
+		method, path = "", method
+	}
+
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method == "ANY" {
+		method = ""
+	}
+
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "/") {
+		return route{}, fmt.Errorf("route pattern[%s]: path must start with /", pattern)
+	}
+
+	segments := splitPath(path)
+	for i, s := range segments {
+		if strings.HasSuffix(s, "+}") && i != len(segments)-1 {
+			return route{}, fmt.Errorf("route pattern[%s]: greedy segment %s must be the last one", pattern, s)
+		}
+	}
+
+	return route{
+		pattern:  pattern,
+		method:   method,
+		segments: segments,
+	}, nil
+}
+
+// match reports whether the route matches the request and returns values of path parameters.
+func (r route) match(method, path string) (map[string]string, bool) {
+	if r.method != "" && !strings.EqualFold(r.method, method) {
+		return nil, false
+	}
+
+	segments := splitPath(path)
+	params := make(map[string]string)
+
+	for i, s := range r.segments {
+		name, isParam := paramName(s)
+
+		if isParam && strings.HasSuffix(name, "+") {
+			if i >= len(segments) {
+				return nil, false
+			}
+			params[strings.TrimSuffix(name, "+")] = strings.Join(segments[i:], "/")
+			return params, true
+		}
+
+		if i >= len(segments) {
+			return nil, false
+		}
+
+		if isParam {
+			params[name] = segments[i]
+			continue
+		}
+
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+
+	return params, true
+}
+
+func paramName(segment string) (string, bool) {
+	if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+		return segment[1 : len(segment)-1], true
+	}
+
+	return "", false
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
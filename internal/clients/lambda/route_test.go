@@ -0,0 +1,41 @@
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRoute_Match(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		method     string
+		path       string
+		wantMatch  bool
+		wantParams map[string]string
+	}{
+		{pattern: "GET /orders", method: "GET", path: "/orders", wantMatch: true, wantParams: map[string]string{}},
+		{pattern: "GET /orders", method: "POST", path: "/orders"},
+		{pattern: "/orders", method: "DELETE", path: "/orders/", wantMatch: true, wantParams: map[string]string{}},
+		{pattern: "ANY /orders/{id}", method: "PUT", path: "/orders/42", wantMatch: true, wantParams: map[string]string{"id": "42"}},
+		{pattern: "GET /orders/{id}", method: "GET", path: "/orders"},
+		{pattern: "GET /orders/{id}", method: "GET", path: "/orders/42/items"},
+		{pattern: "GET /files/{key+}", method: "GET", path: "/files/a/b/c.txt", wantMatch: true, wantParams: map[string]string{"key": "a/b/c.txt"}},
+		{pattern: "GET /files/{key+}", method: "GET", path: "/files"},
+		{pattern: "GET /", method: "GET", path: "/", wantMatch: true, wantParams: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.method+" "+tt.path, func(t *testing.T) {
+			params, ok := mustParseRoute(tt.pattern).match(tt.method, tt.path)
+			assert.Equal(t, tt.wantMatch, ok)
+			assert.Equal(t, tt.wantParams, params)
+		})
+	}
+}
+
+func TestParseRoute_Invalid(t *testing.T) {
+	for _, pattern := range []string{"GET orders", "GET /files/{key+}/meta"} {
+		_, err := parseRoute(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
@@ -0,0 +1,141 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+)
+
+// ErrNoRoute is returned by Router when no route matches the request and no default target is set.
+var ErrNoRoute = errors.New("no route")
+
+type routerRoute struct {
+	route  route
+	target Client
+}
+
+// Router dispatches requests to target clients by "METHOD /path" patterns, see WithRouteCodec for the syntax.
+type Router struct {
+	routes   []routerRoute
+	fallback Client
+}
+
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// FunctionRoute maps a "METHOD /path" pattern to a function name, a partial or a full ARN.
+type FunctionRoute struct {
+	Pattern  string
+	Function string
+	// Options are applied after the router wide ones.
+	Options []Option
+}
+
+// NewFunctionRouter returns a router fanning requests out to the functions of the routes, e.g.
+// "GET /orders/{id}" to orders and "POST /payments" to payments. Routes of the same function without
+// route options share one client, opts are applied to every client.
+func NewFunctionRouter(cli *lambda.Client, routes []FunctionRoute, opts ...Option) (*Router, error) {
+	if cli == nil {
+		return nil, fmt.Errorf("lambda.NewFromConfig returned nil")
+	}
+
+	return newFunctionRouter(cli, routes, opts...)
+}
+
+func newFunctionRouter(cli lambdaAPI, routes []FunctionRoute, opts ...Option) (*Router, error) {
+	r := NewRouter()
+	registry := newRegistry(cli, opts...)
+
+	for i, fr := range routes {
+		rt, err := parseRoute(fr.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("parseRoute[%s]: %w", fr.Pattern, err)
+		}
+
+		name := fr.Function
+		if len(fr.Options) > 0 {
+			name = fmt.Sprintf("%s#%d", fr.Function, i)
+		}
+
+		target, err := registry.Client(name)
+		if errors.Is(err, ErrUnknownFunction) {
+			if err := registry.Register(name, fr.Function, fr.Options...); err != nil {
+				return nil, fmt.Errorf("registry.Register[%s]: %w", fr.Pattern, err)
+			}
+			target, err = registry.Client(name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("registry.Client: %w", err)
+		}
+
+		r.routes = append(r.routes, routerRoute{route: rt, target: target})
+	}
+
+	return r, nil
+}
+
+// Handle registers the target serving requests matching the pattern, routes are matched in registration order.
+// It panics if the pattern is invalid.
+func (r *Router) Handle(pattern string, target Client) {
+	r.routes = append(r.routes, routerRoute{route: mustParseRoute(pattern), target: target})
+}
+
+// Default sets the catch-all target for requests matching no route, like an API Gateway {proxy+} resource.
+// Create it WithEnvelope(EnvelopeRaw) to fall back to raw invocations of a function which is not a proxy handler.
+func (r *Router) Default(target Client) {
+	r.fallback = target
+}
+
+func (r *Router) Invoke(ctx context.Context, httpMethod, path string, body []byte, opts ...Option) (string, error) {
+	target, err := r.target(httpMethod, path)
+	if err != nil {
+		return "", fmt.Errorf("router: %w", err)
+	}
+
+	return target.Invoke(ctx, httpMethod, path, body, opts...)
+}
+
+func (r *Router) InvokeAsync(ctx context.Context, httpMethod, path string, body []byte, opts ...Option) error {
+	target, err := r.target(httpMethod, path)
+	if err != nil {
+		return fmt.Errorf("router: %w", err)
+	}
+
+	return target.InvokeAsync(ctx, httpMethod, path, body, opts...)
+}
+
+// Do invokes the target of req, see Client.Do.
+func (r *Router) Do(ctx context.Context, req Request, opts ...Option) (*Response, error) {
+	target, err := r.target(req.HTTPMethod, req.Path)
+	if err != nil {
+		return nil, fmt.Errorf("router: %w", err)
+	}
+
+	return target.Do(ctx, req, opts...)
+}
+
+// Call invokes the target of the route, see Client.Call.
+func (r *Router) Call(ctx context.Context, httpMethod, path string, in, out any, opts ...Option) error {
+	target, err := r.target(httpMethod, path)
+	if err != nil {
+		return fmt.Errorf("router: %w", err)
+	}
+
+	return target.Call(ctx, httpMethod, path, in, out, opts...)
+}
+
+func (r *Router) target(httpMethod, path string) (Client, error) {
+	for _, rr := range r.routes {
+		if _, ok := rr.route.match(httpMethod, path); ok {
+			return rr.target, nil
+		}
+	}
+
+	if r.fallback != nil {
+		return r.fallback, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoRoute, httpMethod, path)
+}
@@ -0,0 +1,68 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRouter(t *testing.T) {
+	orders := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusOK, "orders"), nil
+	}}
+	catchAll := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: in.Payload}, nil
+	}}
+
+	router := NewRouter()
+	router.Handle("GET /orders/{id}", newClient(orders, testFunctionARN))
+
+	_, err := router.Invoke(_ctx, "GET", "/unknown", nil)
+	require.ErrorIs(t, err, ErrNoRoute)
+
+	router.Default(newClient(catchAll, testFunctionARN, WithEnvelope(EnvelopeRaw)))
+
+	out, err := router.Invoke(_ctx, "GET", "/orders/42", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "orders", out)
+
+	out, err = router.Invoke(_ctx, "POST", "/unknown", []byte(`{"raw":true}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"raw":true}`, out)
+	assert.Equal(t, []byte(`{"raw":true}`), catchAll.calls()[0].Payload)
+}
+
+func TestFunctionRouter(t *testing.T) {
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusOK, `{"function":"`+*in.FunctionName+`"}`), nil
+	}}
+
+	router, err := newFunctionRouter(api, []FunctionRoute{
+		{Pattern: "GET /orders/{id}", Function: "orders"},
+		{Pattern: "POST /orders", Function: "orders"},
+		{Pattern: "POST /payments", Function: "payments", Options: []Option{WithQualifier("live")}},
+	}, WithTimeout(time.Second))
+	require.NoError(t, err)
+	assert.Same(t, router.routes[0].target, router.routes[1].target, "routes of a function share the client")
+
+	resp, err := router.Do(_ctx, Request{HTTPMethod: "GET", Path: "/orders/42"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"function":"orders"}`, resp.Body)
+
+	var out struct{ Function string }
+	require.NoError(t, router.Call(_ctx, "POST", "/payments", map[string]int{"amount": 1}, &out))
+	assert.Equal(t, "payments", out.Function)
+	assert.Equal(t, "live", *api.calls()[1].Qualifier)
+
+	err = router.Call(_ctx, "DELETE", "/orders/42", nil, nil)
+	require.ErrorIs(t, err, ErrNoRoute)
+
+	_, err = newFunctionRouter(api, []FunctionRoute{{Pattern: "orders", Function: "orders"}})
+	require.Error(t, err)
+	_, err = newFunctionRouter(api, []FunctionRoute{{Pattern: "GET /orders", Function: "not a function"}})
+	require.Error(t, err)
+}
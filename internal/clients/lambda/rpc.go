@@ -0,0 +1,54 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"net/http"
+)
+
+// RPCHandler adapts fn to a function side Handler for RPC-style clients such as those generated by lambda-gen.
+// The request body is decoded into In and Out is responded as a JSON body, invalid bodies get 400 Bad Request
+// and errors of fn 500 Internal Server Error with {"message": err.Error()}.
+func RPCHandler[In, Out any](fn func(context.Context, In) (Out, error)) Handler {
+	return func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		var in In
+
+		body, err := decodeBody(req.Body, req.IsBase64Encoded)
+		if err != nil {
+			return rpcError(http.StatusBadRequest, err), nil
+		}
+
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &in); err != nil {
+				return rpcError(http.StatusBadRequest, err), nil
+			}
+		}
+
+		out, err := fn(ctx, in)
+		if err != nil {
+			return rpcError(http.StatusInternalServerError, err), nil
+		}
+
+		payload, err := json.Marshal(out)
+		if err != nil {
+			return rpcError(http.StatusInternalServerError, err), nil
+		}
+
+		return events.APIGatewayProxyResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body:       string(payload),
+		}, nil
+	}
+}
+
+func rpcError(statusCode int, err error) events.APIGatewayProxyResponse {
+	body, _ := json.Marshal(map[string]string{"message": err.Error()})
+
+	return events.APIGatewayProxyResponse{
+		StatusCode: statusCode,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(body),
+	}
+}
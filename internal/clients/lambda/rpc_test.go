@@ -0,0 +1,39 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestRPCHandler(t *testing.T) {
+	type order struct {
+		ID string `json:"id"`
+	}
+
+	handler := RPCHandler(func(_ context.Context, in order) (*order, error) {
+		if in.ID == "" {
+			return nil, errors.New("id is empty")
+		}
+		return &order{ID: in.ID + "!"}, nil
+	})
+
+	resp, err := handler(_ctx, events.APIGatewayProxyRequest{Body: `{"id":"1"}`})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Headers["Content-Type"])
+	assert.JSONEq(t, `{"id":"1!"}`, resp.Body)
+
+	resp, err = handler(_ctx, events.APIGatewayProxyRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.JSONEq(t, `{"message":"id is empty"}`, resp.Body)
+
+	resp, err = handler(_ctx, events.APIGatewayProxyRequest{Body: `{"id":`})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,117 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"time"
+)
+
+// SDKRetryConfig configures the retryer of the AWS SDK, it retries Lambda API calls before WithRetryPolicy does.
+type SDKRetryConfig struct {
+	// Mode is aws.RetryModeStandard by default, aws.RetryModeAdaptive additionally rate limits attempts
+	// client-side once they are throttled.
+	Mode aws.RetryMode
+	// MaxAttempts including the first one, 3 by default.
+	MaxAttempts int
+	// MaxBackoff caps the jittered exponential delay between attempts, 20 seconds by default.
+	MaxBackoff time.Duration
+	// RetryTokens is the capacity of the token bucket spent by retries, 500 by default, negative disables it.
+	RetryTokens int
+}
+
+// WithSDKRetry replaces the retryer of the *lambda.Client for API calls of this client, see WithMetrics and
+// WithEventSink to observe throttled attempts.
+func WithSDKRetry(cfg SDKRetryConfig) Option {
+	standard := func(o *retry.StandardOptions) {
+		if cfg.MaxAttempts > 0 {
+			o.MaxAttempts = cfg.MaxAttempts
+		}
+		if cfg.MaxBackoff > 0 {
+			o.MaxBackoff = cfg.MaxBackoff
+			o.Backoff = retry.NewExponentialJitterBackoff(cfg.MaxBackoff)
+		}
+		if cfg.RetryTokens < 0 {
+			o.RateLimiter = ratelimit.None
+		} else if cfg.RetryTokens > 0 {
+			o.RateLimiter = ratelimit.NewTokenRateLimit(uint(cfg.RetryTokens))
+		}
+	}
+
+	var retryer aws.Retryer
+	if cfg.Mode == aws.RetryModeAdaptive {
+		retryer = retry.NewAdaptiveMode(func(o *retry.AdaptiveModeOptions) {
+			o.StandardOptions = append(o.StandardOptions, standard)
+		})
+	} else {
+		retryer = retry.NewStandard(standard)
+	}
+
+	return func(c *client) {
+		c.sdkRetryer = retryer
+	}
+}
+
+// ThrottleMetrics describes a throttled attempt retried by the SDK, it is passed to ThrottleRecorder.
+type ThrottleMetrics struct {
+	FunctionARN string
+	// Attempt is the throttled attempt, starting at 1.
+	Attempt int
+	// Delay before the next attempt.
+	Delay time.Duration
+}
+
+// ThrottleRecorder is optionally implemented by a MetricsRecorder to count throttled attempts which the SDK
+// retries, those are not visible as invocations.
+type ThrottleRecorder interface {
+	RecordThrottle(m ThrottleMetrics)
+}
+
+// observesThrottles reports whether throttled attempts are reported to recorders or sinks.
+func (c *client) observesThrottles() bool {
+	return len(c.throttleRecorders) > 0 || len(c.eventSinks) > 0
+}
+
+// throttleObserverOption wraps the retryer of an Invoke API call to report throttled attempts.
+func (c *client) throttleObserverOption(ctx context.Context) func(*lambda.Options) {
+	return func(o *lambda.Options) {
+		if retryer, ok := o.Retryer.(aws.RetryerV2); ok {
+			o.Retryer = &throttleObserver{RetryerV2: retryer, ctx: ctx, client: c}
+		}
+	}
+}
+
+type throttleObserver struct {
+	aws.RetryerV2
+	ctx    context.Context
+	client *client
+}
+
+var throttles = retry.IsErrorThrottles(retry.DefaultThrottles)
+
+// RetryDelay is called once the failed attempt is going to be retried.
+func (o *throttleObserver) RetryDelay(attempt int, err error) (time.Duration, error) {
+	delay, delayErr := o.RetryerV2.RetryDelay(attempt, err)
+	if delayErr == nil && throttles.IsErrorThrottle(err) == aws.TrueTernary {
+		o.client.throttled(o.ctx, attempt, delay, err)
+	}
+
+	return delay, delayErr
+}
+
+func (c *client) throttled(ctx context.Context, attempt int, delay time.Duration, err error) {
+	functionARN := c.redact(c.functionARN)
+
+	for _, recorder := range c.throttleRecorders {
+		recorder.RecordThrottle(ThrottleMetrics{FunctionARN: functionARN, Attempt: attempt, Delay: delay})
+	}
+
+	c.emit(ctx, Throttled{
+		EventHeader: c.eventHeader(EventThrottled),
+		Attempt:     attempt,
+		Delay:       delay,
+		Error:       c.redact(err.Error()),
+	})
+}
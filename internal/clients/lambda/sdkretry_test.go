@@ -0,0 +1,87 @@
+package lambda
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type throttleRecorder struct {
+	mu        sync.Mutex
+	throttles []ThrottleMetrics
+}
+
+func (r *throttleRecorder) RecordInvocation(InvocationMetrics) {}
+
+func (r *throttleRecorder) RecordThrottle(m ThrottleMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.throttles = append(r.throttles, m)
+}
+
+// newThrottlingServer throttles the first n requests.
+func newThrottlingServer(t *testing.T, n int32) (*lambda.Client, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) <= n {
+			w.Header().Set("X-Amzn-ErrorType", "TooManyRequestsException")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"Rate exceeded","Reason":"ReservedFunctionConcurrentInvocationLimitExceeded"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"statusCode":200,"body":"ok"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return lambda.New(lambda.Options{
+		Region:       "eu-central-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(server.URL),
+	}), &requests
+}
+
+func TestWithSDKRetry(t *testing.T) {
+	cli, requests := newThrottlingServer(t, 3)
+	recorder := &throttleRecorder{}
+
+	var events []Event
+	sink := func(_ context.Context, e Event) {
+		if e.Header().Type == EventThrottled {
+			events = append(events, e)
+		}
+	}
+
+	c := newClient(cli, testFunctionARN,
+		WithSDKRetry(SDKRetryConfig{Mode: aws.RetryModeAdaptive, MaxAttempts: 4, MaxBackoff: time.Millisecond}),
+		WithMetrics(recorder), WithEventSink(sink))
+
+	body, err := c.Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", body)
+	assert.EqualValues(t, 4, requests.Load())
+
+	require.Len(t, recorder.throttles, 3)
+	assert.Equal(t, 1, recorder.throttles[0].Attempt)
+	assert.Equal(t, testFunctionARN, recorder.throttles[0].FunctionARN)
+	require.Len(t, events, 3)
+	assert.Equal(t, 3, events[2].(Throttled).Attempt)
+}
+
+func TestWithSDKRetry_MaxAttempts(t *testing.T) {
+	cli, requests := newThrottlingServer(t, 10)
+
+	c := newClient(cli, testFunctionARN, WithSDKRetry(SDKRetryConfig{MaxAttempts: 2, MaxBackoff: time.Millisecond}))
+
+	_, err := c.Invoke(_ctx, "GET", "/", nil)
+	assert.ErrorIs(t, err, ErrThrottled)
+	assert.EqualValues(t, 2, requests.Load())
+}
@@ -0,0 +1,107 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// Singleflight collapses concurrent identical sync invocations into one, callers share its response or error.
+// The invocation runs with the context of the first caller, the others stop waiting once their context is done.
+// InvokeTo calls are not deduplicated, as their body may be left in the claim-check store.
+type Singleflight struct {
+	// Methods are the deduplicated HTTP methods, GET by default. Ignored if Key is set.
+	Methods []string
+	// Key returns the deduplication key of req, an empty key disables deduplication.
+	// By default requests with equal function, qualifier, method, path, query, body, VaryHeaders and call scoped
+	// request context are identical, as with Cache.
+	Key func(req *Request) string
+	// VaryHeaders are the request headers distinguishing invocations, Authorization and Cookie by default.
+	// Ignored if Key is set.
+	VaryHeaders []string
+}
+
+// WithSingleflight deduplicates concurrent invocations, it is installed as an interceptor
+// at its position among WithInterceptors options.
+func WithSingleflight(sf Singleflight) Option {
+	if len(sf.Methods) == 0 {
+		sf.Methods = []string{http.MethodGet}
+	}
+	if len(sf.VaryHeaders) == 0 {
+		sf.VaryHeaders = defaultVaryHeaders
+	}
+
+	return func(c *client) {
+		g := &flightGroup{calls: make(map[string]*flight)}
+
+		c.interceptors = append(c.interceptors, func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+			var key string
+			switch {
+			case req.Async, isStreamed(ctx), isWarmPing(ctx):
+			case sf.Key != nil:
+				key = sf.Key(req)
+			case slices.Contains(sf.Methods, req.HTTPMethod):
+				key = c.varyKey(c.callConfig(ctx), req, sf.VaryHeaders)
+			}
+
+			if key == "" {
+				return next(ctx, req)
+			}
+
+			resp, err := g.do(ctx, key, func() (*Response, error) {
+				return next(ctx, req)
+			})
+			if resp != nil {
+				resp = cloneResponse(resp)
+			}
+
+			return resp, err
+		})
+	}
+}
+
+var errLeaderPanicked = errors.New("singleflight: invocation panicked")
+
+type flight struct {
+	done chan struct{}
+	resp *Response
+	err  error
+}
+
+// flightGroup is a minimal singleflight.Group.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+// do runs fn once per key, followers wait for the leader until their ctx is done.
+func (g *flightGroup) do(ctx context.Context, key string, fn func() (*Response, error)) (*Response, error) {
+	g.mu.Lock()
+	if f, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		select {
+		case <-f.done:
+			return f.resp, f.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// followers see the error if fn panics
+	f := &flight{done: make(chan struct{}), err: errLeaderPanicked}
+	g.calls[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+
+	f.resp, f.err = fn()
+
+	return f.resp, f.err
+}
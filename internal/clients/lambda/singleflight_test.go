@@ -0,0 +1,109 @@
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestWithSingleflight(t *testing.T) {
+	release := make(chan struct{})
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		<-release
+		return proxyOutput(in, http.StatusOK, "ok"), nil
+	}}
+
+	var started sync.WaitGroup
+	started.Add(5)
+	block := func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		if req.HTTPMethod == http.MethodGet {
+			started.Done()
+		}
+		return next(ctx, req)
+	}
+	cli := newClient(api, testFunctionARN, WithInterceptors(block), WithSingleflight(Singleflight{}))
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := cli.Invoke(_ctx, "GET", "/orders/1", nil)
+			assert.NoError(t, err)
+			assert.Equal(t, "ok", body)
+		}()
+	}
+
+	started.Wait()
+	close(release)
+	wg.Wait()
+
+	assert.Less(t, len(api.calls()), 5)
+
+	_, err := cli.Invoke(_ctx, "POST", "/orders/1", nil)
+	require.NoError(t, err)
+}
+
+func TestFlightGroupFollowerContext(t *testing.T) {
+	g := &flightGroup{calls: make(map[string]*flight)}
+
+	release := make(chan struct{})
+	leading := make(chan struct{})
+	go func() {
+		_, _ = g.do(_ctx, "key", func() (*Response, error) {
+			close(leading)
+			<-release
+			return &Response{}, nil
+		})
+	}()
+	<-leading
+
+	ctx, cancel := context.WithCancel(_ctx)
+	cancel()
+
+	_, err := g.do(ctx, "key", func() (*Response, error) {
+		t.Fatal("follower must not invoke")
+		return nil, nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, ErrorKindContext, KindOf(err))
+
+	close(release)
+}
+
+func TestWithSingleflight_InvokeTo(t *testing.T) {
+	release := make(chan struct{})
+	leading := make(chan struct{})
+	var once sync.Once
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		first := false
+		once.Do(func() { first = true })
+		if first {
+			close(leading)
+			<-release
+		}
+		return proxyOutput(in, http.StatusOK, "ok"), nil
+	}}
+	cli := newClient(api, testFunctionARN, WithSingleflight(Singleflight{}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := cli.Invoke(_ctx, "GET", "/orders/1", nil)
+		assert.NoError(t, err)
+	}()
+	<-leading
+
+	var buf bytes.Buffer
+	require.NoError(t, cli.InvokeTo(_ctx, "GET", "/orders/1", nil, &buf), "does not wait for the leader")
+	assert.Equal(t, "ok", buf.String())
+
+	close(release)
+	<-done
+	assert.Len(t, api.calls(), 2)
+}
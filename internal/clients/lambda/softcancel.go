@@ -0,0 +1,119 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAbandoned is returned when the caller stops waiting for a sync invocation,
+// the invocation itself keeps running in AWS as Lambda can't be cancelled server-side.
+var ErrAbandoned = errors.New("invocation abandoned")
+
+// SoftCancel configures what happens to a sync invocation once the caller's context is done.
+type SoftCancel struct {
+	// OnOrphan is called when an abandoned invocation eventually completes,
+	// so its request ID can be recorded for later reconciliation.
+	OnOrphan func(Orphan)
+
+	// CompensateMethod and CompensatePath, when set, point to a route
+	// which is invoked asynchronously with the Orphan as JSON body.
+	CompensateMethod string
+	CompensatePath   string
+}
+
+// Orphan describes an invocation the caller gave up waiting for.
+type Orphan struct {
+	FunctionARN string    `json:"functionArn"`
+	HTTPMethod  string    `json:"httpMethod"`
+	Path        string    `json:"path"`
+	RequestID   string    `json:"requestId,omitempty"`
+	AbandonedAt time.Time `json:"abandonedAt"`
+	// Error is the outcome of the abandoned invocation, empty on success.
+	Error string `json:"error,omitempty"`
+	// CompensateError is the outcome of the compensating invocation, empty on success or when none is configured.
+	CompensateError string `json:"compensateError,omitempty"`
+}
+
+// WithSoftCancel makes sync invocations return ErrAbandoned as soon as the caller's context is done,
+// instead of waiting for the in-flight call.
+func WithSoftCancel(sc SoftCancel) Option {
+	return func(c *client) {
+		c.softCancel = &sc
+	}
+}
+
+func (c *client) invokeSoftCancel(ctx context.Context, req *Request) (*Response, error) {
+	type result struct {
+		resp *Response
+		err  error
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		resp, err := c.invoker(context.WithoutCancel(ctx), req)
+		done <- result{resp: resp, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.resp, r.err
+	case <-ctx.Done():
+	}
+
+	abandonedAt := time.Now()
+
+	go func() {
+		r := <-done
+
+		orphan := Orphan{
+			FunctionARN: c.functionARN,
+			HTTPMethod:  req.HTTPMethod,
+			Path:        req.Path,
+			AbandonedAt: abandonedAt,
+		}
+		if r.resp != nil {
+			orphan.RequestID = r.resp.RequestID
+		}
+		if r.err != nil {
+			orphan.Error = r.err.Error()
+		}
+
+		if err := c.compensate(context.WithoutCancel(ctx), orphan); err != nil {
+			orphan.CompensateError = err.Error()
+		}
+
+		if c.softCancel.OnOrphan != nil {
+			c.softCancel.OnOrphan(orphan)
+		}
+	}()
+
+	return nil, fmt.Errorf("%w: %w", ErrAbandoned, context.Cause(ctx))
+}
+
+func (c *client) compensate(ctx context.Context, orphan Orphan) error {
+	if c.softCancel.CompensatePath == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(orphan)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	compensation := &Request{
+		HTTPMethod: c.softCancel.CompensateMethod,
+		Path:       c.softCancel.CompensatePath,
+		Body:       body,
+		Async:      true,
+	}
+
+	if _, err := c.invoker(ctx, compensation); err != nil {
+		return fmt.Errorf("invoke[compensate]: %w", err)
+	}
+
+	return nil
+}
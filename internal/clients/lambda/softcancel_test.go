@@ -0,0 +1,67 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSoftCancel(t *testing.T) {
+	release := make(chan struct{})
+	orphans := make(chan Orphan, 1)
+
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		if in.InvocationType == "Event" {
+			return proxyOutput(in, http.StatusOK, ""), nil
+		}
+		<-release
+		return proxyOutput(in, http.StatusOK, "late"), nil
+	}}
+
+	cli := newClient(api, testFunctionARN, WithSoftCancel(SoftCancel{
+		OnOrphan:         func(o Orphan) { orphans <- o },
+		CompensateMethod: "POST",
+		CompensatePath:   "/compensate",
+	}))
+
+	ctx, cancel := context.WithTimeout(_ctx, 50*time.Millisecond)
+	defer cancel()
+
+	_, err := cli.Invoke(ctx, "POST", "/slow", []byte(`{}`))
+	require.ErrorIs(t, err, ErrAbandoned)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	close(release)
+
+	select {
+	case o := <-orphans:
+		assert.Equal(t, "/slow", o.Path)
+		assert.Empty(t, o.Error)
+		assert.Empty(t, o.CompensateError)
+	case <-time.After(time.Second):
+		t.Fatal("orphan was not reported")
+	}
+
+	calls := api.calls()
+	require.Len(t, calls, 2)
+
+	compensation := proxyRequest(calls[1])
+	assert.Equal(t, "/compensate", compensation.Path)
+
+	var o Orphan
+	require.NoError(t, json.Unmarshal([]byte(compensation.Body), &o))
+	assert.Equal(t, "/slow", o.Path)
+}
+
+func TestSoftCancel_CompletesBeforeDeadline(t *testing.T) {
+	cli := newClient(&fakeAPI{}, testFunctionARN, WithSoftCancel(SoftCancel{}))
+
+	out, err := cli.Invoke(_ctx, "GET", "/fast", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", out)
+}
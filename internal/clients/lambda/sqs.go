@@ -0,0 +1,336 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sqsAPI is the subset of *sqs.Client used by SQSTransport.
+type sqsAPI interface {
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+}
+
+// sqsReceiveAPI is the subset of *sqs.Client used to await messages.
+type sqsReceiveAPI interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+}
+
+// SQSConfig configures an SQSTransport.
+type SQSConfig struct {
+	QueueURL string
+	// MessageGroupID is required by FIFO queues, the correlation ID of the invocation, if any,
+	// is the deduplication ID then, otherwise the queue must enable content-based deduplication.
+	MessageGroupID string
+	DelaySeconds   int32
+}
+
+// SQSTransport is an AsyncTransport publishing the wrapped events to an SQS queue consumed by the function through
+// an event source mapping, for durable at-least-once delivery. Wrap the function side handler with SQSHandler.
+type SQSTransport struct {
+	cli sqsAPI
+	cfg SQSConfig
+}
+
+// NewSQSTransport returns a transport publishing to cfg.QueueURL, see WithAsyncTransport.
+func NewSQSTransport(cli *sqs.Client, cfg SQSConfig) (*SQSTransport, error) {
+	if cli == nil {
+		return nil, fmt.Errorf("sqs.NewFromConfig returned nil")
+	}
+
+	if cfg.QueueURL == "" {
+		return nil, fmt.Errorf("queue url is empty")
+	}
+
+	return &SQSTransport{cli: cli, cfg: cfg}, nil
+}
+
+// Send publishes payload as the message body and returns the message ID.
+func (t *SQSTransport) Send(ctx context.Context, payload []byte) (string, error) {
+	in := &sqs.SendMessageInput{
+		QueueUrl:       pointer.To(t.cfg.QueueURL),
+		MessageBody:    pointer.To(string(payload)),
+		DelaySeconds:   t.cfg.DelaySeconds,
+		MessageGroupId: pointer.ToStringOrNil(t.cfg.MessageGroupID),
+	}
+
+	if id, ok := CorrelationIDFromContext(ctx); ok {
+		in.MessageAttributes = map[string]sqstypes.MessageAttributeValue{
+			CorrelationIDHeader: {DataType: pointer.To("String"), StringValue: pointer.To(id)},
+		}
+		if strings.HasSuffix(t.cfg.QueueURL, ".fifo") {
+			in.MessageDeduplicationId = pointer.To(id)
+		}
+	}
+
+	output, err := t.cli.SendMessage(ctx, in)
+	if err != nil {
+		return "", fmt.Errorf("cli.SendMessage: %w", err)
+	}
+
+	return pointer.Get(output.MessageId), nil
+}
+
+// Backlog returns the approximate number of messages in the queue not yet received by the function.
+func (t *SQSTransport) Backlog(ctx context.Context) (int, error) {
+	name := sqstypes.QueueAttributeNameApproximateNumberOfMessages
+
+	output, err := t.cli.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       pointer.To(t.cfg.QueueURL),
+		AttributeNames: []sqstypes.QueueAttributeName{name},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cli.GetQueueAttributes: %w", err)
+	}
+
+	backlog, err := strconv.Atoi(output.Attributes[string(name)])
+	if err != nil {
+		return 0, fmt.Errorf("strconv.Atoi: %w", err)
+	}
+
+	return backlog, nil
+}
+
+// SQSHandler adapts the function side handler to SQS events published by SQSTransport. Records failing with an
+// error or a 5xx response are reported as batch item failures to be redelivered, which requires
+// ReportBatchItemFailures in the event source mapping.
+func SQSHandler(next Handler) func(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+	return func(ctx context.Context, event events.SQSEvent) (events.SQSEventResponse, error) {
+		var resp events.SQSEventResponse
+
+		for _, record := range event.Records {
+			if !handleSQSRecord(ctx, next, record) {
+				resp.BatchItemFailures = append(resp.BatchItemFailures, events.SQSBatchItemFailure{ItemIdentifier: record.MessageId})
+			}
+		}
+
+		return resp, nil
+	}
+}
+
+// handleSQSRecord reports whether the record succeeded.
+func handleSQSRecord(ctx context.Context, next Handler, record events.SQSMessage) bool {
+	var req events.APIGatewayProxyRequest
+	if err := json.Unmarshal([]byte(record.Body), &req); err != nil {
+		return false
+	}
+
+	resp, err := next(ctx, req)
+
+	return err == nil && resp.StatusCode < 500
+}
+
+// Backoff of sqsPoller while the received messages match no waiter, as they are received again right away.
+const (
+	minUnmatchedBackoff = 100 * time.Millisecond
+	maxUnmatchedBackoff = 5 * time.Second
+)
+
+// sqsPoller long-polls a queue on behalf of every waiter of the process, so that concurrent waiters do not
+// receive and hide each other's messages. Matching messages are deleted, other received messages are made
+// visible again right away for other consumers of a shared queue.
+type sqsPoller struct {
+	cli      sqsReceiveAPI
+	queueURL string
+
+	mu      sync.Mutex
+	waiters map[*sqsWaiter]struct{}
+	cancel  context.CancelFunc
+}
+
+type sqsWaiter struct {
+	match func(sqstypes.Message) bool
+	found chan sqsReceived
+}
+
+type sqsReceived struct {
+	msg *sqstypes.Message
+	err error
+}
+
+func newSQSPoller(cli sqsReceiveAPI, queueURL string) *sqsPoller {
+	return &sqsPoller{cli: cli, queueURL: queueURL, waiters: make(map[*sqsWaiter]struct{})}
+}
+
+// receive waits until a message matches or ctx is done, the queue is polled while anybody waits.
+func (p *sqsPoller) receive(ctx context.Context, match func(sqstypes.Message) bool) (*sqstypes.Message, error) {
+	w := &sqsWaiter{match: match, found: make(chan sqsReceived, 1)}
+
+	p.mu.Lock()
+	p.waiters[w] = struct{}{}
+	if p.cancel == nil {
+		var pollCtx context.Context
+		pollCtx, p.cancel = context.WithCancel(context.WithoutCancel(ctx))
+		go p.poll(pollCtx)
+	}
+	p.mu.Unlock()
+
+	defer p.remove(w)
+
+	select {
+	case r := <-w.found:
+		return r.msg, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// remove stops polling after the last waiter.
+func (p *sqsPoller) remove(w *sqsWaiter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.waiters, w)
+	if len(p.waiters) == 0 && p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+}
+
+func (p *sqsPoller) poll(ctx context.Context) {
+	var backoff time.Duration
+
+	for ctx.Err() == nil {
+		output, err := p.cli.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:              pointer.To(p.queueURL),
+			MaxNumberOfMessages:   10,
+			WaitTimeSeconds:       20,
+			MessageAttributeNames: []string{"All"},
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				p.fail(fmt.Errorf("cli.ReceiveMessage: %w", err))
+			}
+			continue
+		}
+
+		matched := false
+		for _, msg := range output.Messages {
+			if p.deliver(ctx, msg) {
+				matched = true
+				continue
+			}
+
+			// the message becomes visible after the visibility timeout anyway
+			_, _ = p.cli.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+				QueueUrl:          pointer.To(p.queueURL),
+				ReceiptHandle:     msg.ReceiptHandle,
+				VisibilityTimeout: 0,
+			})
+		}
+
+		if matched || len(output.Messages) == 0 {
+			backoff = 0
+			continue
+		}
+
+		backoff = min(max(2*backoff, minUnmatchedBackoff), maxUnmatchedBackoff)
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// deliver passes msg to the first waiter it matches and deletes it, reporting whether it matched.
+func (p *sqsPoller) deliver(ctx context.Context, msg sqstypes.Message) bool {
+	p.mu.Lock()
+	var w *sqsWaiter
+	for candidate := range p.waiters {
+		if candidate.match(msg) {
+			w = candidate
+			break
+		}
+	}
+	if w != nil {
+		delete(p.waiters, w)
+	}
+	p.mu.Unlock()
+
+	if w == nil {
+		return false
+	}
+
+	if _, err := p.cli.DeleteMessage(ctx, &sqs.DeleteMessageInput{QueueUrl: pointer.To(p.queueURL), ReceiptHandle: msg.ReceiptHandle}); err != nil {
+		w.found <- sqsReceived{err: fmt.Errorf("cli.DeleteMessage: %w", err)}
+		return true
+	}
+
+	w.found <- sqsReceived{msg: &msg}
+
+	return true
+}
+
+// fail passes err to every waiter.
+func (p *sqsPoller) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for w := range p.waiters {
+		delete(p.waiters, w)
+		w.found <- sqsReceived{err: err}
+	}
+}
+
+// sqsReplyAPI is the subset of *sqs.Client used by SQSReplyQueue.
+type sqsReplyAPI interface {
+	sqsReceiveAPI
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+// SQSReplyQueue is a ReplyChannel over a standard SQS queue, replies carry the correlation ID as the
+// CorrelationIDHeader message attribute. Waiters of a process share a single poller, waiters of other processes
+// may share the queue too, their replies are made visible again right away. Configure a retention period for
+// replies that nobody awaits anymore.
+type SQSReplyQueue struct {
+	cli      sqsReplyAPI
+	queueURL string
+	poller   *sqsPoller
+}
+
+// NewSQSReplyQueue returns a reply channel over the queue.
+func NewSQSReplyQueue(cli *sqs.Client, queueURL string) *SQSReplyQueue {
+	return newSQSReplyQueue(cli, queueURL)
+}
+
+func newSQSReplyQueue(cli sqsReplyAPI, queueURL string) *SQSReplyQueue {
+	return &SQSReplyQueue{cli: cli, queueURL: queueURL, poller: newSQSPoller(cli, queueURL)}
+}
+
+func (q *SQSReplyQueue) Send(ctx context.Context, correlationID string, payload []byte) error {
+	_, err := q.cli.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    pointer.To(q.queueURL),
+		MessageBody: pointer.To(string(payload)),
+		MessageAttributes: map[string]sqstypes.MessageAttributeValue{
+			CorrelationIDHeader: {DataType: pointer.To("String"), StringValue: pointer.To(correlationID)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("cli.SendMessage: %w", err)
+	}
+
+	return nil
+}
+
+func (q *SQSReplyQueue) Receive(ctx context.Context, correlationID string) ([]byte, error) {
+	msg, err := q.poller.receive(ctx, func(msg sqstypes.Message) bool {
+		attr, ok := msg.MessageAttributes[CorrelationIDHeader]
+		return ok && pointer.Get(attr.StringValue) == correlationID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(pointer.Get(msg.Body)), nil
+}
@@ -0,0 +1,179 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type fakeSQS struct {
+	err error
+
+	mu    sync.Mutex
+	sends []*sqs.SendMessageInput
+}
+
+func (f *fakeSQS) SendMessage(_ context.Context, in *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	f.sends = append(f.sends, in)
+
+	return &sqs.SendMessageOutput{MessageId: pointer.To("message-1")}, nil
+}
+
+func (f *fakeSQS) GetQueueAttributes(context.Context, *sqs.GetQueueAttributesInput, ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	return &sqs.GetQueueAttributesOutput{Attributes: map[string]string{"ApproximateNumberOfMessages": "42"}}, nil
+}
+
+func TestWithAsyncTransport_SQS(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, 200, "ok"), nil
+	}}
+	queue := &fakeSQS{}
+	transport := &SQSTransport{cli: queue, cfg: SQSConfig{QueueURL: "https://sqs.eu-central-1.amazonaws.com/000000000000/orders.fifo", MessageGroupID: "orders"}}
+	cli := newClient(api, testFunctionARN, WithAsyncTransport(transport), WithCorrelationID())
+
+	resp, err := cli.Do(ContextWithCorrelationID(_ctx, "corr-1"), Request{HTTPMethod: "POST", Path: "/orders", Body: []byte(`{"id":"1"}`), Async: true})
+	require.NoError(t, err)
+	assert.Equal(t, "message-1", resp.RequestID)
+
+	send := queue.sends[0]
+	assert.Equal(t, "orders", *send.MessageGroupId)
+	assert.Equal(t, "corr-1", *send.MessageDeduplicationId)
+	assert.Equal(t, "corr-1", *send.MessageAttributes[CorrelationIDHeader].StringValue)
+
+	var event events.APIGatewayProxyRequest
+	require.NoError(t, json.Unmarshal([]byte(*send.MessageBody), &event))
+	assert.Equal(t, "/orders", event.Path)
+	assert.Equal(t, `{"id":"1"}`, event.Body)
+
+	_, err = cli.Invoke(_ctx, "GET", "/orders/1", nil)
+	require.NoError(t, err)
+	assert.Len(t, api.calls(), 1, "sync invocations call the Invoke API")
+
+	queue.err = errors.New("queue does not exist")
+	err = cli.InvokeAsync(_ctx, "POST", "/orders", nil)
+	require.Error(t, err)
+	assert.Equal(t, ErrorKindTransport, KindOf(err))
+
+	backlog, err := transport.Backlog(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 42, backlog)
+}
+
+func TestSQSHandler(t *testing.T) {
+	handler := SQSHandler(func(_ context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		switch req.Path {
+		case "/error":
+			return events.APIGatewayProxyResponse{}, errors.New("boom")
+		case "/unavailable":
+			return events.APIGatewayProxyResponse{StatusCode: http.StatusServiceUnavailable}, nil
+		}
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusBadRequest}, nil
+	})
+
+	resp, err := handler(_ctx, events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "1", Body: `{"path":"/orders"}`},
+		{MessageId: "2", Body: `{"path":"/error"}`},
+		{MessageId: "3", Body: `{"path":"/unavailable"}`},
+		{MessageId: "4", Body: `not json`},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []events.SQSBatchItemFailure{{ItemIdentifier: "2"}, {ItemIdentifier: "3"}, {ItemIdentifier: "4"}}, resp.BatchItemFailures)
+}
+
+// fakeReplyQueue is an in-memory queue, received messages stay in it until deleted.
+type fakeReplyQueue struct {
+	mu       sync.Mutex
+	messages []sqstypes.Message
+	next     int
+}
+
+func (f *fakeReplyQueue) SendMessage(_ context.Context, in *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.next++
+	f.messages = append(f.messages, sqstypes.Message{
+		Body:              in.MessageBody,
+		MessageAttributes: in.MessageAttributes,
+		ReceiptHandle:     pointer.To(strconv.Itoa(f.next)),
+	})
+
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func (f *fakeReplyQueue) ReceiveMessage(context.Context, *sqs.ReceiveMessageInput, ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return &sqs.ReceiveMessageOutput{Messages: slices.Clone(f.messages)}, nil
+}
+
+func (f *fakeReplyQueue) DeleteMessage(_ context.Context, in *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.messages = slices.DeleteFunc(f.messages, func(msg sqstypes.Message) bool { return *msg.ReceiptHandle == *in.ReceiptHandle })
+
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeReplyQueue) ChangeMessageVisibility(context.Context, *sqs.ChangeMessageVisibilityInput, ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func TestSQSReplyQueue(t *testing.T) {
+	fake := &fakeReplyQueue{}
+	queue := newSQSReplyQueue(fake, "replies")
+
+	require.NoError(t, queue.Send(_ctx, "corr-1", []byte("one")))
+	require.NoError(t, queue.Send(_ctx, "corr-2", []byte("two")))
+
+	payload, err := queue.Receive(_ctx, "corr-2")
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(payload))
+	assert.Len(t, fake.messages, 1, "replies of others are kept")
+}
+
+func TestSQSReplyQueue_ConcurrentWaiters(t *testing.T) {
+	fake := &fakeReplyQueue{}
+	queue := newSQSReplyQueue(fake, "replies")
+
+	ids := []string{"corr-1", "corr-2", "corr-3"}
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			payload, err := queue.Receive(_ctx, id)
+			assert.NoError(t, err)
+			assert.Equal(t, "reply "+id, string(payload))
+		}()
+	}
+
+	for _, id := range slices.Backward(ids) {
+		require.NoError(t, queue.Send(_ctx, id, []byte("reply "+id)))
+	}
+	wg.Wait()
+
+	assert.Empty(t, fake.messages)
+}
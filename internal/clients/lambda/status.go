@@ -0,0 +1,34 @@
+package lambda
+
+import (
+	"net/http"
+	"slices"
+)
+
+// WithAcceptStatuses sets the proxy response status codes returned as a response rather than ErrUnexpectedStatus,
+// only 200 by default. Use Do to read the status code of a response.
+func WithAcceptStatuses(codes ...int) Option {
+	return func(c *client) {
+		c.acceptStatuses = codes
+	}
+}
+
+// WithClientErrorsAsData returns 4xx proxy responses as a response rather than ErrUnexpectedStatus,
+// for REST-style functions where e.g. 404 Not Found is a business outcome. Use Do to read status code and headers.
+func WithClientErrorsAsData() Option {
+	return func(c *client) {
+		c.clientErrorsAsData = true
+	}
+}
+
+func (c *client) accepts(statusCode int) bool {
+	if c.clientErrorsAsData && statusCode >= 400 && statusCode < 500 {
+		return true
+	}
+
+	if len(c.acceptStatuses) == 0 {
+		return statusCode == http.StatusOK
+	}
+
+	return slices.Contains(c.acceptStatuses, statusCode)
+}
@@ -0,0 +1,64 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestWithAcceptStatuses(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		status := http.StatusCreated
+		if proxyRequest(in).Path == "/missing" {
+			status = http.StatusNotFound
+		}
+		return proxyOutput(in, status, "created"), nil
+	}}
+	cli := newClient(api, testFunctionARN, WithAcceptStatuses(http.StatusOK, http.StatusCreated, http.StatusNoContent))
+
+	resp, err := cli.Do(_ctx, Request{HTTPMethod: "POST", Path: "/items"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "created", resp.Body)
+
+	body, err := cli.Invoke(_ctx, "POST", "/items", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "created", body)
+
+	resp, err = cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/missing"})
+	var use *ErrUnexpectedStatus
+	require.ErrorAs(t, err, &use)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "response is returned along with the error")
+}
+
+func TestWithClientErrorsAsData(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		status := http.StatusNotFound
+		if proxyRequest(in).Path == "/broken" {
+			status = http.StatusBadGateway
+		}
+		payload, err := json.Marshal(events.APIGatewayProxyResponse{
+			StatusCode: status,
+			Headers:    map[string]string{"Content-Type": "application/problem+json"},
+			Body:       `{"title":"not found"}`,
+		})
+		return &lambda.InvokeOutput{StatusCode: http.StatusOK, Payload: payload}, err
+	}}
+	cli := newClient(api, testFunctionARN, WithClientErrorsAsData())
+
+	resp, err := cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/items/1"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, "application/problem+json", resp.Headers["Content-Type"])
+	assert.Equal(t, `{"title":"not found"}`, resp.Body)
+
+	_, err = cli.Do(_ctx, Request{HTTPMethod: "GET", Path: "/broken"})
+	var use *ErrUnexpectedStatus
+	require.ErrorAs(t, err, &use)
+	assert.Equal(t, http.StatusBadGateway, use.Code)
+}
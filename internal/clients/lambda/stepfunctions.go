@@ -0,0 +1,195 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultPollInterval    = time.Second
+	defaultMaxPollInterval = 30 * time.Second
+)
+
+// StepFunctionsConfig configures polling of executions started by NewStepFunctions.
+type StepFunctionsConfig struct {
+	// PollInterval is the first delay between DescribeExecution calls, it doubles up to MaxPollInterval, defaults to 1s.
+	PollInterval    time.Duration
+	MaxPollInterval time.Duration
+}
+
+// stepFunctionsAPI is the subset of *sfn.Client used by NewStepFunctions.
+type stepFunctionsAPI interface {
+	StartExecution(ctx context.Context, params *sfn.StartExecutionInput, optFns ...func(*sfn.Options)) (*sfn.StartExecutionOutput, error)
+	DescribeExecution(ctx context.Context, params *sfn.DescribeExecutionInput, optFns ...func(*sfn.Options)) (*sfn.DescribeExecutionOutput, error)
+}
+
+// NewStepFunctions returns a client starting executions of a Standard Workflows state machine, for flows exceeding
+// the 15 minutes limit of a function. The execution input is the request envelope and its output the response one,
+// e.g. APIGatewayProxyResponse produced by the last state. Sync invocations poll the execution until it completes
+// or ctx is done, failed, timed out and aborted executions are function errors with the error and cause of the
+// execution. Async invocations return once the execution is started. Response.RequestID is the execution ARN,
+// WithQualifier selects a version or an alias of the state machine.
+func NewStepFunctions(cli *sfn.Client, stateMachineARN string, cfg StepFunctionsConfig, opts ...Option) (Client, error) {
+	if cli == nil {
+		return nil, fmt.Errorf("sfn.NewFromConfig returned nil")
+	}
+
+	if err := validateStateMachine(stateMachineARN); err != nil {
+		return nil, fmt.Errorf("validateStateMachine: %w", err)
+	}
+
+	return newStepFunctions(cli, stateMachineARN, cfg, opts...), nil
+}
+
+func newStepFunctions(cli stepFunctionsAPI, stateMachineARN string, cfg StepFunctionsConfig, opts ...Option) *client {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.MaxPollInterval < cfg.PollInterval {
+		cfg.MaxPollInterval = max(defaultMaxPollInterval, cfg.PollInterval)
+	}
+
+	api := &stepFunctionsInvoker{cli: cli, stateMachineARN: stateMachineARN, cfg: cfg, rand: newRandomRand()}
+
+	return newClient(api, stateMachineARN, opts...)
+}
+
+func validateStateMachine(stateMachineARN string) error {
+	a, err := arn.Parse(stateMachineARN)
+	if err != nil {
+		return fmt.Errorf("arn.Parse[%s]: %w", stateMachineARN, err)
+	}
+
+	if a.Service != "states" {
+		return fmt.Errorf("not a state machine ARN: %s", stateMachineARN)
+	}
+
+	return nil
+}
+
+// stepFunctionsInvoker is a lambdaAPI running invocations as executions of the state machine.
+type stepFunctionsInvoker struct {
+	cli             stepFunctionsAPI
+	stateMachineARN string
+	cfg             StepFunctionsConfig
+	rand            *Rand
+}
+
+func (s *stepFunctionsInvoker) Invoke(ctx context.Context, in *lambda.InvokeInput, optFns ...func(*lambda.Options)) (*lambda.InvokeOutput, error) {
+	stateMachineARN := s.stateMachineARN
+	if in.Qualifier != nil {
+		stateMachineARN += ":" + *in.Qualifier
+	}
+
+	sfnOptFns := stepFunctionsOptions(optFns)
+
+	started, err := s.cli.StartExecution(ctx, &sfn.StartExecutionInput{
+		StateMachineArn: pointer.To(stateMachineARN),
+		Name:            pointer.To(randomUUID(s.rand)),
+		Input:           pointer.To(string(in.Payload)),
+	}, sfnOptFns...)
+	if err != nil {
+		return nil, fmt.Errorf("cli.StartExecution: %w", err)
+	}
+
+	output := &lambda.InvokeOutput{StatusCode: http.StatusOK}
+	awsmiddleware.SetRequestIDMetadata(&output.ResultMetadata, pointer.Get(started.ExecutionArn))
+
+	if in.InvocationType == types.InvocationTypeEvent {
+		output.StatusCode = http.StatusAccepted
+		return output, nil
+	}
+
+	execution, err := s.wait(ctx, started.ExecutionArn, sfnOptFns)
+	if err != nil {
+		return nil, fmt.Errorf("wait[%s]: %w", pointer.Get(started.ExecutionArn), err)
+	}
+
+	if execution.Status == sfntypes.ExecutionStatusSucceeded {
+		output.Payload = []byte(pointer.Get(execution.Output))
+		return output, nil
+	}
+
+	errorType := pointer.Get(execution.Error)
+	if errorType == "" {
+		errorType = string(execution.Status)
+	}
+
+	payload, err := json.Marshal(messages.InvokeResponse_Error{Message: pointer.Get(execution.Cause), Type: errorType})
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	output.Payload = payload
+	output.FunctionError = pointer.To("Unhandled")
+
+	return output, nil
+}
+
+// wait polls the execution with a doubling interval until it is no longer running.
+func (s *stepFunctionsInvoker) wait(ctx context.Context, executionARN *string, optFns []func(*sfn.Options)) (*sfn.DescribeExecutionOutput, error) {
+	interval := s.cfg.PollInterval
+
+	for {
+		execution, err := s.cli.DescribeExecution(ctx, &sfn.DescribeExecutionInput{ExecutionArn: executionARN}, optFns...)
+		if err != nil {
+			return nil, fmt.Errorf("cli.DescribeExecution: %w", err)
+		}
+
+		if execution.Status != sfntypes.ExecutionStatusRunning {
+			return execution, nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		interval = min(2*interval, s.cfg.MaxPollInterval)
+	}
+}
+
+// GetFunction resolves the function ARN to the state machine ARN.
+func (s *stepFunctionsInvoker) GetFunction(context.Context, *lambda.GetFunctionInput, ...func(*lambda.Options)) (*lambda.GetFunctionOutput, error) {
+	return &lambda.GetFunctionOutput{Configuration: &types.FunctionConfiguration{FunctionArn: pointer.To(s.stateMachineARN)}}, nil
+}
+
+// stepFunctionsOptions carries the per-call region, endpoint, HTTP client and credentials options over to sfn.
+func stepFunctionsOptions(optFns []func(*lambda.Options)) []func(*sfn.Options) {
+	if len(optFns) == 0 {
+		return nil
+	}
+
+	return []func(*sfn.Options){func(o *sfn.Options) {
+		lo := lambda.Options{
+			Region:          o.Region,
+			Credentials:     o.Credentials,
+			HTTPClient:      o.HTTPClient,
+			EndpointOptions: lambda.EndpointResolverOptions(o.EndpointOptions),
+			APIOptions:      o.APIOptions,
+		}
+		for _, fn := range optFns {
+			fn(&lo)
+		}
+
+		o.Region = lo.Region
+		o.Credentials = lo.Credentials
+		o.HTTPClient = lo.HTTPClient
+		o.EndpointOptions = sfn.EndpointResolverOptions(lo.EndpointOptions)
+		o.APIOptions = lo.APIOptions
+	}}
+}
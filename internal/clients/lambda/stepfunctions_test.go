@@ -0,0 +1,126 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testStateMachineARN = "arn:aws:states:eu-central-1:000000000000:stateMachine:orders"
+
+// fakeSFN runs every execution through the statuses, the last one is final.
+type fakeSFN struct {
+	statuses  []sfntypes.ExecutionStatus
+	output    string
+	errorType string
+	cause     string
+
+	mu         sync.Mutex
+	starts     []*sfn.StartExecutionInput
+	describes  int
+	optionsFns [][]func(*sfn.Options)
+}
+
+func (f *fakeSFN) StartExecution(_ context.Context, in *sfn.StartExecutionInput, optFns ...func(*sfn.Options)) (*sfn.StartExecutionOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.starts = append(f.starts, in)
+	f.optionsFns = append(f.optionsFns, optFns)
+
+	return &sfn.StartExecutionOutput{ExecutionArn: pointer.To("arn:aws:states:eu-central-1:000000000000:execution:orders:" + *in.Name)}, nil
+}
+
+func (f *fakeSFN) DescribeExecution(_ context.Context, in *sfn.DescribeExecutionInput, _ ...func(*sfn.Options)) (*sfn.DescribeExecutionOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	status := f.statuses[min(f.describes, len(f.statuses)-1)]
+	f.describes++
+
+	return &sfn.DescribeExecutionOutput{
+		ExecutionArn: in.ExecutionArn,
+		Status:       status,
+		Output:       pointer.ToStringOrNil(f.output),
+		Error:        pointer.ToStringOrNil(f.errorType),
+		Cause:        pointer.ToStringOrNil(f.cause),
+	}, nil
+}
+
+func TestNewStepFunctions(t *testing.T) {
+	api := &fakeSFN{
+		statuses: []sfntypes.ExecutionStatus{sfntypes.ExecutionStatusRunning, sfntypes.ExecutionStatusRunning, sfntypes.ExecutionStatusSucceeded},
+		output:   `{"statusCode":200,"body":"{\"id\":\"1\"}"}`,
+	}
+	cli := newStepFunctions(api, testStateMachineARN, StepFunctionsConfig{PollInterval: time.Millisecond}, WithQualifier("live"), WithRegion("eu-west-1"))
+
+	resp, err := cli.Do(_ctx, Request{HTTPMethod: "POST", Path: "/orders", Body: []byte(`{"id":"1"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"1"}`, resp.Body)
+	assert.Equal(t, 3, api.describes)
+
+	start := api.starts[0]
+	assert.Equal(t, testStateMachineARN+":live", *start.StateMachineArn)
+	assert.Contains(t, resp.RequestID, *start.Name, "the execution ARN")
+
+	var event events.APIGatewayProxyRequest
+	require.NoError(t, json.Unmarshal([]byte(*start.Input), &event))
+	assert.Equal(t, "/orders", event.Path)
+
+	var o sfn.Options
+	for _, fn := range api.optionsFns[0] {
+		fn(&o)
+	}
+	assert.Equal(t, "eu-west-1", o.Region)
+
+	arn, err := cli.FunctionARN(_ctx)
+	require.NoError(t, err)
+	assert.Equal(t, testStateMachineARN, arn)
+}
+
+func TestNewStepFunctions_Failed(t *testing.T) {
+	api := &fakeSFN{statuses: []sfntypes.ExecutionStatus{sfntypes.ExecutionStatusFailed}, errorType: "States.TaskFailed", cause: "payment declined"}
+	cli := newStepFunctions(api, testStateMachineARN, StepFunctionsConfig{})
+
+	_, err := cli.Invoke(_ctx, "POST", "/orders", nil)
+	var ie *InvocationError
+	require.ErrorAs(t, err, &ie)
+	assert.Equal(t, "States.TaskFailed", ie.Type)
+	assert.Equal(t, "payment declined", ie.Message)
+
+	api.statuses, api.errorType, api.cause = []sfntypes.ExecutionStatus{sfntypes.ExecutionStatusTimedOut}, "", ""
+	_, err = cli.Invoke(_ctx, "POST", "/orders", nil)
+	require.ErrorAs(t, err, &ie)
+	assert.Equal(t, "TIMED_OUT", ie.Type)
+}
+
+func TestNewStepFunctions_Async(t *testing.T) {
+	api := &fakeSFN{statuses: []sfntypes.ExecutionStatus{sfntypes.ExecutionStatusRunning}}
+	cli := newStepFunctions(api, testStateMachineARN, StepFunctionsConfig{})
+
+	require.NoError(t, cli.InvokeAsync(_ctx, "POST", "/orders", nil))
+	assert.Len(t, api.starts, 1)
+	assert.Zero(t, api.describes, "async invocations are not polled")
+
+	ctx, cancel := context.WithTimeout(_ctx, 20*time.Millisecond)
+	defer cancel()
+
+	_, err := newStepFunctions(api, testStateMachineARN, StepFunctionsConfig{PollInterval: time.Millisecond}).Invoke(ctx, "POST", "/orders", nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded, "running executions are polled until ctx is done")
+}
+
+func TestNewStepFunctions_Invalid(t *testing.T) {
+	_, err := NewStepFunctions(sfn.New(sfn.Options{}), "orders", StepFunctionsConfig{})
+	require.Error(t, err)
+
+	_, err = NewStepFunctions(sfn.New(sfn.Options{}), testFunctionARN, StepFunctionsConfig{})
+	require.Error(t, err, "a function ARN")
+}
@@ -0,0 +1,96 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const defaultConcurrency = 10
+
+// WithConcurrency sets the number of parallel invocations used by InvokeStreaming, 10 by default.
+// Non-positive n keeps the default.
+func WithConcurrency(n int) Option {
+	if n <= 0 {
+		n = defaultConcurrency
+	}
+
+	return func(c *client) {
+		c.concurrency = n
+	}
+}
+
+// InvokeStreaming synchronously invokes requests as they arrive on the channel and emits their results,
+// so that callers can pipeline arbitrary many invocations without keeping them in memory.
+// Results are emitted in completion order, Result.Index is the position of the Request on the input channel.
+// The returned channel is closed once in is closed and drained or ctx is done.
+func (c *client) InvokeStreaming(ctx context.Context, in <-chan Request) <-chan Result {
+	type job struct {
+		index int
+		req   Request
+	}
+
+	jobs := make(chan job)
+	out := make(chan Result)
+
+	go func() {
+		defer close(jobs)
+
+		index := 0
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case req, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case jobs <- job{index: index, req: req}:
+				}
+				index++
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range c.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- c.invokeRequest(ctx, j.index, j.req):
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+func (c *client) invokeRequest(ctx context.Context, index int, req Request) Result {
+	resp, err := c.do(ctx, &req)
+	if err != nil {
+		return Result{Index: index, Err: fmt.Errorf("invoke[sync]: %w", err)}
+	}
+
+	return Result{Index: index, Body: resp.Body}
+}
+
+func validateConcurrency(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("concurrency must be positive: %d", n)
+	}
+
+	return nil
+}
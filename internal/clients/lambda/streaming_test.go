@@ -0,0 +1,68 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestInvokeStreaming(t *testing.T) {
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return proxyOutput(in, http.StatusOK, proxyRequest(in).Path), nil
+	}}
+
+	cli := newClient(api, testFunctionARN, WithConcurrency(3))
+
+	const n = 50
+
+	in := make(chan Request)
+	go func() {
+		defer close(in)
+		for i := range n {
+			in <- Request{HTTPMethod: "GET", Path: fmt.Sprintf("/%d", i)}
+		}
+	}()
+
+	seen := make(map[int]bool, n)
+	for r := range cli.InvokeStreaming(_ctx, in) {
+		require.NoError(t, r.Err)
+		assert.Equal(t, fmt.Sprintf("/%d", r.Index), r.Body)
+		seen[r.Index] = true
+	}
+
+	assert.Len(t, seen, n)
+}
+
+func TestInvokeStreaming_ContextCancelled(t *testing.T) {
+	cli := newClient(&fakeAPI{}, testFunctionARN)
+
+	ctx, cancel := context.WithCancel(_ctx)
+	in := make(chan Request)
+
+	out := cli.InvokeStreaming(ctx, in)
+	cancel()
+
+	for range out {
+	}
+}
+
+func TestWithConcurrency_NonPositive(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		cli := newClient(&fakeAPI{}, testFunctionARN, WithConcurrency(n))
+		assert.Equal(t, defaultConcurrency, cli.concurrency)
+
+		in := make(chan Request, 1)
+		in <- Request{HTTPMethod: "GET", Path: "/"}
+		close(in)
+
+		var results []Result
+		for r := range cli.InvokeStreaming(_ctx, in) {
+			results = append(results, r)
+		}
+		assert.Len(t, results, 1)
+	}
+}
@@ -0,0 +1,60 @@
+package lambda
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+)
+
+// TemplateData is the data available to a Template as .Index and .Vars.
+type TemplateData struct {
+	// Index is the position of the request, e.g. in a load test.
+	Index int
+	// Vars are caller defined variables, e.g. set with CLI flags.
+	Vars map[string]string
+}
+
+// Template renders request bodies, so that parameterized invocations don't require external tooling:
+//
+//	{"id": "{{uuid}}", "seq": {{.Index}}, "tenant": {{json .Vars.tenant}}, "region": "{{env "AWS_REGION"}}"}
+type Template struct {
+	tmpl *template.Template
+}
+
+// ParseTemplate parses a text/template. Besides the builtins it provides the functions
+// env (environment variable), uuid (random version 4 UUID), now (RFC 3339 UTC time) and json (JSON encoding).
+// Missing variables fail rendering rather than rendering "<no value>".
+func ParseTemplate(text string) (*Template, error) {
+	rnd := newRandomRand()
+
+	tmpl, err := template.New("body").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"env":  os.Getenv,
+			"uuid": func() string { return randomUUID(rnd) },
+			"now":  func() string { return time.Now().UTC().Format(time.RFC3339) },
+			"json": func(v any) (string, error) {
+				b, err := json.Marshal(v)
+				return string(b), err
+			},
+		}).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("template.Parse: %w", err)
+	}
+
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Render executes the template with the data.
+func (t *Template) Render(data TemplateData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("tmpl.Execute: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,28 @@
+package lambda
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	t.Setenv("TEST_TEMPLATE_REGION", "eu-central-1")
+
+	tmpl, err := ParseTemplate(`{"id":"{{uuid}}","seq":{{.Index}},"tenant":{{json .Vars.tenant}},"region":"{{env "TEST_TEMPLATE_REGION"}}"}`)
+	require.NoError(t, err)
+
+	body, err := tmpl.Render(TemplateData{Index: 7, Vars: map[string]string{"tenant": `acme "inc"`}})
+	require.NoError(t, err)
+
+	assert.Regexp(t, regexp.MustCompile(
+		`^\{"id":"[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}","seq":7,"tenant":"acme \\"inc\\"","region":"eu-central-1"\}$`),
+		string(body))
+
+	_, err = tmpl.Render(TemplateData{})
+	require.Error(t, err, "missing variable")
+
+	_, err = ParseTemplate(`{{.Index`)
+	require.Error(t, err)
+}
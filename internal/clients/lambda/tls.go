@@ -0,0 +1,30 @@
+package lambda
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// NewCertPool returns the system roots with the PEM certificates of the CA bundle files appended,
+// use it as HTTPTransport.RootCAs for TLS-intercepting gateways or endpoints signed by internal CAs.
+func NewCertPool(caBundles ...string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, fmt.Errorf("x509.SystemCertPool: %w", err)
+	}
+
+	for _, path := range caBundles {
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("os.ReadFile: %w", err)
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates in CA bundle: " + path)
+		}
+	}
+
+	return pool, nil
+}
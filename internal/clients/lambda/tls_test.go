@@ -0,0 +1,51 @@
+package lambda
+
+import (
+	"encoding/pem"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithHTTPTransport_RootCAs(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"statusCode":200,"body":"ok"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	caBundle := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	cli := lambda.New(lambda.Options{
+		Region:       "eu-central-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(server.URL),
+	})
+
+	_, err := newClient(cli, testFunctionARN).Invoke(_ctx, "GET", "/", nil)
+	require.ErrorContains(t, err, "certificate", "signed by unknown authority")
+
+	pool, err := NewCertPool(caBundle)
+	require.NoError(t, err)
+
+	body, err := newClient(cli, testFunctionARN, WithHTTPTransport(HTTPTransport{RootCAs: pool})).Invoke(_ctx, "GET", "/", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", body)
+}
+
+func TestNewCertPool_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0o600))
+
+	_, err := NewCertPool(path)
+	require.Error(t, err)
+
+	_, err = NewCertPool(filepath.Join(t.TempDir(), "missing.pem"))
+	require.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package lambda
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"maps"
+)
+
+const tracerName = "lambda-invoker/internal/clients/lambda"
+
+// WithTracing starts an OpenTelemetry client span around every invocation and injects the trace context
+// into the proxy request headers, so that downstream functions can continue the trace.
+// Nil arguments default to the global TracerProvider and TextMapPropagator.
+// Tracing is installed as an interceptor, at its position among WithInterceptors options.
+func WithTracing(tp trace.TracerProvider, propagator propagation.TextMapPropagator) Option {
+	return func(c *client) {
+		if tp == nil {
+			tp = otel.GetTracerProvider()
+		}
+		if propagator == nil {
+			propagator = otel.GetTextMapPropagator()
+		}
+
+		c.interceptors = append(c.interceptors, c.tracingInterceptor(tp.Tracer(tracerName), propagator))
+	}
+}
+
+func (c *client) tracingInterceptor(tracer trace.Tracer, propagator propagation.TextMapPropagator) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+		ctx, span := tracer.Start(ctx, "lambda.invoke "+req.HTTPMethod,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("faas.invoked_provider", "aws"),
+				attribute.String("aws.lambda.invoked_arn", c.redact(c.functionARN)),
+				attribute.String("http.request.method", req.HTTPMethod),
+				attribute.String("url.path", req.Path),
+				attribute.Int("lambda.request.body.size", len(req.Body)),
+				attribute.Bool("lambda.async", req.Async),
+			))
+		defer span.End()
+
+		headers := make(map[string]string, len(req.Headers)+2)
+		maps.Copy(headers, req.Headers)
+		propagator.Inject(ctx, propagation.MapCarrier(headers))
+		req.Headers = headers
+
+		resp, err := next(ctx, req)
+
+		if resp != nil {
+			span.SetAttributes(
+				attribute.String("faas.invocation_id", resp.RequestID),
+				attribute.Int("http.response.status_code", resp.StatusCode),
+				attribute.Int("lambda.response.body.size", len(resp.Body)),
+			)
+			if resp.LogResult != "" {
+				span.SetAttributes(attribute.Bool("faas.coldstart", resp.ColdStart))
+			}
+		}
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		return resp, err
+	}
+}
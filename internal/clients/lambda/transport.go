@@ -0,0 +1,122 @@
+package lambda
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HTTPTransport tunes the HTTP client of Lambda API calls, zero fields keep the SDK defaults:
+// 100 idle connections, 10 per host, 90s idle timeout, 30s dial timeout and HTTP/2 if the endpoint supports it.
+// Raise MaxIdleConnsPerHost for high-throughput invokers, every connection above it is closed after use.
+type HTTPTransport struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits connections including active ones, unlimited by default.
+	MaxConnsPerHost       int
+	IdleConnTimeout       time.Duration
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	// DisableHTTP2 forces HTTP/1.1, each in-flight invocation then holds its own connection.
+	DisableHTTP2 bool
+	// Proxy replaces the proxy of HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	Proxy *ProxyConfig
+	// TLSConfig replaces the SDK TLS configuration, it is cloned. The SDK requires TLS 1.2 by default.
+	TLSConfig *tls.Config
+	// RootCAs verify the endpoint certificate instead of the system roots, see NewCertPool.
+	RootCAs *x509.CertPool
+}
+
+// WithHTTPTransport tunes the HTTP client of the *lambda.Client, it is built once from the first API call.
+// Clients sharing the option share the connection pool, e.g. in a Registry.
+func WithHTTPTransport(transport HTTPTransport) Option {
+	hc := &httpClient{transport: transport}
+
+	return func(c *client) {
+		c.httpClient = hc.apiOption
+	}
+}
+
+// WithHTTPClient replaces the HTTP client of the *lambda.Client for calls of the function.
+func WithHTTPClient(hc lambda.HTTPClient) Option {
+	return func(c *client) {
+		c.httpClient = func(o *lambda.Options) { o.HTTPClient = hc }
+	}
+}
+
+// httpClient lazily builds the tuned client from the HTTP client of the first API call.
+type httpClient struct {
+	transport HTTPTransport
+
+	once   sync.Once
+	client lambda.HTTPClient
+}
+
+func (h *httpClient) apiOption(o *lambda.Options) {
+	h.once.Do(func() {
+		base, ok := o.HTTPClient.(*awshttp.BuildableClient)
+		if !ok {
+			base = awshttp.NewBuildableClient()
+		}
+
+		h.client = base.
+			WithTransportOptions(h.transport.apply).
+			WithDialerOptions(h.transport.applyDialer)
+	})
+
+	o.HTTPClient = h.client
+}
+
+func (t HTTPTransport) apply(tr *http.Transport) {
+	if t.MaxIdleConns > 0 {
+		tr.MaxIdleConns = t.MaxIdleConns
+	}
+	if t.MaxIdleConnsPerHost > 0 {
+		tr.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+	}
+	if t.MaxConnsPerHost > 0 {
+		tr.MaxConnsPerHost = t.MaxConnsPerHost
+	}
+	if t.IdleConnTimeout > 0 {
+		tr.IdleConnTimeout = t.IdleConnTimeout
+	}
+	if t.TLSHandshakeTimeout > 0 {
+		tr.TLSHandshakeTimeout = t.TLSHandshakeTimeout
+	}
+	if t.ResponseHeaderTimeout > 0 {
+		tr.ResponseHeaderTimeout = t.ResponseHeaderTimeout
+	}
+	if t.Proxy != nil {
+		tr.Proxy = t.Proxy.proxyFunc()
+	}
+	if t.TLSConfig != nil {
+		tr.TLSClientConfig = t.TLSConfig.Clone()
+	}
+	if t.RootCAs != nil {
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		tr.TLSClientConfig.RootCAs = t.RootCAs
+	}
+	if t.DisableHTTP2 {
+		tr.ForceAttemptHTTP2 = false
+		// a non-nil empty map disables HTTP/2 of http.Transport
+		tr.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+}
+
+func (t HTTPTransport) applyDialer(d *net.Dialer) {
+	if t.DialTimeout > 0 {
+		d.Timeout = t.DialTimeout
+	}
+	if t.KeepAlive > 0 {
+		d.KeepAlive = t.KeepAlive
+	}
+}
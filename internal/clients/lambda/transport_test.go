@@ -0,0 +1,51 @@
+package lambda
+
+import (
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithHTTPTransport(t *testing.T) {
+	api := &fakeAPI{}
+	registry := newRegistry(api, WithHTTPTransport(HTTPTransport{
+		MaxIdleConnsPerHost: 256,
+		MaxConnsPerHost:     512,
+		IdleConnTimeout:     time.Minute,
+		DialTimeout:         2 * time.Second,
+		DisableHTTP2:        true,
+	}))
+	require.NoError(t, registry.Register("orders", "orders"))
+	require.NoError(t, registry.Register("payments", "payments"))
+
+	for _, name := range []string{"orders", "payments"} {
+		_, err := registry.Do(_ctx, name, Request{HTTPMethod: http.MethodGet, Path: "/"})
+		require.NoError(t, err)
+	}
+
+	hc, ok := api.options(0).HTTPClient.(*awshttp.BuildableClient)
+	require.True(t, ok)
+	assert.Same(t, hc, api.options(1).HTTPClient, "connection pool is shared")
+
+	tr := hc.GetTransport()
+	assert.Equal(t, 256, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, 512, tr.MaxConnsPerHost)
+	assert.Equal(t, awshttp.DefaultHTTPTransportMaxIdleConns, tr.MaxIdleConns, "SDK default")
+	assert.Equal(t, time.Minute, tr.IdleConnTimeout)
+	assert.False(t, tr.ForceAttemptHTTP2)
+	assert.NotNil(t, tr.TLSNextProto)
+	assert.Equal(t, 2*time.Second, hc.GetDialer().Timeout)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	api := &fakeAPI{}
+	hc := &http.Client{}
+
+	_, err := newClient(api, testFunctionARN, WithHTTPClient(hc)).Do(_ctx, Request{HTTPMethod: http.MethodGet, Path: "/"})
+	require.NoError(t, err)
+
+	assert.Same(t, hc, api.options(0).HTTPClient)
+}
@@ -0,0 +1,85 @@
+package lambda
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const maxVersionHistory = 100
+
+// VersionTransition records that invocations started to be handled by another function version,
+// From is empty for the first observed version.
+type VersionTransition struct {
+	From       string
+	To         string
+	RequestID  string
+	ObservedAt time.Time
+}
+
+// VersionTracker follows ExecutedVersion of sync invocations to tell when the upstream function
+// changed underneath the caller, install it with WithInterceptors(tracker.Interceptor).
+type VersionTracker struct {
+	onTransition func(VersionTransition)
+
+	mu      sync.Mutex
+	current string
+	history []VersionTransition
+}
+
+// NewVersionTracker returns a tracker calling onTransition, if not nil, on every observed version change.
+func NewVersionTracker(onTransition func(VersionTransition)) *VersionTracker {
+	return &VersionTracker{onTransition: onTransition}
+}
+
+// Interceptor observes the executed version of every successful sync invocation.
+func (t *VersionTracker) Interceptor(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+	resp, err := next(ctx, req)
+	if err == nil && resp != nil && resp.ExecutedVersion != "" {
+		t.observe(resp.ExecutedVersion, resp.RequestID)
+	}
+
+	return resp, err
+}
+
+// Current returns the most recently observed version.
+func (t *VersionTracker) Current() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.current
+}
+
+// History returns up to the last 100 transitions, oldest first.
+func (t *VersionTracker) History() []VersionTransition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]VersionTransition(nil), t.history...)
+}
+
+func (t *VersionTracker) observe(version, requestID string) {
+	t.mu.Lock()
+	if version == t.current {
+		t.mu.Unlock()
+		return
+	}
+
+	transition := VersionTransition{
+		From:       t.current,
+		To:         version,
+		RequestID:  requestID,
+		ObservedAt: time.Now(),
+	}
+
+	t.current = version
+	t.history = append(t.history, transition)
+	if len(t.history) > maxVersionHistory {
+		t.history = t.history[len(t.history)-maxVersionHistory:]
+	}
+	t.mu.Unlock()
+
+	if t.onTransition != nil {
+		t.onTransition(transition)
+	}
+}
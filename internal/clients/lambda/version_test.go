@@ -0,0 +1,45 @@
+package lambda
+
+import (
+	"context"
+	"github.com/AlekSi/pointer"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+func TestVersionTracker(t *testing.T) {
+	versions := []string{"1", "1", "2", "2", "1"}
+	call := 0
+
+	api := &fakeAPI{invoke: func(ctx context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		out := proxyOutput(in, http.StatusOK, "")
+		out.ExecutedVersion = pointer.To(versions[call])
+		call++
+		return out, nil
+	}}
+
+	var transitions []VersionTransition
+	tracker := NewVersionTracker(func(vt VersionTransition) {
+		transitions = append(transitions, vt)
+	})
+
+	cli := newClient(api, testFunctionARN, WithInterceptors(tracker.Interceptor))
+
+	for range versions {
+		_, err := cli.Invoke(_ctx, "GET", "/", nil)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, transitions, 3)
+	assert.Equal(t, "", transitions[0].From)
+	assert.Equal(t, "1", transitions[0].To)
+	assert.Equal(t, "1", transitions[1].From)
+	assert.Equal(t, "2", transitions[1].To)
+	assert.Equal(t, "1", transitions[2].To)
+
+	assert.Equal(t, "1", tracker.Current())
+	assert.Equal(t, transitions, tracker.History())
+}
@@ -0,0 +1,150 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// WarmerHeader marks warm-up pings, handlers should recognise it and return without doing any work.
+	WarmerHeader = "X-Lambda-Warmer"
+	// WarmerConcurrencyHeader carries the number of concurrent pings of a round,
+	// handlers may sleep briefly on pings so that every ping lands on its own instance.
+	WarmerConcurrencyHeader = "X-Lambda-Warmer-Concurrency"
+)
+
+// WarmerConfig configures a Warmer.
+type WarmerConfig struct {
+	// Interval between rounds, 5 minutes by default.
+	Interval time.Duration
+	// Concurrency is the number of instances kept warm, i.e. concurrent pings per round, 1 by default.
+	Concurrency int
+	// Timeout of a single round, 10 seconds by default.
+	Timeout time.Duration
+	// Ping is the warm-up request, "GET /" by default. WarmerHeader and WarmerConcurrencyHeader are always set.
+	Ping Request
+	// OnError is called with errors of failed rounds, if not nil.
+	OnError func(error)
+}
+
+// Warmer periodically pings the function to keep instances initialised for interactive paths.
+type Warmer struct {
+	client Client
+	cfg    WarmerConfig
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewWarmer returns a stopped warmer of the client.
+func NewWarmer(client Client, cfg WarmerConfig) *Warmer {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Minute
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Ping.HTTPMethod == "" {
+		cfg.Ping.HTTPMethod = "GET"
+	}
+	if cfg.Ping.Path == "" {
+		cfg.Ping.Path = "/"
+	}
+	cfg.Ping.Async = false
+	cfg.Ping.Headers = withHeader(cfg.Ping.Headers, WarmerHeader, "true")
+	cfg.Ping.Headers = withHeader(cfg.Ping.Headers, WarmerConcurrencyHeader, strconv.Itoa(cfg.Concurrency))
+
+	return &Warmer{client: client, cfg: cfg}
+}
+
+// Start runs a round immediately and then every interval on a background goroutine until Stop is called
+// or ctx is done. It returns an error if the warmer is already running.
+func (w *Warmer) Start(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel != nil {
+		return errors.New("warmer is already running")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	w.cancel, w.done = cancel, done
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(w.cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			if err := w.Warm(ctx); err != nil && ctx.Err() == nil && w.cfg.OnError != nil {
+				w.cfg.OnError(err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the background goroutine and waits for the current round to finish, it is a no-op if not running.
+// The warmer can be started again afterwards.
+func (w *Warmer) Stop() {
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	w.cancel, w.done = nil, nil
+	w.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+// Warm sends a single round of concurrent pings and returns the joined errors of failed pings.
+// Pings bypass WithSingleflight and WithCache, so that each of them reaches its own instance.
+func (w *Warmer) Warm(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithValue(ctx, warmPingKey{}, true), w.cfg.Timeout)
+	defer cancel()
+
+	errs := make([]error, w.cfg.Concurrency)
+
+	var wg sync.WaitGroup
+	for i := range w.cfg.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := w.client.Do(ctx, w.cfg.Ping); err != nil {
+				errs[i] = fmt.Errorf("ping[%d]: %w", i, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+type warmPingKey struct{}
+
+// isWarmPing reports that ctx is of a Warmer ping.
+func isWarmPing(ctx context.Context) bool {
+	ping, _ := ctx.Value(warmPingKey{}).(bool)
+	return ping
+}
@@ -0,0 +1,82 @@
+package lambda
+
+import (
+	"context"
+	"errors"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWarmer_Warm(t *testing.T) {
+	api := &fakeAPI{}
+	cli := newClient(api, testFunctionARN)
+
+	w := NewWarmer(cli, WarmerConfig{Concurrency: 3, Ping: Request{Path: "/ping"}})
+	require.NoError(t, w.Warm(_ctx))
+
+	calls := api.calls()
+	require.Len(t, calls, 3)
+	for _, in := range calls {
+		req := proxyRequest(in)
+		assert.Equal(t, "GET", req.HTTPMethod)
+		assert.Equal(t, "/ping", req.Path)
+		assert.Equal(t, "true", req.Headers[WarmerHeader])
+		assert.Equal(t, "3", req.Headers[WarmerConcurrencyHeader])
+	}
+}
+
+func TestWarmer_Warm_SingleflightCache(t *testing.T) {
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		// keeps pings in flight together, so that deduplication would collapse them
+		time.Sleep(20 * time.Millisecond)
+		return proxyOutput(in, http.StatusOK, "ok"), nil
+	}}
+	cli := newClient(api, testFunctionARN, WithSingleflight(Singleflight{}), WithCache(Cache{TTL: time.Minute}))
+
+	w := NewWarmer(cli, WarmerConfig{Concurrency: 3})
+	require.NoError(t, w.Warm(_ctx))
+	require.NoError(t, w.Warm(_ctx))
+
+	assert.Len(t, api.calls(), 6, "every ping reaches the function")
+}
+
+func TestWarmer_Warm_Error(t *testing.T) {
+	api := &fakeAPI{invoke: func(context.Context, *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		return nil, errors.New("connection reset")
+	}}
+	cli := newClient(api, testFunctionARN)
+
+	err := NewWarmer(cli, WarmerConfig{Concurrency: 2}).Warm(_ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ping[0]")
+	assert.Contains(t, err.Error(), "ping[1]")
+}
+
+func TestWarmer_StartStop(t *testing.T) {
+	var pings atomic.Int32
+	api := &fakeAPI{invoke: func(_ context.Context, in *lambda.InvokeInput) (*lambda.InvokeOutput, error) {
+		pings.Add(1)
+		return proxyOutput(in, 200, ""), nil
+	}}
+	cli := newClient(api, testFunctionARN)
+
+	w := NewWarmer(cli, WarmerConfig{Interval: 10 * time.Millisecond})
+	require.NoError(t, w.Start(_ctx))
+	require.Error(t, w.Start(_ctx), "already running")
+
+	require.Eventually(t, func() bool { return pings.Load() >= 3 }, time.Second, time.Millisecond)
+	w.Stop()
+
+	stopped := pings.Load()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, stopped, pings.Load())
+
+	w.Stop()
+	require.NoError(t, w.Start(_ctx), "restarts after stop")
+	w.Stop()
+}
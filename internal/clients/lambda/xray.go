@@ -0,0 +1,74 @@
+package lambda
+
+import (
+	"context"
+	"fmt"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"time"
+)
+
+// TraceHeader is the X-Ray trace header name.
+const TraceHeader = "X-Amzn-Trace-Id"
+
+// lambdaTraceIDKey is the context key aws-lambda-go stores the trace header of the running invocation with.
+const lambdaTraceIDKey = "x-amzn-trace-id"
+
+type traceHeaderKey struct{}
+
+// ContextWithTraceHeader returns a context carrying the X-Ray trace header, e.g. "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1".
+func ContextWithTraceHeader(ctx context.Context, header string) context.Context {
+	return context.WithValue(ctx, traceHeaderKey{}, header)
+}
+
+// TraceHeaderFromContext returns the trace header set by ContextWithTraceHeader,
+// or the one of the running invocation if the caller is a function started by aws-lambda-go.
+func TraceHeaderFromContext(ctx context.Context) (string, bool) {
+	if header, ok := ctx.Value(traceHeaderKey{}).(string); ok && header != "" {
+		return header, true
+	}
+
+	if header, ok := ctx.Value(lambdaTraceIDKey).(string); ok && header != "" {
+		return header, true
+	}
+
+	return "", false
+}
+
+// WithXRayPropagation propagates the X-Ray trace header from the context, or a newly generated one,
+// to the Invoke API request, so that Lambda continues the trace, and to the proxy request headers.
+// It is installed as an interceptor at its position among WithInterceptors options.
+func WithXRayPropagation() Option {
+	return func(c *client) {
+		c.xrayPropagation = true
+		c.interceptors = append(c.interceptors, c.xrayInterceptor)
+	}
+}
+
+func (c *client) xrayInterceptor(ctx context.Context, req *Request, next Invoker) (*Response, error) {
+	header, ok := TraceHeaderFromContext(ctx)
+	if !ok {
+		header = c.newTraceHeader()
+	}
+
+	req.Headers = withHeader(req.Headers, TraceHeader, header)
+
+	return next(ContextWithTraceHeader(ctx, header), req)
+}
+
+// newTraceHeader generates a root trace ID, sampling is left to the function.
+func (c *client) newTraceHeader() string {
+	return fmt.Sprintf("Root=1-%08x-%08x%016x", time.Now().Unix(), uint32(c.rand.Uint64()), c.rand.Uint64())
+}
+
+// traceHeaderOption adds the trace header of ctx to the Invoke API request.
+func traceHeaderOption(ctx context.Context) (func(*lambda.Options), bool) {
+	header, ok := TraceHeaderFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	return func(o *lambda.Options) {
+		o.APIOptions = append(o.APIOptions, smithyhttp.SetHeaderValue(TraceHeader, header))
+	}, true
+}
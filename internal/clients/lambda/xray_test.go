@@ -0,0 +1,49 @@
+package lambda
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestWithXRayPropagation(t *testing.T) {
+	const header = "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *regexp.Regexp
+	}{
+		{
+			name: "explicit",
+			ctx:  ContextWithTraceHeader(_ctx, header),
+			want: regexp.MustCompile("^" + regexp.QuoteMeta(header) + "$"),
+		},
+		{
+			name: "running function",
+			//nolint:staticcheck // aws-lambda-go uses a string key
+			ctx:  context.WithValue(_ctx, lambdaTraceIDKey, header),
+			want: regexp.MustCompile("^" + regexp.QuoteMeta(header) + "$"),
+		},
+		{
+			name: "generated",
+			ctx:  _ctx,
+			want: regexp.MustCompile(`^Root=1-[0-9a-f]{8}-[0-9a-f]{24}$`),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &fakeAPI{}
+			cli := newClient(api, testFunctionARN, WithXRayPropagation())
+
+			_, err := cli.Invoke(tt.ctx, "GET", "/", nil)
+			require.NoError(t, err)
+
+			assert.Regexp(t, tt.want, proxyRequest(api.calls()[0]).Headers[TraceHeader])
+			assert.Len(t, api.options(0).APIOptions, 1)
+		})
+	}
+}